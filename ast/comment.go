@@ -0,0 +1,99 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/flily/magi-c/context"
+)
+
+type Comment struct {
+	TerminalNodeBase
+	Block bool
+	Lines []string
+}
+
+func NewComment(ctx *context.Context, block bool, lines []string) *Comment {
+	c := &Comment{
+		TerminalNodeBase: NewTerminalNodeBase(ctx),
+		Block:            block,
+		Lines:            lines,
+	}
+
+	return c
+}
+
+func ASTBuildLineComment(content string) *Comment {
+	return NewComment(nil, false, []string{content})
+}
+
+func ASTBuildBlockComment(lines ...string) *Comment {
+	return NewComment(nil, true, lines)
+}
+
+func (c *Comment) Type() TokenType {
+	return CommentStart
+}
+
+func (c *Comment) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+func (c *Comment) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(c, other)
+	if err != nil {
+		return err
+	}
+
+	if c.Block != o.Block {
+		return c.Context().Error("wrong comment style, expect block=%v, got block=%v", o.Block, c.Block)
+	}
+
+	if c.Text() != o.Text() {
+		return c.Context().Error("wrong comment content, expect '%s', got '%s'", o.Text(), c.Text())
+	}
+
+	return nil
+}
+
+// Commentable is implemented by nodes which can carry comments, comments are trivia in syntax and attached
+// to the nearest node, leading comments are in lines before the node, and trailing comments are in the same
+// line after the node.
+type Commentable interface {
+	AddLeadingComments(comments ...*Comment)
+	AddTrailingComments(comments ...*Comment)
+	LeadingComments() []*Comment
+	TrailingComments() []*Comment
+}
+
+type CommentContainer struct {
+	leading  []*Comment
+	trailing []*Comment
+}
+
+func (c *CommentContainer) AddLeadingComments(comments ...*Comment) {
+	c.leading = append(c.leading, comments...)
+}
+
+func (c *CommentContainer) AddTrailingComments(comments ...*Comment) {
+	c.trailing = append(c.trailing, comments...)
+}
+
+func (c *CommentContainer) LeadingComments() []*Comment {
+	return c.leading
+}
+
+func (c *CommentContainer) TrailingComments() []*Comment {
+	return c.trailing
+}
+
+// LiftComments moves leading comments of the first token and trailing comments of the last token onto a
+// non-terminal node.
+func LiftComments(node Commentable, first Commentable, last Commentable) {
+	if first != nil && first != node {
+		node.AddLeadingComments(first.LeadingComments()...)
+	}
+
+	if last != nil && last != node {
+		node.AddTrailingComments(last.TrailingComments()...)
+	}
+}
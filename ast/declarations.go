@@ -4,9 +4,25 @@ import (
 	"github.com/flily/magi-c/context"
 )
 
+// WithKeyword is a contextual keyword, which declares global variables used by function, like 'with(g int)'.
+const WithKeyword = "with"
+
+// FunctionDeclaration declares a function, an instance method with a receiver like 'fun (p *Point) Distance()',
+// or a type method like 'fun Point.New()'. Global variables used are declared in 'with(...)' after arguments, or
+// in brackets before name, like 'fun [g int]f()'.
 type FunctionDeclaration struct {
 	NonTerminalNode
+	Export            *TerminalToken
 	Keyword           *TerminalToken
+	With              *Identifier
+	LGlobals          *TerminalToken
+	Globals           *ArgumentList
+	RGlobals          *TerminalToken
+	LParenReceiver    *TerminalToken
+	Receiver          *ArgumentDeclaration
+	RParenReceiver    *TerminalToken
+	TypeName          *Identifier
+	Dot               *TerminalToken
 	Name              *Identifier
 	LParenArgs        *TerminalToken
 	Arguments         *ArgumentList
@@ -47,14 +63,97 @@ func ASTBuildFunction(name string, args *ArgumentList, returnTypes *TypeList, st
 	return funcDecl
 }
 
+// ASTBuildMethod builds an instance method with receiver, receiver type is like 'Point' or '*Point'.
+func ASTBuildMethod(receiver string, receiverType string, name string, args *ArgumentList, returnTypes *TypeList, statements []Statement) *FunctionDeclaration {
+	f := ASTBuildFunction(name, args, returnTypes, statements)
+	f.LParenReceiver = NewTerminalToken(nil, LeftParen)
+	f.Receiver = ASTBuildArgumentWithoutComma(receiver, receiverType)
+	f.RParenReceiver = NewTerminalToken(nil, RightParen)
+
+	return f
+}
+
+// ASTBuildFunctionWithGlobals builds a function declares global variables used in 'with(...)'.
+func ASTBuildFunctionWithGlobals(name string, globals *ArgumentList, args *ArgumentList, returnTypes *TypeList, statements []Statement) *FunctionDeclaration {
+	f := ASTBuildFunction(name, args, returnTypes, statements)
+	f.With = ASTBuildIdentifier(WithKeyword)
+	f.LGlobals = NewTerminalToken(nil, LeftParen)
+	f.Globals = globals
+	f.RGlobals = NewTerminalToken(nil, RightParen)
+
+	return f
+}
+
+func ASTBuildTypeMethod(typeName string, name string, args *ArgumentList, returnTypes *TypeList, statements []Statement) *FunctionDeclaration {
+	f := ASTBuildFunction(name, args, returnTypes, statements)
+	f.TypeName = ASTBuildIdentifier(typeName)
+	f.Dot = NewTerminalToken(nil, Period)
+
+	return f
+}
+
 func (f *FunctionDeclaration) declarationNode() {}
 
+func (f *FunctionDeclaration) IsExported() bool {
+	return f.Export != nil
+}
+
+// GlobalAccesses returns global variables declared in function signature, in either 'with(...)' or brackets.
+func (f *FunctionDeclaration) GlobalAccesses() []*ArgumentDeclaration {
+	if f.Globals == nil {
+		return nil
+	}
+
+	return f.Globals.Arguments
+}
+
+// ReceiverType returns the type which method belongs to, for both instance methods and type methods.
+func (f *FunctionDeclaration) ReceiverType() (string, bool) {
+	if f.TypeName != nil {
+		return f.TypeName.Name, true
+	}
+
+	if f.Receiver != nil {
+		if t, ok := f.Receiver.Type.(*SimpleType); ok {
+			return t.Identifier.Name, true
+		}
+	}
+
+	return "", false
+}
+
+func (f *FunctionDeclaration) IsMethod() bool {
+	_, ok := f.ReceiverType()
+	return ok
+}
+
+// FullName returns name of function, or name of method prefixed with its type, like 'Point.Distance'.
+func (f *FunctionDeclaration) FullName() string {
+	if typeName, ok := f.ReceiverType(); ok {
+		return typeName + "." + f.Name.Name
+	}
+
+	return f.Name.Name
+}
+
 func (f *FunctionDeclaration) EqualTo(archor context.ContextProvider, other Comparable) error {
 	o, err := CheckNodeEqual(f, other)
 	if err != nil {
 		return err
 	}
 
+	if err := CheckNilPointerEqual(f, f.Export, o.Export); err != nil {
+		return err
+	}
+
+	if err := CheckNilPointerEqual(f, f.Receiver, o.Receiver); err != nil {
+		return err
+	}
+
+	if err := CheckNilPointerEqual(f, f.TypeName, o.TypeName); err != nil {
+		return err
+	}
+
 	if err := f.Name.EqualTo(f, o.Name); err != nil {
 		return err
 	}
@@ -63,6 +162,10 @@ func (f *FunctionDeclaration) EqualTo(archor context.ContextProvider, other Comp
 		return err
 	}
 
+	if err := CheckNilPointerEqual(f, f.Globals, o.Globals); err != nil {
+		return err
+	}
+
 	if err := CheckNilPointerEqual(f, f.ReturnTypes, o.ReturnTypes); err != nil {
 		return err
 	}
@@ -76,7 +179,17 @@ func (f *FunctionDeclaration) EqualTo(archor context.ContextProvider, other Comp
 
 func (f *FunctionDeclaration) Context() *context.Context {
 	ctx1 := context.JoinObjects(
+		f.Export,
 		f.Keyword,
+		f.With,
+		f.LGlobals,
+		f.Globals,
+		f.RGlobals,
+		f.LParenReceiver,
+		f.Receiver,
+		f.RParenReceiver,
+		f.TypeName,
+		f.Dot,
 		f.Name,
 		f.LParenArgs,
 		f.Arguments,
@@ -98,3 +211,170 @@ func (f *FunctionDeclaration) Context() *context.Context {
 	return context.Join(ctx1, ctx2, ctx3)
 
 }
+
+// TypeDeclaration declares a named type, in forms of 'struct Name { ... }', 'type Name struct { ... }' or
+// 'type Name OtherType'.
+type TypeDeclaration struct {
+	NonTerminalNode
+	Keyword    *TerminalToken
+	Name       *Identifier
+	Definition Type
+}
+
+func NewTypeDeclaration(keyword *TerminalToken, name *Identifier, definition Type) *TypeDeclaration {
+	d := &TypeDeclaration{
+		Keyword:    keyword,
+		Name:       name,
+		Definition: definition,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildTypeDeclaration(name string, definition Type) *TypeDeclaration {
+	return NewTypeDeclaration(ASTBuildKeyword(TypeDefine), ASTBuildIdentifier(name), definition)
+}
+
+func (d *TypeDeclaration) declarationNode() {}
+
+// StructType returns definition of the structure, or false if the type is not a structure.
+func (d *TypeDeclaration) StructType() (*StructType, bool) {
+	t, ok := d.Definition.(*StructType)
+	return t, ok
+}
+
+func (d *TypeDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Name.EqualTo(d, o.Name); err != nil {
+		return err
+	}
+
+	return d.Definition.EqualTo(d, o.Definition)
+}
+
+func (d *TypeDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Keyword, d.Name, d.Definition)
+}
+
+// GlobalDeclaration declares a global variable in document, or declares access to a global variable in function.
+type GlobalDeclaration struct {
+	NonTerminalNode
+	Export   *TerminalToken
+	Keyword  *TerminalToken
+	Variable *VariableDeclaration
+}
+
+func NewGlobalDeclaration(keyword *TerminalToken, variable *VariableDeclaration) *GlobalDeclaration {
+	d := &GlobalDeclaration{
+		Keyword:  keyword,
+		Variable: variable,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildGlobalDeclaration(name string, typ Type, value Expression) *GlobalDeclaration {
+	return NewGlobalDeclaration(ASTBuildKeyword(Global), ASTBuildVariableDeclaration(Var, name, typ, value))
+}
+
+func (d *GlobalDeclaration) declarationNode() {}
+func (d *GlobalDeclaration) statementNode()   {}
+
+func (d *GlobalDeclaration) IsExported() bool {
+	return d.Export != nil
+}
+
+func (d *GlobalDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckNilPointerEqual(d, d.Export, o.Export); err != nil {
+		return err
+	}
+
+	return d.Variable.EqualTo(d, o.Variable)
+}
+
+func (d *GlobalDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Export, d.Keyword, d.Variable)
+}
+
+// ModuleDeclaration declares name of module of the document, like 'module foo'.
+type ModuleDeclaration struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	Name    *Identifier
+}
+
+func NewModuleDeclaration(keyword *TerminalToken, name *Identifier) *ModuleDeclaration {
+	d := &ModuleDeclaration{
+		Keyword: keyword,
+		Name:    name,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildModuleDeclaration(name string) *ModuleDeclaration {
+	return NewModuleDeclaration(ASTBuildKeyword(Module), ASTBuildIdentifier(name))
+}
+
+func (d *ModuleDeclaration) declarationNode() {}
+
+func (d *ModuleDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	return d.Name.EqualTo(d, o.Name)
+}
+
+func (d *ModuleDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Keyword, d.Name)
+}
+
+// ImportDeclaration imports a module, like 'import foo', exported symbols are referenced as 'foo.bar'.
+type ImportDeclaration struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	Name    *Identifier
+}
+
+func NewImportDeclaration(keyword *TerminalToken, name *Identifier) *ImportDeclaration {
+	d := &ImportDeclaration{
+		Keyword: keyword,
+		Name:    name,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildImportDeclaration(name string) *ImportDeclaration {
+	return NewImportDeclaration(ASTBuildKeyword(Import), ASTBuildIdentifier(name))
+}
+
+func (d *ImportDeclaration) declarationNode() {}
+
+func (d *ImportDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	return d.Name.EqualTo(d, o.Name)
+}
+
+func (d *ImportDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Keyword, d.Name)
+}
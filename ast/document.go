@@ -6,11 +6,13 @@ import (
 
 type Declaration interface {
 	Node
+	Commentable
 	declarationNode()
 }
 
 type Statement interface {
 	Node
+	Commentable
 	statementNode()
 }
 
@@ -60,3 +62,40 @@ func (d *Document) Context() *context.Context {
 
 	return context.Join(ctxList...)
 }
+
+// ErrorNode takes place of a declaration or a statement which fails to parse, tokens skipped in error recovery are
+// kept.
+type ErrorNode struct {
+	NonTerminalNode
+	Tokens []TerminalNode
+}
+
+func NewErrorNode(tokens []TerminalNode) *ErrorNode {
+	n := &ErrorNode{
+		Tokens: tokens,
+	}
+	n.Init(n)
+
+	return n
+}
+
+func ASTBuildErrorNode() *ErrorNode {
+	return NewErrorNode(nil)
+}
+
+func (n *ErrorNode) declarationNode() {}
+func (n *ErrorNode) statementNode()   {}
+
+func (n *ErrorNode) EqualTo(_ context.ContextProvider, other Comparable) error {
+	_, err := CheckNodeEqual(n, other)
+	return err
+}
+
+func (n *ErrorNode) Context() *context.Context {
+	ctxList := make([]context.ContextProvider, 0, len(n.Tokens))
+	for _, token := range n.Tokens {
+		ctxList = append(ctxList, token)
+	}
+
+	return context.JoinObjects(ctxList...)
+}
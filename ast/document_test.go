@@ -136,3 +136,36 @@ func TestDocumentNotEqualOnType(t *testing.T) {
 		t.Fatalf("wrong error message:\nexpected:\n%s\ngot:\n%s", message, err.Error())
 	}
 }
+
+func TestErrorNode(t *testing.T) {
+	words := generateTestWords("var = 3")
+	tokens := []TerminalNode{
+		NewTerminalToken(words[0], Var),
+		NewTerminalToken(words[1], Assign),
+		NewTerminalToken(words[2], Integer),
+	}
+
+	node := NewErrorNode(tokens)
+	checkDeclarationNodeInterface(node)
+	checkStatementNodeInterface(node)
+
+	if err := node.EqualTo(nil, ASTBuildErrorNode()); err != nil {
+		t.Errorf("error nodes expected to be equal, got:\n%s", err)
+	}
+
+	if err := node.EqualTo(nil, ASTBuildIdentifier("a")); err == nil {
+		t.Errorf("error node expected to be different from identifier")
+	}
+
+	expected := strings.Join([]string{
+		"    1 | var = 3",
+		"      | ^^^ ^ ^",
+	}, "\n")
+	if got := node.Context().HighlightText(""); got != expected {
+		t.Errorf("wrong context of error node, expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if ctx := ASTBuildErrorNode().Context(); ctx != nil {
+		t.Errorf("context of empty error node expected to be nil")
+	}
+}
@@ -153,3 +153,591 @@ func (e *InfixExpression) EqualTo(_ context.ContextProvider, other Comparable) e
 func (e *InfixExpression) Context() *context.Context {
 	return context.JoinObjects(e.LeftOperand, e.Operator, e.RightOperand)
 }
+
+// PointerArithmeticExpression shifts a pointer by offset in form of 'p +>> 1' or 'p -<< 1', offset is in bytes
+// unless a unit type follows, like 'p +>> 1 uint32'.
+type PointerArithmeticExpression struct {
+	NonTerminalNode
+	Pointer  Expression
+	Operator *TerminalToken
+	Offset   Expression
+	Unit     *SimpleType
+}
+
+func NewPointerArithmeticExpression(pointer Expression, operator *TerminalToken, offset Expression, unit *SimpleType) *PointerArithmeticExpression {
+	e := &PointerArithmeticExpression{
+		Pointer:  pointer,
+		Operator: operator,
+		Offset:   offset,
+		Unit:     unit,
+	}
+	e.Init(e)
+
+	return e
+}
+
+// ASTBuildPointerArithmeticExpression builds a pointer arithmetic, offset is in bytes if unit is empty.
+func ASTBuildPointerArithmeticExpression(pointer Expression, operatorToken TokenType, offset Expression, unit string) *PointerArithmeticExpression {
+	var unitType *SimpleType
+	if len(unit) > 0 {
+		unitType = ASTBuildSimpleType(unit)
+	}
+
+	return NewPointerArithmeticExpression(pointer, ASTBuildSymbol(operatorToken), offset, unitType)
+}
+
+func (e *PointerArithmeticExpression) expressionNode() {}
+
+func (e *PointerArithmeticExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Pointer.EqualTo(e, o.Pointer); err != nil {
+		return err
+	}
+
+	if e.Operator.Token != o.Operator.Token {
+		return e.Operator.Context().Error("expect operator '%s', got '%s'", o.Operator.Token, e.Operator.Token).With("%s", o.Operator.Token)
+	}
+
+	if err := e.Offset.EqualTo(e, o.Offset); err != nil {
+		return err
+	}
+
+	if e.Unit == nil || o.Unit == nil {
+		if e.Unit != o.Unit {
+			return e.Context().Error("unit of pointer arithmetic mismatch, expect %T, got %T", o.Unit, e.Unit)
+		}
+
+		return nil
+	}
+
+	return e.Unit.EqualTo(e, o.Unit)
+}
+
+func (e *PointerArithmeticExpression) Context() *context.Context {
+	return context.JoinObjects(e.Pointer, e.Operator, e.Offset, e.Unit)
+}
+
+// IsSubtraction tells whether pointer is shifted backward by '-<<'.
+func (e *PointerArithmeticExpression) IsSubtraction() bool {
+	return e.Operator.Token == PointerSub
+}
+
+type PrefixExpression struct {
+	NonTerminalNode
+	Operator *TerminalToken
+	Operand  Expression
+}
+
+func NewPrefixExpression(operator *TerminalToken, operand Expression) *PrefixExpression {
+	expr := &PrefixExpression{
+		Operator: operator,
+		Operand:  operand,
+	}
+	expr.Init(expr)
+
+	return expr
+}
+
+func ASTBuildPrefixExpression(operatorToken TokenType, operand Expression) *PrefixExpression {
+	return NewPrefixExpression(ASTBuildSymbol(operatorToken), operand)
+}
+
+func (e *PrefixExpression) expressionNode() {}
+
+func (e *PrefixExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if e.Operator.Token != o.Operator.Token {
+		return e.Operator.Context().Error("expect operator '%s', got '%s'", o.Operator.Token, e.Operator.Token).With("%s", o.Operator.Token)
+	}
+
+	return e.Operand.EqualTo(e, o.Operand)
+}
+
+func (e *PrefixExpression) Context() *context.Context {
+	return context.JoinObjects(e.Operator, e.Operand)
+}
+
+type ParenthesizedExpression struct {
+	NonTerminalNode
+	LParen     *TerminalToken
+	Expression Expression
+	RParen     *TerminalToken
+}
+
+func NewParenthesizedExpression(lParen *TerminalToken, expr Expression, rParen *TerminalToken) *ParenthesizedExpression {
+	e := &ParenthesizedExpression{
+		LParen:     lParen,
+		Expression: expr,
+		RParen:     rParen,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildParenthesizedExpression(expr Expression) *ParenthesizedExpression {
+	return NewParenthesizedExpression(ASTBuildSymbol(LeftParen), expr, ASTBuildSymbol(RightParen))
+}
+
+func (e *ParenthesizedExpression) expressionNode() {}
+
+func (e *ParenthesizedExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	return e.Expression.EqualTo(e, o.Expression)
+}
+
+func (e *ParenthesizedExpression) Context() *context.Context {
+	return context.JoinObjects(e.LParen, e.Expression, e.RParen)
+}
+
+type CallExpression struct {
+	NonTerminalNode
+	Callee    Expression
+	LParen    *TerminalToken
+	Arguments *ExpressionList
+	RParen    *TerminalToken
+}
+
+func NewCallExpression(callee Expression, lParen *TerminalToken, args *ExpressionList, rParen *TerminalToken) *CallExpression {
+	e := &CallExpression{
+		Callee:    callee,
+		LParen:    lParen,
+		Arguments: args,
+		RParen:    rParen,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildCallExpression(callee Expression, args ...*ExpressionListItem) *CallExpression {
+	return NewCallExpression(callee, ASTBuildSymbol(LeftParen), ASTBuildExpressionList(args...), ASTBuildSymbol(RightParen))
+}
+
+func (e *CallExpression) expressionNode() {}
+
+func (e *CallExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Callee.EqualTo(e, o.Callee); err != nil {
+		return err
+	}
+
+	return e.Arguments.EqualTo(e, o.Arguments)
+}
+
+func (e *CallExpression) Context() *context.Context {
+	return context.JoinObjects(e.Callee, e.LParen, e.Arguments, e.RParen)
+}
+
+// FunctionName returns name of callee if it is an identifier.
+func (e *CallExpression) FunctionName() (string, bool) {
+	if id, ok := e.Callee.(*Identifier); ok {
+		return id.Name, true
+	}
+
+	return "", false
+}
+
+type MemberExpression struct {
+	NonTerminalNode
+	Object Expression
+	Period *TerminalToken
+	Member *Identifier
+}
+
+func NewMemberExpression(object Expression, period *TerminalToken, member *Identifier) *MemberExpression {
+	e := &MemberExpression{
+		Object: object,
+		Period: period,
+		Member: member,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildMemberExpression(object Expression, member string) *MemberExpression {
+	return NewMemberExpression(object, ASTBuildSymbol(Period), ASTBuildIdentifier(member))
+}
+
+func (e *MemberExpression) expressionNode() {}
+
+func (e *MemberExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Object.EqualTo(e, o.Object); err != nil {
+		return err
+	}
+
+	return e.Member.EqualTo(e, o.Member)
+}
+
+func (e *MemberExpression) Context() *context.Context {
+	return context.JoinObjects(e.Object, e.Period, e.Member)
+}
+
+type IndexExpression struct {
+	NonTerminalNode
+	Object   Expression
+	LBracket *TerminalToken
+	Index    Expression
+	RBracket *TerminalToken
+}
+
+func NewIndexExpression(object Expression, lBracket *TerminalToken, index Expression, rBracket *TerminalToken) *IndexExpression {
+	e := &IndexExpression{
+		Object:   object,
+		LBracket: lBracket,
+		Index:    index,
+		RBracket: rBracket,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildIndexExpression(object Expression, index Expression) *IndexExpression {
+	return NewIndexExpression(object, ASTBuildSymbol(LeftBracket), index, ASTBuildSymbol(RightBracket))
+}
+
+func (e *IndexExpression) expressionNode() {}
+
+func (e *IndexExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Object.EqualTo(e, o.Object); err != nil {
+		return err
+	}
+
+	return e.Index.EqualTo(e, o.Index)
+}
+
+func (e *IndexExpression) Context() *context.Context {
+	return context.JoinObjects(e.Object, e.LBracket, e.Index, e.RBracket)
+}
+
+// IsAssignable checks if an expression can be the target of an assignment.
+func IsAssignable(expr Expression) bool {
+	switch e := expr.(type) {
+	case *Identifier, *MemberExpression, *IndexExpression:
+		return true
+
+	case *PrefixExpression:
+		return e.Operator.Token == Asterisk
+
+	case *ParenthesizedExpression:
+		return IsAssignable(e.Expression)
+	}
+
+	return false
+}
+
+// RootIdentifier returns the variable which an assignable expression is based on, pointer dereferences are not
+// followed.
+func RootIdentifier(expr Expression) *Identifier {
+	switch e := expr.(type) {
+	case *Identifier:
+		return e
+
+	case *MemberExpression:
+		return RootIdentifier(e.Object)
+
+	case *IndexExpression:
+		return RootIdentifier(e.Object)
+
+	case *ParenthesizedExpression:
+		return RootIdentifier(e.Expression)
+	}
+
+	return nil
+}
+
+// ArrayLiteral is a fixed size array in form of 'int[4]{1, 2, 3}', elements not given are zero.
+type ArrayLiteral struct {
+	NonTerminalNode
+	Type     *ArrayType
+	LBrace   *TerminalToken
+	Elements *ExpressionList
+	RBrace   *TerminalToken
+}
+
+func NewArrayLiteral(t *ArrayType, lBrace *TerminalToken, elements *ExpressionList, rBrace *TerminalToken) *ArrayLiteral {
+	e := &ArrayLiteral{
+		Type:     t,
+		LBrace:   lBrace,
+		Elements: elements,
+		RBrace:   rBrace,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildArrayLiteral(t *ArrayType, elements ...*ExpressionListItem) *ArrayLiteral {
+	return NewArrayLiteral(t, ASTBuildSymbol(LeftBrace), ASTBuildExpressionList(elements...), ASTBuildSymbol(RightBrace))
+}
+
+func (e *ArrayLiteral) expressionNode() {}
+
+func (e *ArrayLiteral) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Type.EqualTo(e, o.Type); err != nil {
+		return err
+	}
+
+	return e.Elements.EqualTo(e, o.Elements)
+}
+
+func (e *ArrayLiteral) Context() *context.Context {
+	return context.JoinObjects(e.Type, e.LBrace, e.Elements, e.RBrace)
+}
+
+// FieldValue initializes a field in struct literal, in form of 'x: 1'.
+type FieldValue struct {
+	NonTerminalNode
+	Name  *Identifier
+	Colon *TerminalToken
+	Value Expression
+	Comma *TerminalToken
+}
+
+func NewFieldValue(name *Identifier, colon *TerminalToken, value Expression, comma *TerminalToken) *FieldValue {
+	f := &FieldValue{
+		Name:  name,
+		Colon: colon,
+		Value: value,
+		Comma: comma,
+	}
+	f.Init(f)
+
+	return f
+}
+
+func ASTBuildFieldValueWithComma(name string, value Expression) *FieldValue {
+	return NewFieldValue(ASTBuildIdentifier(name), ASTBuildSymbol(Colon), value, ASTBuildSymbol(Comma))
+}
+
+func ASTBuildFieldValueWithoutComma(name string, value Expression) *FieldValue {
+	return NewFieldValue(ASTBuildIdentifier(name), ASTBuildSymbol(Colon), value, nil)
+}
+
+func (f *FieldValue) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(f, other)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Name.EqualTo(f, o.Name); err != nil {
+		return err
+	}
+
+	if err := f.Value.EqualTo(f, o.Value); err != nil {
+		return err
+	}
+
+	return CheckNilPointerEqual(f, f.Comma, o.Comma)
+}
+
+func (f *FieldValue) Context() *context.Context {
+	return context.JoinObjects(f.Name, f.Colon, f.Value, f.Comma)
+}
+
+// StructLiteral is a value of structure with named fields, like 'Point{x: 1, y: 2}'.
+type StructLiteral struct {
+	NonTerminalNode
+	Type   *SimpleType
+	LBrace *TerminalToken
+	Fields []*FieldValue
+	RBrace *TerminalToken
+}
+
+func NewStructLiteral(t *SimpleType, lBrace *TerminalToken, fields []*FieldValue, rBrace *TerminalToken) *StructLiteral {
+	e := &StructLiteral{
+		Type:   t,
+		LBrace: lBrace,
+		Fields: fields,
+		RBrace: rBrace,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildStructLiteral(t string, fields ...*FieldValue) *StructLiteral {
+	return NewStructLiteral(ASTBuildSimpleType(t), ASTBuildSymbol(LeftBrace), fields, ASTBuildSymbol(RightBrace))
+}
+
+func (e *StructLiteral) expressionNode() {}
+
+func (e *StructLiteral) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Type.EqualTo(e, o.Type); err != nil {
+		return err
+	}
+
+	return CheckArrayEqual("FIELD VALUES", e, e.Fields, o.Fields)
+}
+
+func (e *StructLiteral) Context() *context.Context {
+	ctxs := make([]context.ContextProvider, 0, len(e.Fields)+3)
+	ctxs = append(ctxs, e.Type, e.LBrace)
+	for _, field := range e.Fields {
+		ctxs = append(ctxs, field)
+	}
+
+	ctxs = append(ctxs, e.RBrace)
+	return context.JoinObjects(ctxs...)
+}
+
+// NewExpression allocates a value in heap. The value is a list literal like 'new int[]{1, 2, 3}', a struct literal
+// like 'new Point{x: 1}', or a type with optional initial value like 'new int32(5)' and 'new Node()'.
+type NewExpression struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	Value   Expression
+}
+
+func NewNewExpression(keyword *TerminalToken, value Expression) *NewExpression {
+	e := &NewExpression{
+		Keyword: keyword,
+		Value:   value,
+	}
+	e.Init(e)
+
+	return e
+}
+
+func ASTBuildNewExpression(value Expression) *NewExpression {
+	return NewNewExpression(ASTBuildKeyword(New), value)
+}
+
+func (e *NewExpression) expressionNode() {}
+
+func (e *NewExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	return e.Value.EqualTo(e, o.Value)
+}
+
+func (e *NewExpression) Context() *context.Context {
+	return context.JoinObjects(e.Keyword, e.Value)
+}
+
+// ListLiteral returns the array literal allocated as a list.
+func (e *NewExpression) ListLiteral() (*ArrayLiteral, bool) {
+	l, ok := e.Value.(*ArrayLiteral)
+	return l, ok
+}
+
+// StructLiteral returns the struct literal allocated.
+func (e *NewExpression) StructLiteral() (*StructLiteral, bool) {
+	l, ok := e.Value.(*StructLiteral)
+	return l, ok
+}
+
+// Constructor returns the call of type in form of 'new T(...)', whose callee is name of type.
+func (e *NewExpression) Constructor() (*CallExpression, bool) {
+	call, ok := e.Value.(*CallExpression)
+	return call, ok
+}
+
+// SizeofExpression gets size of a type or an expression, like 'sizeof(Header)' and 'sizeof(buf[0])'. Operand in
+// form of a type is parsed as Type, even if it may name a variable, like 'sizeof(p)', which is resolved by checker.
+type SizeofExpression struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	LParen  *TerminalToken
+	Type    Type
+	Value   Expression
+	RParen  *TerminalToken
+}
+
+func NewSizeofExpression(keyword *TerminalToken, lParen *TerminalToken, operand Node, rParen *TerminalToken) *SizeofExpression {
+	e := &SizeofExpression{
+		Keyword: keyword,
+		LParen:  lParen,
+		RParen:  rParen,
+	}
+
+	switch o := operand.(type) {
+	case Type:
+		e.Type = o
+
+	case Expression:
+		e.Value = o
+	}
+
+	e.Init(e)
+
+	return e
+}
+
+// ASTBuildSizeofExpression builds sizeof of a type or an expression.
+func ASTBuildSizeofExpression(operand Node) *SizeofExpression {
+	return NewSizeofExpression(ASTBuildKeyword(Sizeof), ASTBuildSymbol(LeftParen), operand, ASTBuildSymbol(RightParen))
+}
+
+func (e *SizeofExpression) expressionNode() {}
+
+func (e *SizeofExpression) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(e, other)
+	if err != nil {
+		return err
+	}
+
+	if e.Type != nil && o.Type != nil {
+		return e.Type.EqualTo(e, o.Type)
+	}
+
+	if e.Value != nil && o.Value != nil {
+		return e.Value.EqualTo(e, o.Value)
+	}
+
+	return e.Context().Error("operand of sizeof mismatch, expect %T, got %T", o.Operand(), e.Operand())
+}
+
+func (e *SizeofExpression) Context() *context.Context {
+	return context.JoinObjects(e.Keyword, e.LParen, e.Operand(), e.RParen)
+}
+
+// Operand returns type or expression whose size is got.
+func (e *SizeofExpression) Operand() Node {
+	if e.Type != nil {
+		return e.Type
+	}
+
+	return e.Value
+}
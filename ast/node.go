@@ -17,6 +17,7 @@ type Node interface {
 }
 
 type ContextContainer struct {
+	CommentContainer
 	context *context.Context
 }
 
@@ -31,6 +32,7 @@ func (n *ContextContainer) HighlightText(message string, args ...any) string {
 
 type TerminalNode interface {
 	Node
+	Commentable
 	Type() TokenType
 }
 
@@ -49,6 +51,7 @@ func NewTerminalNodeBase(ctx *context.Context) TerminalNodeBase {
 }
 
 type NonTerminalNode struct {
+	CommentContainer
 	provider context.ContextProvider
 }
 
@@ -148,3 +151,16 @@ func CheckArrayEqual[T Comparable](message string, archor context.ContextProvide
 
 	return nil
 }
+
+// checkOptionalNodeEqual compares optional nodes stored in interface fields, which are nil if absent.
+func checkOptionalNodeEqual(archor context.ContextProvider, a Comparable, b Comparable) error {
+	if a == nil || b == nil {
+		if a != b {
+			return archor.Context().Error("optional node mismatch, expect %T, got %T", b, a)
+		}
+
+		return nil
+	}
+
+	return a.EqualTo(archor, b)
+}
@@ -41,3 +41,762 @@ func (r *ReturnStatement) EqualTo(_ context.ContextProvider, other Comparable) e
 func (r *ReturnStatement) Context() *context.Context {
 	return context.JoinObjects(r.Return, r.Value)
 }
+
+type ExpressionStatement struct {
+	NonTerminalNode
+	Expression Expression
+}
+
+func NewExpressionStatement(expr Expression) *ExpressionStatement {
+	s := &ExpressionStatement{
+		Expression: expr,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildExpressionStatement(expr Expression) *ExpressionStatement {
+	return NewExpressionStatement(expr)
+}
+
+func (s *ExpressionStatement) statementNode() {}
+
+func (s *ExpressionStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	return s.Expression.EqualTo(s, o.Expression)
+}
+
+func (s *ExpressionStatement) Context() *context.Context {
+	return s.Expression.Context()
+}
+
+// VariableDeclaration declares a variable by 'var' or 'const', and a pointer declared with 'ref' after the keyword
+// is a reference without ownership, like 'var ref p *int = &n'. 'ref' can also be the keyword itself.
+type VariableDeclaration struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	Ref     *TerminalToken
+	Name    *Identifier
+	Type    Type
+	Assign  *TerminalToken
+	Value   Expression
+}
+
+func NewVariableDeclaration(keyword *TerminalToken, name *Identifier) *VariableDeclaration {
+	d := &VariableDeclaration{
+		Keyword: keyword,
+		Name:    name,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildVariableDeclaration(keyword TokenType, name string, typ Type, value Expression) *VariableDeclaration {
+	d := NewVariableDeclaration(ASTBuildKeyword(keyword), ASTBuildIdentifier(name))
+	d.Type = typ
+	d.Value = value
+	if value != nil {
+		d.Assign = ASTBuildSymbol(Assign)
+	}
+
+	return d
+}
+
+// ASTBuildReferenceDeclaration builds a declaration of reference, in form of 'var ref p *T = v'.
+func ASTBuildReferenceDeclaration(keyword TokenType, name string, typ Type, value Expression) *VariableDeclaration {
+	d := ASTBuildVariableDeclaration(keyword, name, typ, value)
+	if keyword != Ref {
+		d.Ref = ASTBuildKeyword(Ref)
+	}
+
+	return d
+}
+
+func (d *VariableDeclaration) statementNode() {}
+
+func (d *VariableDeclaration) IsConst() bool {
+	return d.Keyword.Token == Const
+}
+
+// IsRef checks if the variable is a reference, which does not own the value pointed to.
+func (d *VariableDeclaration) IsRef() bool {
+	return d.Ref != nil || d.Keyword.Token == Ref
+}
+
+// IsAuto checks if type of the variable is inferred from its value.
+func (d *VariableDeclaration) IsAuto() bool {
+	if d.Type == nil {
+		return true
+	}
+
+	_, ok := d.Type.(*AutoType)
+	return ok
+}
+
+func (d *VariableDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Keyword.EqualTo(d, o.Keyword); err != nil {
+		return err
+	}
+
+	if err := CheckNilPointerEqual(d, d.Ref, o.Ref); err != nil {
+		return err
+	}
+
+	if err := d.Name.EqualTo(d, o.Name); err != nil {
+		return err
+	}
+
+	if d.Type == nil || o.Type == nil {
+		if d.Type != o.Type {
+			return d.Name.Context().Error("variable type mismatch, expect %T, got %T", o.Type, d.Type)
+		}
+
+	} else if err := d.Type.EqualTo(d, o.Type); err != nil {
+		return err
+	}
+
+	if d.Value == nil || o.Value == nil {
+		if d.Value != o.Value {
+			return d.Name.Context().Error("variable value mismatch, expect %T, got %T", o.Value, d.Value)
+		}
+
+		return nil
+	}
+
+	return d.Value.EqualTo(d, o.Value)
+}
+
+func (d *VariableDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Keyword, d.Ref, d.Name, d.Type, d.Assign, d.Value)
+}
+
+// InferenceDeclaration declares variables in types of values, variables declared by 'ref a := p' are references.
+type InferenceDeclaration struct {
+	NonTerminalNode
+	Ref    *TerminalToken
+	Names  *ExpressionList
+	Assign *TerminalToken
+	Values *ExpressionList
+}
+
+func NewInferenceDeclaration(names *ExpressionList, assign *TerminalToken, values *ExpressionList) *InferenceDeclaration {
+	d := &InferenceDeclaration{
+		Names:  names,
+		Assign: assign,
+		Values: values,
+	}
+	d.Init(d)
+
+	return d
+}
+
+func ASTBuildInferenceDeclaration(names []string, values ...Expression) *InferenceDeclaration {
+	nameList := NewExpressionList()
+	for i, name := range names {
+		var comma *TerminalToken
+		if i < len(names)-1 {
+			comma = ASTBuildSymbol(Comma)
+		}
+		nameList.Add(ASTBuildIdentifier(name), comma)
+	}
+
+	valueList := NewExpressionList()
+	for i, value := range values {
+		var comma *TerminalToken
+		if i < len(values)-1 {
+			comma = ASTBuildSymbol(Comma)
+		}
+		valueList.Add(value, comma)
+	}
+
+	return NewInferenceDeclaration(nameList, ASTBuildSymbol(InferenceAssign), valueList)
+}
+
+// ASTBuildReferenceInference builds an inference declaration of references, in form of 'ref a := p'.
+func ASTBuildReferenceInference(names []string, values ...Expression) *InferenceDeclaration {
+	d := ASTBuildInferenceDeclaration(names, values...)
+	d.Ref = ASTBuildKeyword(Ref)
+	return d
+}
+
+func (d *InferenceDeclaration) statementNode() {}
+
+// IsRef checks if the variables are references, which do not own values pointed to.
+func (d *InferenceDeclaration) IsRef() bool {
+	return d.Ref != nil
+}
+
+// Identifiers returns declared names, names are guaranteed to be identifiers by parser.
+func (d *InferenceDeclaration) Identifiers() []*Identifier {
+	result := make([]*Identifier, 0, d.Names.Length())
+	for _, item := range d.Names.Expressions {
+		result = append(result, item.Expression.(*Identifier))
+	}
+
+	return result
+}
+
+func (d *InferenceDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(d, other)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckNilPointerEqual(d, d.Ref, o.Ref); err != nil {
+		return err
+	}
+
+	if err := d.Names.EqualTo(d, o.Names); err != nil {
+		return err
+	}
+
+	return d.Values.EqualTo(d, o.Values)
+}
+
+func (d *InferenceDeclaration) Context() *context.Context {
+	return context.JoinObjects(d.Ref, d.Names, d.Assign, d.Values)
+}
+
+type AssignmentStatement struct {
+	NonTerminalNode
+	Targets  *ExpressionList
+	Operator *TerminalToken
+	Values   *ExpressionList
+}
+
+func NewAssignmentStatement(targets *ExpressionList, operator *TerminalToken, values *ExpressionList) *AssignmentStatement {
+	s := &AssignmentStatement{
+		Targets:  targets,
+		Operator: operator,
+		Values:   values,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildAssignmentStatement(target Expression, operator TokenType, value Expression) *AssignmentStatement {
+	return NewAssignmentStatement(
+		ASTBuildExpressionList(ASTBuildExpressionListItemWithoutComma(target)),
+		ASTBuildSymbol(operator),
+		ASTBuildExpressionList(ASTBuildExpressionListItemWithoutComma(value)),
+	)
+}
+
+func (s *AssignmentStatement) statementNode() {}
+
+// IsCompound checks if the operator is a compound assignment like '+='.
+func (s *AssignmentStatement) IsCompound() bool {
+	return s.Operator.Token != Assign
+}
+
+func (s *AssignmentStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Targets.EqualTo(s, o.Targets); err != nil {
+		return err
+	}
+
+	if err := s.Operator.EqualTo(s, o.Operator); err != nil {
+		return err
+	}
+
+	return s.Values.EqualTo(s, o.Values)
+}
+
+func (s *AssignmentStatement) Context() *context.Context {
+	return context.JoinObjects(s.Targets, s.Operator, s.Values)
+}
+
+type BlockStatement struct {
+	NonTerminalNode
+	LBrace     *TerminalToken
+	Statements []Statement
+	RBrace     *TerminalToken
+}
+
+func NewBlockStatement(lBrace *TerminalToken, statements []Statement, rBrace *TerminalToken) *BlockStatement {
+	s := &BlockStatement{
+		LBrace:     lBrace,
+		Statements: statements,
+		RBrace:     rBrace,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildBlockStatement(statements ...Statement) *BlockStatement {
+	return NewBlockStatement(ASTBuildSymbol(LeftBrace), statements, ASTBuildSymbol(RightBrace))
+}
+
+func (s *BlockStatement) statementNode() {}
+
+func (s *BlockStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	return CheckArrayEqual("STATEMENT LIST", s, s.Statements, o.Statements)
+}
+
+func (s *BlockStatement) Context() *context.Context {
+	ctxList := make([]context.ContextProvider, 0, len(s.Statements)+2)
+	ctxList = append(ctxList, s.LBrace)
+	for _, stmt := range s.Statements {
+		ctxList = append(ctxList, stmt)
+	}
+	ctxList = append(ctxList, s.RBrace)
+
+	return context.JoinObjects(ctxList...)
+}
+
+// ConditionalBranch is a condition and its body, leading by keyword 'if' or 'elif'.
+type ConditionalBranch struct {
+	NonTerminalNode
+	Keyword   *TerminalToken
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func NewConditionalBranch(keyword *TerminalToken, condition Expression, body *BlockStatement) *ConditionalBranch {
+	b := &ConditionalBranch{
+		Keyword:   keyword,
+		Condition: condition,
+		Body:      body,
+	}
+	b.Init(b)
+
+	return b
+}
+
+func ASTBuildConditionalBranch(keyword TokenType, condition Expression, statements ...Statement) *ConditionalBranch {
+	return NewConditionalBranch(ASTBuildKeyword(keyword), condition, ASTBuildBlockStatement(statements...))
+}
+
+func (b *ConditionalBranch) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(b, other)
+	if err != nil {
+		return err
+	}
+
+	if b.Keyword.Token != o.Keyword.Token {
+		return b.Keyword.Context().Error("wrong keyword, expect '%s', got '%s'", o.Keyword.Token, b.Keyword.Token)
+	}
+
+	if err := b.Condition.EqualTo(b, o.Condition); err != nil {
+		return err
+	}
+
+	return b.Body.EqualTo(b, o.Body)
+}
+
+func (b *ConditionalBranch) Context() *context.Context {
+	return context.JoinObjects(b.Keyword, b.Condition, b.Body)
+}
+
+type IfStatement struct {
+	NonTerminalNode
+	Branches []*ConditionalBranch
+	Else     *TerminalToken
+	ElseBody *BlockStatement
+}
+
+func NewIfStatement(branch *ConditionalBranch) *IfStatement {
+	s := &IfStatement{
+		Branches: []*ConditionalBranch{branch},
+	}
+	s.Init(s)
+
+	return s
+}
+
+// ASTBuildIfStatement builds an if statement, the first branch is 'if' and the rest are 'elif'. elseBody is
+// optional.
+func ASTBuildIfStatement(branches []*ConditionalBranch, elseBody *BlockStatement) *IfStatement {
+	s := &IfStatement{
+		Branches: branches,
+		ElseBody: elseBody,
+	}
+	if elseBody != nil {
+		s.Else = ASTBuildKeyword(Else)
+	}
+	s.Init(s)
+
+	return s
+}
+
+func (s *IfStatement) statementNode() {}
+
+func (s *IfStatement) AddElif(branch *ConditionalBranch) {
+	s.Branches = append(s.Branches, branch)
+}
+
+func (s *IfStatement) SetElse(keyword *TerminalToken, body *BlockStatement) {
+	s.Else = keyword
+	s.ElseBody = body
+}
+
+func (s *IfStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckArrayEqual("BRANCHES", s, s.Branches, o.Branches); err != nil {
+		return err
+	}
+
+	return CheckNilPointerEqual(s, s.ElseBody, o.ElseBody)
+}
+
+func (s *IfStatement) Context() *context.Context {
+	ctxList := make([]context.ContextProvider, 0, len(s.Branches)+2)
+	for _, branch := range s.Branches {
+		ctxList = append(ctxList, branch)
+	}
+	ctxList = append(ctxList, s.Else, s.ElseBody)
+
+	return context.JoinObjects(ctxList...)
+}
+
+// IncrementStatement is 'x++' or 'x--', which is a statement but not an expression.
+type IncrementStatement struct {
+	NonTerminalNode
+	Target   Expression
+	Operator *TerminalToken
+}
+
+func NewIncrementStatement(target Expression, operator *TerminalToken) *IncrementStatement {
+	s := &IncrementStatement{
+		Target:   target,
+		Operator: operator,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildIncrementStatement(target Expression, operator TokenType) *IncrementStatement {
+	return NewIncrementStatement(target, ASTBuildSymbol(operator))
+}
+
+func (s *IncrementStatement) statementNode() {}
+
+func (s *IncrementStatement) IsDecrement() bool {
+	return s.Operator.Token == Decrement
+}
+
+func (s *IncrementStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if s.Operator.Token != o.Operator.Token {
+		return s.Operator.Context().Error("wrong operator, expect '%s', got '%s'", o.Operator.Token, s.Operator.Token)
+	}
+
+	return s.Target.EqualTo(s, o.Target)
+}
+
+func (s *IncrementStatement) Context() *context.Context {
+	return context.JoinObjects(s.Target, s.Operator)
+}
+
+type WhileStatement struct {
+	NonTerminalNode
+	Keyword   *TerminalToken
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func NewWhileStatement(keyword *TerminalToken, condition Expression, body *BlockStatement) *WhileStatement {
+	s := &WhileStatement{
+		Keyword:   keyword,
+		Condition: condition,
+		Body:      body,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildWhileStatement(condition Expression, statements ...Statement) *WhileStatement {
+	return NewWhileStatement(ASTBuildKeyword(While), condition, ASTBuildBlockStatement(statements...))
+}
+
+func (s *WhileStatement) statementNode() {}
+
+func (s *WhileStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Condition.EqualTo(s, o.Condition); err != nil {
+		return err
+	}
+
+	return s.Body.EqualTo(s, o.Body)
+}
+
+func (s *WhileStatement) Context() *context.Context {
+	return context.JoinObjects(s.Keyword, s.Condition, s.Body)
+}
+
+type DoWhileStatement struct {
+	NonTerminalNode
+	Do        *TerminalToken
+	Body      *BlockStatement
+	While     *TerminalToken
+	Condition Expression
+}
+
+func NewDoWhileStatement(do *TerminalToken, body *BlockStatement, while *TerminalToken, condition Expression) *DoWhileStatement {
+	s := &DoWhileStatement{
+		Do:        do,
+		Body:      body,
+		While:     while,
+		Condition: condition,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildDoWhileStatement(condition Expression, statements ...Statement) *DoWhileStatement {
+	return NewDoWhileStatement(ASTBuildKeyword(Do), ASTBuildBlockStatement(statements...), ASTBuildKeyword(While), condition)
+}
+
+func (s *DoWhileStatement) statementNode() {}
+
+func (s *DoWhileStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Body.EqualTo(s, o.Body); err != nil {
+		return err
+	}
+
+	return s.Condition.EqualTo(s, o.Condition)
+}
+
+func (s *DoWhileStatement) Context() *context.Context {
+	return context.JoinObjects(s.Do, s.Body, s.While, s.Condition)
+}
+
+// ForStatement is a C-style for loop, all of Initializer, Condition and Post are optional.
+type ForStatement struct {
+	NonTerminalNode
+	Keyword        *TerminalToken
+	LParen         *TerminalToken
+	Initializer    Statement
+	FirstSemicolon *TerminalToken
+	Condition      Expression
+	LastSemicolon  *TerminalToken
+	Post           Statement
+	RParen         *TerminalToken
+	Body           *BlockStatement
+}
+
+func NewForStatement(keyword *TerminalToken) *ForStatement {
+	s := &ForStatement{
+		Keyword: keyword,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildForStatement(init Statement, condition Expression, post Statement, statements ...Statement) *ForStatement {
+	s := NewForStatement(ASTBuildKeyword(For))
+	s.LParen = ASTBuildSymbol(LeftParen)
+	s.Initializer = init
+	s.FirstSemicolon = ASTBuildSymbol(Semicolon)
+	s.Condition = condition
+	s.LastSemicolon = ASTBuildSymbol(Semicolon)
+	s.Post = post
+	s.RParen = ASTBuildSymbol(RightParen)
+	s.Body = ASTBuildBlockStatement(statements...)
+
+	return s
+}
+
+func (s *ForStatement) statementNode() {}
+
+func (s *ForStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOptionalNodeEqual(s, s.Initializer, o.Initializer); err != nil {
+		return err
+	}
+
+	if err := checkOptionalNodeEqual(s, s.Condition, o.Condition); err != nil {
+		return err
+	}
+
+	if err := checkOptionalNodeEqual(s, s.Post, o.Post); err != nil {
+		return err
+	}
+
+	return s.Body.EqualTo(s, o.Body)
+}
+
+func (s *ForStatement) Context() *context.Context {
+	return context.JoinObjects(s.Keyword, s.LParen, s.Initializer, s.FirstSemicolon, s.Condition,
+		s.LastSemicolon, s.Post, s.RParen, s.Body)
+}
+
+type ForeachStatement struct {
+	NonTerminalNode
+	Keyword  *TerminalToken
+	LParen   *TerminalToken
+	Variable *Identifier
+	In       *TerminalToken
+	Iterable Expression
+	RParen   *TerminalToken
+	Body     *BlockStatement
+}
+
+func NewForeachStatement(keyword *TerminalToken) *ForeachStatement {
+	s := &ForeachStatement{
+		Keyword: keyword,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildForeachStatement(variable string, iterable Expression, statements ...Statement) *ForeachStatement {
+	s := NewForeachStatement(ASTBuildKeyword(Foreach))
+	s.LParen = ASTBuildSymbol(LeftParen)
+	s.Variable = ASTBuildIdentifier(variable)
+	s.In = ASTBuildKeyword(In)
+	s.Iterable = iterable
+	s.RParen = ASTBuildSymbol(RightParen)
+	s.Body = ASTBuildBlockStatement(statements...)
+
+	return s
+}
+
+func (s *ForeachStatement) statementNode() {}
+
+func (s *ForeachStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Variable.EqualTo(s, o.Variable); err != nil {
+		return err
+	}
+
+	if err := s.Iterable.EqualTo(s, o.Iterable); err != nil {
+		return err
+	}
+
+	return s.Body.EqualTo(s, o.Body)
+}
+
+func (s *ForeachStatement) Context() *context.Context {
+	return context.JoinObjects(s.Keyword, s.LParen, s.Variable, s.In, s.Iterable, s.RParen, s.Body)
+}
+
+// LoopControlStatement is 'break' or 'continue'.
+type LoopControlStatement struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+}
+
+func NewLoopControlStatement(keyword *TerminalToken) *LoopControlStatement {
+	s := &LoopControlStatement{
+		Keyword: keyword,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildLoopControlStatement(keyword TokenType) *LoopControlStatement {
+	return NewLoopControlStatement(ASTBuildKeyword(keyword))
+}
+
+func (s *LoopControlStatement) statementNode() {}
+
+func (s *LoopControlStatement) IsBreak() bool {
+	return s.Keyword.Token == Break
+}
+
+func (s *LoopControlStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	if s.Keyword.Token != o.Keyword.Token {
+		return s.Keyword.Context().Error("wrong keyword, expect '%s', got '%s'", o.Keyword.Token, s.Keyword.Token)
+	}
+
+	return nil
+}
+
+func (s *LoopControlStatement) Context() *context.Context {
+	return s.Keyword.Context()
+}
+
+// DeleteStatement frees memory pointed by target in form of 'delete p', and the pointer is set to null.
+type DeleteStatement struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	Target  Expression
+}
+
+func NewDeleteStatement(keyword *TerminalToken, target Expression) *DeleteStatement {
+	s := &DeleteStatement{
+		Keyword: keyword,
+		Target:  target,
+	}
+	s.Init(s)
+
+	return s
+}
+
+func ASTBuildDeleteStatement(target Expression) *DeleteStatement {
+	return NewDeleteStatement(ASTBuildKeyword(Delete), target)
+}
+
+func (s *DeleteStatement) statementNode() {}
+
+func (s *DeleteStatement) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(s, other)
+	if err != nil {
+		return err
+	}
+
+	return s.Target.EqualTo(s, o.Target)
+}
+
+func (s *DeleteStatement) Context() *context.Context {
+	return context.JoinObjects(s.Keyword, s.Target)
+}
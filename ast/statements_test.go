@@ -91,3 +91,47 @@ func TestReturnStatementNotEqual(t *testing.T) {
 		t.Errorf("wrong error message:\nexpected:\n%s\ngot:\n%s", message, err.Error())
 	}
 }
+
+func TestIfStatementNotEqualInElseBody(t *testing.T) {
+	text := "if a { } else { }"
+	ctxList := generateTestWords(text)
+
+	branch := NewConditionalBranch(
+		NewTerminalToken(ctxList[0], If),
+		NewIdentifier(ctxList[1]),
+		NewBlockStatement(NewTerminalToken(ctxList[2], LeftBrace), nil, NewTerminalToken(ctxList[3], RightBrace)),
+	)
+	ifStmt := NewIfStatement(branch)
+	ifStmt.SetElse(NewTerminalToken(ctxList[4], Else),
+		NewBlockStatement(NewTerminalToken(ctxList[5], LeftBrace), nil, NewTerminalToken(ctxList[6], RightBrace)))
+
+	checkStatementNodeInterface(ifStmt)
+
+	expected := ASTBuildIfStatement(
+		[]*ConditionalBranch{
+			ASTBuildConditionalBranch(If, ASTBuildIdentifier("a")),
+		},
+		nil,
+	)
+
+	message := strings.Join([]string{
+		"test.txt:1:15: error: unexpected *ast.BlockStatement found",
+		"    1 | if a { } else { }",
+		"      |               ^ ^",
+		"      |               unexpected token",
+	}, "\n")
+
+	err := ifStmt.EqualTo(nil, expected)
+	if err == nil {
+		t.Fatalf("IfStatement expected not equal, but equal")
+	}
+
+	if err.Error() != message {
+		t.Errorf("wrong error message:\nexpected:\n%s\ngot:\n%s", message, err.Error())
+	}
+
+	expected.ElseBody = ASTBuildBlockStatement()
+	if err := ifStmt.EqualTo(nil, expected); err != nil {
+		t.Errorf("IfStatement not equal:\n%s", err)
+	}
+}
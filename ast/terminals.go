@@ -106,8 +106,74 @@ func (l *FloatLiteral) EqualTo(_ context.ContextProvider, other Comparable) erro
 	return nil
 }
 
+type BooleanLiteral struct {
+	TerminalNodeBase
+	Value bool
+}
+
+func NewBooleanLiteral(ctx *context.Context, value bool) *BooleanLiteral {
+	l := &BooleanLiteral{
+		TerminalNodeBase: NewTerminalNodeBase(ctx),
+		Value:            value,
+	}
+
+	return l
+}
+
+func (l *BooleanLiteral) expressionNode() {}
+
+func (l *BooleanLiteral) Type() TokenType {
+	if l.Value {
+		return True
+	}
+
+	return False
+}
+
+func (l *BooleanLiteral) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(l, other)
+	if err != nil {
+		return err
+	}
+
+	if o.Value != l.Value {
+		return l.Context().Error("wrong boolean value, expect %v, got %v", o.Value, l.Value).With("%v", o.Value)
+	}
+
+	return nil
+}
+
+type NullLiteral struct {
+	TerminalNodeBase
+}
+
+func NewNullLiteral(ctx *context.Context) *NullLiteral {
+	l := &NullLiteral{
+		TerminalNodeBase: NewTerminalNodeBase(ctx),
+	}
+
+	return l
+}
+
+func (l *NullLiteral) expressionNode() {}
+
+func (l *NullLiteral) Type() TokenType {
+	return Null
+}
+
+func (l *NullLiteral) EqualTo(_ context.ContextProvider, other Comparable) error {
+	_, err := CheckNodeEqual(l, other)
+	return err
+}
+
 func ASTBuildValue(v any) Expression {
 	switch val := v.(type) {
+	case nil:
+		return NewNullLiteral(nil)
+
+	case bool:
+		return NewBooleanLiteral(nil, val)
+
 	case string:
 		return NewStringLiteral(nil, val)
 
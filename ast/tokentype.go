@@ -36,6 +36,7 @@ const (
 	While
 	Do
 	Foreach
+	In
 	Break
 	Continue
 	And
@@ -75,27 +76,39 @@ const (
 	ShiftRight         // >>
 	PointerAdd         // +>>
 	PointerSub         // -<<
+	Increment          // ++
+	Decrement          // --
 	// #
 
 	punctuationBegin
-	Assign          // =
-	InferenceAssign // :=
-	LeftParen       // (
-	RightParen      // )
-	LeftBracket     // [
-	RightBracket    // ]
-	LeftBrace       // {
-	RightBrace      // }
-	Comma           // ,
-	Period          // .
-	Colon           // :
-	Semicolon       // ;
-	DualColon       // ::
-	QuestionMark    // ?
-	Bang            // !
-	Hash            // #
-	At              // @
-	CommentStart    // //
+	Assign           // =
+	InferenceAssign  // :=
+	AddAssign        // +=
+	SubAssign        // -=
+	MulAssign        // *=
+	DivAssign        // /=
+	ModAssign        // %=
+	AndAssign        // &=
+	OrAssign         // |=
+	XorAssign        // ^=
+	ShiftLeftAssign  // <<=
+	ShiftRightAssign // >>=
+	LeftParen        // (
+	RightParen       // )
+	LeftBracket      // [
+	RightBracket     // ]
+	LeftBrace        // {
+	RightBrace       // }
+	Comma            // ,
+	Period           // .
+	Colon            // :
+	Semicolon        // ;
+	DualColon        // ::
+	QuestionMark     // ?
+	Bang             // !
+	Hash             // #
+	At               // @
+	CommentStart     // //
 
 	punctuationEnd
 	operatorEnd
@@ -130,6 +143,7 @@ const (
 	SWhile              = "while"
 	SDo                 = "do"
 	SForeach            = "foreach"
+	SIn                 = "in"
 	SBreak              = "break"
 	SContinue           = "continue"
 	SAnd                = "and"
@@ -167,8 +181,20 @@ const (
 	SShiftRight         = ">>"
 	SPointerAdd         = "+>>"
 	SPointerSub         = "-<<"
+	SIncrement          = "++"
+	SDecrement          = "--"
 	SAssign             = "="
 	SInferenceAssign    = ":="
+	SAddAssign          = "+="
+	SSubAssign          = "-="
+	SMulAssign          = "*="
+	SDivAssign          = "/="
+	SModAssign          = "%="
+	SAndAssign          = "&="
+	SOrAssign           = "|="
+	SXorAssign          = "^="
+	SShiftLeftAssign    = "<<="
+	SShiftRightAssign   = ">>="
 	SLeftParen          = "("
 	SRightParen         = ")"
 	SLeftBracket        = "["
@@ -211,6 +237,7 @@ var tokenStringMap = map[TokenType]string{
 	While:              SWhile,
 	Do:                 SDo,
 	Foreach:            SForeach,
+	In:                 SIn,
 	Break:              SBreak,
 	Continue:           SContinue,
 	And:                SAnd,
@@ -247,8 +274,20 @@ var tokenStringMap = map[TokenType]string{
 	ShiftRight:         SShiftRight,
 	PointerAdd:         SPointerAdd,
 	PointerSub:         SPointerSub,
+	Increment:          SIncrement,
+	Decrement:          SDecrement,
 	Assign:             SAssign,
 	InferenceAssign:    SInferenceAssign,
+	AddAssign:          SAddAssign,
+	SubAssign:          SSubAssign,
+	MulAssign:          SMulAssign,
+	DivAssign:          SDivAssign,
+	ModAssign:          SModAssign,
+	AndAssign:          SAndAssign,
+	OrAssign:           SOrAssign,
+	XorAssign:          SXorAssign,
+	ShiftLeftAssign:    SShiftLeftAssign,
+	ShiftRightAssign:   SShiftRightAssign,
 	LeftParen:          SLeftParen,
 	RightParen:         SRightParen,
 	LeftBracket:        SLeftBracket,
@@ -307,6 +346,7 @@ var keywordMap = map[string]TokenType{
 	SWhile:      While,
 	SDo:         Do,
 	SForeach:    Foreach,
+	SIn:         In,
 	SBreak:      Break,
 	SContinue:   Continue,
 	SAnd:        And,
@@ -355,8 +395,20 @@ var operatorMap = map[string]TokenType{
 	SShiftRight:         ShiftRight,
 	SPointerAdd:         PointerAdd,
 	SPointerSub:         PointerSub,
+	SIncrement:          Increment,
+	SDecrement:          Decrement,
 	SAssign:             Assign,
 	SInferenceAssign:    InferenceAssign,
+	SAddAssign:          AddAssign,
+	SSubAssign:          SubAssign,
+	SMulAssign:          MulAssign,
+	SDivAssign:          DivAssign,
+	SModAssign:          ModAssign,
+	SAndAssign:          AndAssign,
+	SOrAssign:           OrAssign,
+	SXorAssign:          XorAssign,
+	SShiftLeftAssign:    ShiftLeftAssign,
+	SShiftRightAssign:   ShiftRightAssign,
 	SLeftParen:          LeftParen,
 	SRightParen:         RightParen,
 	SLeftBracket:        LeftBracket,
@@ -14,6 +14,7 @@ func TestTokenTypeMethods(t *testing.T) {
 		{notUsedToken, false, "<Token 1>"},
 		{True, false, "true"},
 		{Plus, true, "+"},
+		{Increment, true, "++"},
 	}
 
 	for _, c := range cases {
@@ -77,6 +77,151 @@ func (t *SimpleType) AddPointerAsterisk(asterisk *TerminalToken) {
 	t.PointerAsterisk = append(t.PointerAsterisk, asterisk)
 }
 
+// ArrayType is a fixed size array in form of 'int[4]', size is omitted in array literals and inferred from
+// elements.
+type ArrayType struct {
+	NonTerminalNode
+	ElementType *SimpleType
+	LBracket    *TerminalToken
+	Size        Expression
+	RBracket    *TerminalToken
+}
+
+func NewArrayType(element *SimpleType, lBracket *TerminalToken, size Expression, rBracket *TerminalToken) *ArrayType {
+	t := &ArrayType{
+		ElementType: element,
+		LBracket:    lBracket,
+		Size:        size,
+		RBracket:    rBracket,
+	}
+	t.Init(t)
+
+	return t
+}
+
+// ASTBuildArrayType builds an array type, size is omitted if it is not positive.
+func ASTBuildArrayType(element string, size int) *ArrayType {
+	var sizeExpr Expression
+	if size > 0 {
+		sizeExpr = ASTBuildValue(size)
+	}
+
+	return NewArrayType(ASTBuildSimpleType(element), ASTBuildSymbol(LeftBracket), sizeExpr, ASTBuildSymbol(RightBracket))
+}
+
+func (t *ArrayType) typeNode() {}
+
+func (t *ArrayType) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(t, other)
+	if err != nil {
+		return err
+	}
+
+	if err := t.ElementType.EqualTo(t, o.ElementType); err != nil {
+		return err
+	}
+
+	if t.Size == nil || o.Size == nil {
+		if t.Size != o.Size {
+			return t.LBracket.Context().Error("array size mismatch, expect %T, got %T", o.Size, t.Size)
+		}
+
+		return nil
+	}
+
+	return t.Size.EqualTo(t, o.Size)
+}
+
+func (t *ArrayType) Context() *context.Context {
+	return context.JoinObjects(t.ElementType, t.LBracket, t.Size, t.RBracket)
+}
+
+// ListType is a resizable list allocated in heap, in form of 'int[]'.
+type ListType struct {
+	NonTerminalNode
+	ElementType *SimpleType
+	LBracket    *TerminalToken
+	RBracket    *TerminalToken
+}
+
+func NewListType(element *SimpleType, lBracket *TerminalToken, rBracket *TerminalToken) *ListType {
+	t := &ListType{
+		ElementType: element,
+		LBracket:    lBracket,
+		RBracket:    rBracket,
+	}
+	t.Init(t)
+
+	return t
+}
+
+func ASTBuildListType(element string) *ListType {
+	return NewListType(ASTBuildSimpleType(element), ASTBuildSymbol(LeftBracket), ASTBuildSymbol(RightBracket))
+}
+
+func (t *ListType) typeNode() {}
+
+func (t *ListType) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(t, other)
+	if err != nil {
+		return err
+	}
+
+	return t.ElementType.EqualTo(t, o.ElementType)
+}
+
+func (t *ListType) Context() *context.Context {
+	return context.JoinObjects(t.ElementType, t.LBracket, t.RBracket)
+}
+
+// ValueType returns the named type stored by value in t, elements of arrays are included, and pointers and lists
+// are not.
+func ValueType(t Type) *SimpleType {
+	var result *SimpleType
+	switch typ := t.(type) {
+	case *SimpleType:
+		result = typ
+
+	case *ArrayType:
+		result = typ.ElementType
+	}
+
+	if result == nil || len(result.PointerAsterisk) > 0 {
+		return nil
+	}
+
+	return result
+}
+
+type AutoType struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+}
+
+func NewAutoType(keyword *TerminalToken) *AutoType {
+	t := &AutoType{
+		Keyword: keyword,
+	}
+	t.Init(t)
+
+	return t
+}
+
+func ASTBuildAutoType() *AutoType {
+	return NewAutoType(ASTBuildKeyword(Auto))
+}
+
+func (t *AutoType) typeNode() {}
+
+func (t *AutoType) EqualTo(_ context.ContextProvider, other Comparable) error {
+	_, err := CheckNodeEqual(t, other)
+	return err
+}
+
+func (t *AutoType) Context() *context.Context {
+	return t.Keyword.Context()
+}
+
 type ArgumentDeclaration struct {
 	NonTerminalNode
 	Name  *Identifier
@@ -277,3 +422,104 @@ func (l *TypeList) Context() *context.Context {
 
 	return context.JoinObjects(ctxList...)
 }
+
+type FieldDeclaration struct {
+	NonTerminalNode
+	Name *Identifier
+	Type Type
+}
+
+func NewFieldDeclaration(name *Identifier, t Type) *FieldDeclaration {
+	f := &FieldDeclaration{
+		Name: name,
+		Type: t,
+	}
+	f.Init(f)
+
+	return f
+}
+
+func ASTBuildField(name string, t string) *FieldDeclaration {
+	return NewFieldDeclaration(ASTBuildIdentifier(name), ASTBuildSimpleType(t))
+}
+
+func (f *FieldDeclaration) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(f, other)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Name.EqualTo(f, o.Name); err != nil {
+		return err
+	}
+
+	return f.Type.EqualTo(f, o.Type)
+}
+
+func (f *FieldDeclaration) Context() *context.Context {
+	return context.JoinObjects(f.Name, f.Type)
+}
+
+// StructType is body of a structure, keyword 'struct' is nil in declaration like 'struct Point { ... }'.
+type StructType struct {
+	NonTerminalNode
+	Keyword *TerminalToken
+	LBrace  *TerminalToken
+	Fields  []*FieldDeclaration
+	RBrace  *TerminalToken
+}
+
+func NewStructType(keyword *TerminalToken, lBrace *TerminalToken) *StructType {
+	t := &StructType{
+		Keyword: keyword,
+		LBrace:  lBrace,
+	}
+	t.Init(t)
+
+	return t
+}
+
+func ASTBuildStructType(fields ...*FieldDeclaration) *StructType {
+	t := NewStructType(ASTBuildKeyword(Structure), ASTBuildSymbol(LeftBrace))
+	t.Fields = fields
+	t.RBrace = ASTBuildSymbol(RightBrace)
+
+	return t
+}
+
+func (t *StructType) typeNode() {}
+
+func (t *StructType) AddField(field *FieldDeclaration) {
+	t.Fields = append(t.Fields, field)
+}
+
+// Field returns declaration of field by name.
+func (t *StructType) Field(name string) (*FieldDeclaration, bool) {
+	for _, field := range t.Fields {
+		if field.Name.Name == name {
+			return field, true
+		}
+	}
+
+	return nil, false
+}
+
+func (t *StructType) EqualTo(_ context.ContextProvider, other Comparable) error {
+	o, err := CheckNodeEqual(t, other)
+	if err != nil {
+		return err
+	}
+
+	return CheckArrayEqual("FIELDS", t, t.Fields, o.Fields)
+}
+
+func (t *StructType) Context() *context.Context {
+	ctxList := make([]context.ContextProvider, 0, len(t.Fields)+3)
+	ctxList = append(ctxList, t.Keyword, t.LBrace)
+	for _, field := range t.Fields {
+		ctxList = append(ctxList, field)
+	}
+	ctxList = append(ctxList, t.RBrace)
+
+	return context.JoinObjects(ctxList...)
+}
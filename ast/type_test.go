@@ -491,3 +491,40 @@ func TestTypeListNotEqualOnSize(t *testing.T) {
 		t.Fatalf("wrong error message:\n%s\nexpect\n%s", err, message)
 	}
 }
+
+func TestArrayType(t *testing.T) {
+	text := "lorem [ 42 ]"
+	ctxList := generateTestWords(text)
+
+	element := NewSimpleType(nil, NewIdentifier(ctxList[0]))
+	lBracket := NewTerminalToken(ctxList[1], LeftBracket)
+	size := NewIntegerLiteral(ctxList[2], 42)
+	rBracket := NewTerminalToken(ctxList[3], RightBracket)
+	arrayType := NewArrayType(element, lBracket, size, rBracket)
+	checkTypeNodeInterface(arrayType)
+
+	if err := arrayType.EqualTo(nil, ASTBuildArrayType("lorem", 42)); err != nil {
+		t.Fatalf("ArrayType not equal: %s", err)
+	}
+
+	if err := arrayType.EqualTo(nil, ASTBuildArrayType("lorem", 0)); err == nil {
+		t.Fatalf("expect a error on missing size but got nil")
+	}
+}
+
+func TestListType(t *testing.T) {
+	text := "lorem [ ]"
+	ctxList := generateTestWords(text)
+
+	element := NewSimpleType(nil, NewIdentifier(ctxList[0]))
+	listType := NewListType(element, NewTerminalToken(ctxList[1], LeftBracket), NewTerminalToken(ctxList[2], RightBracket))
+	checkTypeNodeInterface(listType)
+
+	if err := listType.EqualTo(nil, ASTBuildListType("lorem")); err != nil {
+		t.Fatalf("ListType not equal: %s", err)
+	}
+
+	if err := listType.EqualTo(nil, ASTBuildArrayType("lorem", 0)); err == nil {
+		t.Fatalf("expect a error on array type but got nil")
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/flily/magi-c/coder"
+	"github.com/flily/magi-c/coder/check"
+	"github.com/flily/magi-c/coder/csyntax"
 	"github.com/flily/magi-c/context"
 )
 
@@ -16,7 +18,7 @@ func translateFile(c *coder.Coder, filename string) error {
 	indexName, err := c.ParseFile(filename)
 	if err != nil {
 		switch e := err.(type) {
-		case *context.Diagnostic:
+		case *context.Diagnostic, *context.DiagnosticContainer:
 			fmt.Printf("Syntax error:\n%s\n", e)
 
 		default:
@@ -32,6 +34,10 @@ func translateFile(c *coder.Coder, filename string) error {
 		return err
 	}
 
+	if result := c.Diagnostics(indexName); result != nil && result.Count(context.Warning) > 0 {
+		fmt.Printf("Check warning:\n%s\n", result)
+	}
+
 	outputFilename := c.OutputFilename(indexName)
 	fmt.Printf("%s -> [%s] %s", filename, indexName, outputFilename)
 	err = c.Output(indexName)
@@ -45,6 +51,18 @@ func translateFile(c *coder.Coder, filename string) error {
 	return nil
 }
 
+// ruleOptions are options of check rules given by repeated flags.
+type ruleOptions []string
+
+func (o *ruleOptions) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *ruleOptions) Set(value string) error {
+	*o = append(*o, value)
+	return nil
+}
+
 func translateDirectory(c *coder.Coder, base string) error {
 	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -69,23 +87,91 @@ func translateDirectory(c *coder.Coder, base string) error {
 	return nil
 }
 
-func doTranslate(args []string) error {
-	set := flag.NewFlagSet("translate", flag.ExitOnError)
-	output := set.String("output", "output", "output base directory")
-	_ = set.Parse(args)
+// coderFlags are flags shared by commands creating a coder.
+type coderFlags struct {
+	set    *flag.FlagSet
+	output *string
+	debug  *bool
+	target *string
+	std    *string
+	rules  ruleOptions
+	werror *bool
+}
+
+func newCoderFlags(name string) *coderFlags {
+	f := &coderFlags{set: flag.NewFlagSet(name, flag.ExitOnError)}
+	f.output = f.set.String("output", "output", "output base directory")
+	f.debug = f.set.Bool("debug", false, "enable runtime checks in output")
+	f.target = f.set.String("target", check.DefaultTargetProfile.Name,
+		"target profile evaluating sizes of types, one of: "+strings.Join(check.TargetProfileNames(), ", "))
+	f.std = f.set.String("std", "c99", "C standard of output, one of: c89, c99")
+	f.set.Var(&f.rules, "W", "override check rule, 'ID', 'no-ID' or 'error=ID', where ID is one of: "+
+		strings.Join(check.RuleIDs(), ", "))
+	f.werror = f.set.Bool("Werror", false, "raise warnings of check rules to errors")
+	return f
+}
+
+// base returns path of source given in arguments, which is current directory by default.
+func (f *coderFlags) base() string {
+	if f.set.NArg() > 0 {
+		return f.set.Arg(0)
+	}
+
+	return "."
+}
+
+// newCoder creates coder translating source in base, levels of check rules are overridden by project config in dir,
+// and then by options in command line.
+func (f *coderFlags) newCoder(base string, dir string) (*coder.Coder, error) {
+	profile, found := check.LookupTargetProfile(*f.target)
+	if !found {
+		return nil, fmt.Errorf("unknown target profile '%s'", *f.target)
+	}
+
+	standard, found := csyntax.LookupCStandard(*f.std)
+	if !found {
+		return nil, fmt.Errorf("unknown C standard '%s'", *f.std)
+	}
+
+	c := coder.NewCoder(base, *f.output)
+	c.Debug = *f.debug
+	c.Target = profile
+	c.Standard = standard
+	c.Checks.WarningAsError = *f.werror
 
-	base := "."
+	err := c.Checks.ReadRuleFile(filepath.Join(dir, check.RuleFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
 
-	if set.NArg() > 0 {
-		base = set.Arg(0)
+	for _, option := range f.rules {
+		if err := c.Checks.SetRuleOption(option); err != nil {
+			return nil, err
+		}
 	}
 
+	return c, nil
+}
+
+func doTranslate(args []string) error {
+	flags := newCoderFlags("translate")
+	_ = flags.set.Parse(args)
+
+	base := flags.base()
 	stat, err := os.Stat(base)
 	if err != nil {
 		return err
 	}
 
-	c := coder.NewCoder(base, *output)
+	dir := base
+	if !stat.IsDir() {
+		dir = filepath.Dir(base)
+	}
+
+	c, err := flags.newCoder(base, dir)
+	if err != nil {
+		return err
+	}
 
 	if stat.IsDir() {
 		err = translateDirectory(c, base)
@@ -98,16 +184,10 @@ func doTranslate(args []string) error {
 }
 
 func doBuild(args []string) error {
-	set := flag.NewFlagSet("translate", flag.ExitOnError)
-	output := set.String("output", "output", "output base directory")
-	_ = set.Parse(args)
-
-	base := "."
-
-	if set.NArg() > 0 {
-		base = set.Arg(0)
-	}
+	flags := newCoderFlags("build")
+	_ = flags.set.Parse(args)
 
+	base := flags.base()
 	stat, err := os.Stat(base)
 	if err != nil {
 		return err
@@ -118,7 +198,11 @@ func doBuild(args []string) error {
 		return nil
 	}
 
-	c := coder.NewCoder(base, *output)
+	c, err := flags.newCoder(base, base)
+	if err != nil {
+		return err
+	}
+
 	err = translateDirectory(c, base)
 	if err != nil {
 		return err
@@ -6,11 +6,13 @@ import (
 
 type Cache struct {
 	Documents map[string]*ast.Document
+	Modules   map[string]string
 }
 
 func NewCache() *Cache {
 	c := &Cache{
 		Documents: make(map[string]*ast.Document),
+		Modules:   make(map[string]string),
 	}
 
 	return c
@@ -24,3 +26,13 @@ func (c *Cache) Get(index string) (*ast.Document, bool) {
 	doc, ok := c.Documents[index]
 	return doc, ok
 }
+
+// AddModule records index of document which declares the module.
+func (c *Cache) AddModule(name string, index string) {
+	c.Modules[name] = index
+}
+
+func (c *Cache) Module(name string) (string, bool) {
+	index, ok := c.Modules[name]
+	return index, ok
+}
@@ -36,3 +36,17 @@ func TestCacheBasic(t *testing.T) {
 		t.Fatalf("Expected to not find document '%s' in cache, but it was found", filenameNotExist)
 	}
 }
+
+func TestCacheModule(t *testing.T) {
+	cache := NewCache()
+	cache.AddModule("math", "math.mc")
+
+	index, ok := cache.Module("math")
+	if !ok || index != "math.mc" {
+		t.Fatalf("Failed to get module 'math' from cache, got '%s'", index)
+	}
+
+	if _, ok := cache.Module("io"); ok {
+		t.Fatalf("Expected to not find module 'io' in cache, but it was found")
+	}
+}
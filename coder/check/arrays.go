@@ -0,0 +1,209 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// checkArraySize checks size of array which SHALL be a positive integer constant, size is optional in literals
+// and 0 is returned.
+func checkArraySize(scope *Scope, t *ast.ArrayType, literal bool) (int, context.DiagnosticInfo) {
+	if t.Size == nil {
+		if literal {
+			return 0, nil
+		}
+
+		return 0, t.LBracket.Context().Error("missing size of array").
+			With("SHALL be a positive integer constant").
+			ByRule(RuleArraySize, context.Error)
+	}
+
+	size, ok := scope.ConstantInteger(t.Size)
+	if e := unknownSizeof(scope, t.Size); !ok && e != nil {
+		return 0, e.Context().Error("size is unknown for target '%s'", scope.Root().Layout.Target.Name).
+			With("array size SHALL be known at compile time, select a target profile to evaluate it").
+			ByRule(RuleArraySize, context.Error)
+	}
+
+	if !ok {
+		return 0, t.Size.Context().Error("array size is not a constant").
+			With("SHALL be a positive integer constant").
+			ByRule(RuleArraySize, context.Error)
+	}
+
+	if size <= 0 {
+		return 0, t.Size.Context().Error("invalid array size %d", size).
+			With("SHALL be a positive integer constant").
+			ByRule(RuleArraySize, context.Error)
+	}
+
+	return int(size), nil
+}
+
+// unknownSizeof finds sizeof in expression whose size is unknown for target.
+func unknownSizeof(scope *Scope, expr ast.Expression) *ast.SizeofExpression {
+	switch e := expr.(type) {
+	case *ast.SizeofExpression:
+		if _, ok := scope.ConstantInteger(e); !ok {
+			return e
+		}
+
+	case *ast.ParenthesizedExpression:
+		return unknownSizeof(scope, e.Expression)
+
+	case *ast.PrefixExpression:
+		return unknownSizeof(scope, e.Operand)
+
+	case *ast.InfixExpression:
+		if found := unknownSizeof(scope, e.LeftOperand); found != nil {
+			return found
+		}
+
+		return unknownSizeof(scope, e.RightOperand)
+	}
+
+	return nil
+}
+
+// checkType checks type used in declarations of variables and fields.
+func checkType(scope *Scope, t ast.Type) context.DiagnosticInfo {
+	if a, ok := t.(*ast.ArrayType); ok {
+		_, err := checkArraySize(scope, a, false)
+		return err
+	}
+
+	return nil
+}
+
+// typeLength returns number of elements of a fixed size array, or 0 if type is not an array.
+func typeLength(scope *Scope, t ast.Type) int {
+	if a, ok := t.(*ast.ArrayType); ok && a.Size != nil {
+		if size, ok := scope.ConstantInteger(a.Size); ok && size > 0 {
+			return int(size)
+		}
+	}
+
+	return 0
+}
+
+// literalLength returns number of elements of an array literal, which is inferred from elements if size is omitted.
+func literalLength(scope *Scope, l *ast.ArrayLiteral) int {
+	if size := typeLength(scope, l.Type); size > 0 {
+		return size
+	}
+
+	return l.Elements.Length()
+}
+
+// valueLength returns number of elements in value of variable declared in type t, or 0 if it is not an array.
+func valueLength(scope *Scope, t ast.Type, value ast.Expression) int {
+	if size := typeLength(scope, t); size > 0 {
+		return size
+	}
+
+	if l, ok := value.(*ast.ArrayLiteral); ok {
+		return literalLength(scope, l)
+	}
+
+	return 0
+}
+
+// checkArrayLiteral checks size and elements of an array literal, an empty literal without size is allowed only
+// for lists.
+func checkArrayLiteral(scope *Scope, l *ast.ArrayLiteral, list bool) context.DiagnosticInfo {
+	size, err := checkArraySize(scope, l.Type, true)
+	if err != nil {
+		return err
+	}
+
+	if err := checkExpressionList(scope, l.Elements); err != nil {
+		return err
+	}
+
+	count := l.Elements.Length()
+	if size == 0 && count == 0 && !list {
+		return l.Context().Error("missing size of empty array literal").
+			With("size or elements required").
+			ByRule(RuleArraySize, context.Error)
+	}
+
+	if size > 0 && count > size {
+		return l.Elements.Expressions[size].Context().Error("too many elements in array literal, expect at most %d, got %d", size, count).
+			With("array index %d out of bounds", size).
+			ByRule(RuleArrayLiteral, context.Error)
+	}
+
+	return nil
+}
+
+// checkArrayInitializer checks array literal assigned to a variable declared in type t.
+func checkArrayInitializer(scope *Scope, t ast.Type, value ast.Expression) context.DiagnosticInfo {
+	_, isArray := t.(*ast.ArrayType)
+	l, ok := value.(*ast.ArrayLiteral)
+	if _, isList := t.(*ast.ListType); isList && ok {
+		return value.Context().Error("cannot use array literal as list").
+			With("allocate list by 'new %s'", l.Type.ElementType.Identifier.Name+"[]{...}").
+			ByRule(RuleArrayLiteral, context.Error)
+	}
+
+	if !ok {
+		if isArray {
+			return value.Context().Error("cannot initialize array with non-literal value").
+				With("SHALL be an array literal").
+				ByRule(RuleArrayLiteral, context.Error)
+		}
+
+		return nil
+	}
+
+	size, length := typeLength(scope, t), literalLength(scope, l)
+	if size > 0 && length > size {
+		return value.Context().Error("cannot use array of %d elements as %d-element array", length, size).
+			With("too many elements").
+			ByRule(RuleArrayLiteral, context.Error).
+			For(t.Context().Note("declared here"))
+	}
+
+	return nil
+}
+
+// checkArrayCopy rejects inferring a variable from another array, arrays are not copied by value.
+func checkArrayCopy(scope *Scope, value ast.Expression) context.DiagnosticInfo {
+	id, ok := value.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found || symbol.Length <= 0 {
+		return nil
+	}
+
+	return id.Context().Error("cannot copy array '%s'", id.Name).
+		With("arrays SHALL be initialized by array literals").
+		ByRule(RuleArrayCopy, context.Error).
+		For(symbol.Context.Note("declared here"))
+}
+
+// checkIndexBounds rejects constant index out of bounds of a fixed size array.
+func checkIndexBounds(scope *Scope, e *ast.IndexExpression) context.DiagnosticInfo {
+	id, ok := e.Object.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found || symbol.Length <= 0 {
+		return nil
+	}
+
+	index, ok := scope.ConstantInteger(e.Index)
+	if !ok || (index >= 0 && index < int64(symbol.Length)) {
+		return nil
+	}
+
+	return e.Index.Context().Error("invalid array index %d, out of bounds for %d-element array", index, symbol.Length).
+		With("SHALL be in range [0, %d)", symbol.Length).
+		ByRule(RuleIndexOutOfBounds, context.Error).
+		For(symbol.Context.Note("declared here"))
+}
@@ -0,0 +1,144 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckArraysCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"global var table int32[2 * 4] = int32[8]{1, 2, 3}",
+		"",
+		"fun sum(n int32) with(table int32[8]) (int32) {",
+		"    nums := int32[]{1, 2, 3, 4}",
+		"    var buf int32[4] = int32[]{nums[0]}",
+		"    buf[3] = table[7]",
+		"    return buf[n] + nums[3]",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckArrayErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[]{1, 2, 3}",
+				"    return nums[3]",
+				"}",
+			},
+			[]string{
+				"test.mc:3:17: error: invalid array index 3, out of bounds for 3-element array [index-out-of-bounds]",
+				"    3 |     return nums[3]",
+				"      |                 ^",
+				"      |                 SHALL be in range [0, 3)",
+				"test.mc:2:5: note: declared here",
+				"    2 |     nums := int32[]{1, 2, 3}",
+				"      |     ^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[2]{1, 2, 3}",
+				"    return nums[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:28: error: too many elements in array literal, expect at most 2, got 3 [array-literal]",
+				"    2 |     nums := int32[2]{1, 2, 3}",
+				"      |                            ^",
+				"      |                            array index 2 out of bounds",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[]{}",
+				"    return nums[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:13: error: missing size of empty array literal [array-size]",
+				"    2 |     nums := int32[]{}",
+				"      |             ^^^^^^^^^",
+				"      |             size or elements required",
+			},
+		},
+		{
+			[]string{
+				"fun get(n int32) (int32) {",
+				"    var nums int32[n]",
+				"    return nums[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:20: error: array size is not a constant [array-size]",
+				"    2 |     var nums int32[n]",
+				"      |                    ^",
+				"      |                    SHALL be a positive integer constant",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[]{1, 2, 3}",
+				"    var buf int32[4] = nums",
+				"    return buf[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:3:24: error: cannot initialize array with non-literal value [array-literal]",
+				"    3 |     var buf int32[4] = nums",
+				"      |                        ^^^^",
+				"      |                        SHALL be an array literal",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[]{1, 2, 3}",
+				"    copied := nums",
+				"    return copied[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:3:15: error: cannot copy array 'nums' [array-copy]",
+				"    3 |     copied := nums",
+				"      |               ^^^^",
+				"      |               arrays SHALL be initialized by array literals",
+				"test.mc:2:5: note: declared here",
+				"    2 |     nums := int32[]{1, 2, 3}",
+				"      |     ^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    nums := int32[]{1, 2, 3}",
+				"    var buf int32[3]",
+				"    buf = nums",
+				"    return buf[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:4:5: error: cannot assign to array 'buf' [array-copy]",
+				"    4 |     buf = nums",
+				"      |     ^^^",
+				"      |     assign to its elements instead",
+				"test.mc:3:9: note: declared here",
+				"    3 |     var buf int32[3]",
+				"      |         ^^^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
@@ -9,11 +9,22 @@ import (
 
 type CheckConfigure struct {
 	Level context.ErrorLevel
+
+	// Target is the target profile, by which sizes of types are evaluated at compile time.
+	Target *TargetProfile
+
+	// Rules overrides levels of rules by ID, a rule in level Ignored is disabled.
+	Rules map[string]context.ErrorLevel
+
+	// WarningAsError raises warnings of all rules to errors.
+	WarningAsError bool
 }
 
 func NewDefaultCheckConfigure() *CheckConfigure {
 	c := &CheckConfigure{
-		Level: context.Error,
+		Level:  context.Error,
+		Target: DefaultTargetProfile,
+		Rules:  make(map[string]context.ErrorLevel),
 	}
 
 	return c
@@ -77,10 +88,25 @@ func (r *CheckRunner[T, B]) Run(conf *CheckConfigure, node B) *context.Diagnosti
 	return c
 }
 
-func checkDeclaration(conf *CheckConfigure, d ast.Declaration) *context.DiagnosticContainer {
+func checkDeclaration(conf *CheckConfigure, globals *Scope, d ast.Declaration) *context.DiagnosticContainer {
 	switch decl := d.(type) {
 	case *ast.FunctionDeclaration:
-		return checkFunctionDeclaration(conf, decl)
+		return checkFunctionDeclaration(conf, globals, decl)
+
+	case *ast.GlobalDeclaration:
+		return NewCheckList(func(d *ast.GlobalDeclaration) context.DiagnosticInfo {
+			return checkGlobalDeclaration(globals, d)
+		}).Check(conf, decl)
+
+	case *ast.TypeDeclaration:
+		return checkTypeDeclaration(conf, globals, decl)
+
+	case *ast.ModuleDeclaration, *ast.ImportDeclaration:
+		return nil
+
+	case *ast.ErrorNode:
+		// syntax errors are reported by parser
+		return nil
 
 	case *ast.PreprocessorInclude:
 		return nil
@@ -89,16 +115,30 @@ func checkDeclaration(conf *CheckConfigure, d ast.Declaration) *context.Diagnost
 		return nil
 
 	default:
-		return d.Context().Error("unsupported declaration type %T", d).ToContainer()
+		return d.Context().Error("unsupported declaration type %T", d).
+			ByRule(RuleUnsupportedSyntax, context.Error).ToContainer()
 	}
 }
 
-func checkDocument(conf *CheckConfigure, doc *ast.Document) *context.DiagnosticContainer {
+func checkDocument(conf *CheckConfigure, imports map[string]*ast.Document, info *TypeInfo, names *resolution, doc *ast.Document) *context.DiagnosticContainer {
+	c := context.NewDiagnosticContainer(conf.Level)
+	globals, err := declareGlobals(conf, doc)
+	if err == nil {
+		globals.Info = info
+		err = declareImports(globals, imports, doc)
+	}
+
+	if err != nil {
+		_ = c.Add(err)
+		return c
+	}
+
 	l := NewCheckRunner(
-		checkDeclaration,
+		func(conf *CheckConfigure, d ast.Declaration) *context.DiagnosticContainer {
+			return checkDeclaration(conf, globals, d)
+		},
 	)
 
-	c := context.NewDiagnosticContainer(conf.Level)
 	for _, decl := range doc.Declarations {
 		err := l.Run(conf, decl)
 		if err != nil {
@@ -109,26 +149,47 @@ func checkDocument(conf *CheckConfigure, doc *ast.Document) *context.DiagnosticC
 		}
 	}
 
+	_ = c.Merge(resolveDocument(conf, globals, names, doc))
 	return c
 }
 
 type CodeChecker struct {
 	config   *CheckConfigure
 	document *ast.Document
+	imports  map[string]*ast.Document
+	types    *TypeInfo
+	names    *resolution
 }
 
 func NewCodeChecker(conf *CheckConfigure, document *ast.Document) *CodeChecker {
 	c := &CodeChecker{
 		config:   conf,
 		document: document,
+		imports:  make(map[string]*ast.Document),
+		types:    NewTypeInfo(),
+		names:    newResolution(),
 	}
 
 	return c
 }
 
+// AddImport adds document of a module imported by the checked document.
+func (c *CodeChecker) AddImport(name string, doc *ast.Document) {
+	c.imports[name] = doc
+}
+
+// Types returns types of expressions resolved by Check.
+func (c *CodeChecker) Types() *TypeInfo {
+	return c.types
+}
+
 func (c *CodeChecker) Check() *context.DiagnosticContainer {
 	l := NewCheckRunner(
-		checkDocument,
+		checkDocumentTypes,
+		checkDocumentFunctions,
+		func(conf *CheckConfigure, doc *ast.Document) *context.DiagnosticContainer {
+			return checkDocument(conf, c.imports, c.types, c.names, doc)
+		},
 	)
 
 	return l.Run(c.config, c.document)
@@ -1,6 +1,7 @@
 package check
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/flily/magi-c/ast"
@@ -12,7 +13,13 @@ import (
 func parseCode(t *testing.T, source string) *ast.Document {
 	t.Helper()
 
-	parser := parser.NewLLParserFromCode(source, "test.mc")
+	return parseCodeFile(t, source, "test.mc")
+}
+
+func parseCodeFile(t *testing.T, source string, filename string) *ast.Document {
+	t.Helper()
+
+	parser := parser.NewLLParserFromCode(source, filename)
 	preprocessor.RegisterPreprocessors(parser)
 	doc, err := parser.Parse()
 	if err != nil {
@@ -59,3 +66,35 @@ func checkCodeError(t *testing.T, source string, expected string) {
 		t.Fatalf("code check error mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
 	}
 }
+
+func TestCheckPartialDocument(t *testing.T) {
+	source := strings.Join([]string{
+		"fun f() {",
+		"    var x int = = 3",
+		"}",
+		"fun g() {",
+		"    var y int = 1",
+		"    var y int = 2",
+		"}",
+	}, "\n")
+
+	doc, err := parser.NewLLParserFromCode(source, "test.mc").Parse()
+	if err == nil {
+		t.Fatalf("syntax error expected")
+	}
+
+	expected := strings.Join([]string{
+		"test.mc:6:9: error: duplicated variable name: 'y' [duplicated-name]",
+		"    6 |     var y int = 2",
+		"      |         ^",
+		"      |         duplicated name",
+		"test.mc:5:9: note: first declared here",
+		"    5 |     var y int = 1",
+		"      |         ^",
+	}, "\n")
+
+	container := NewCodeChecker(NewDefaultCheckConfigure(), doc).Check()
+	if container.Error() != expected {
+		t.Fatalf("code check error mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
+	}
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func checkIdentifier(scope *Scope, id *ast.Identifier) context.DiagnosticInfo {
+	symbol, found := scope.Lookup(id.Name)
+	if found && symbol.Kind == SymbolGlobal {
+		return id.Context().Error("global variable '%s' is used without declaration", id.Name).
+			With("declare it in 'with(...)' or by 'global var %s'", id.Name).
+			ByRule(RuleGlobalAccess, context.Error).
+			For(symbol.Context.Note("declared as global here"))
+	}
+
+	return nil
+}
+
+// checkExpression checks names used in an expression, members are not variables and are not checked.
+func checkExpression(scope *Scope, expr ast.Expression) context.DiagnosticInfo {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		if err := checkIdentifier(scope, e); err != nil {
+			return err
+		}
+
+		return checkOwnerState(scope, e)
+
+	case *ast.ParenthesizedExpression:
+		return checkExpression(scope, e.Expression)
+
+	case *ast.PrefixExpression:
+		return checkExpression(scope, e.Operand)
+
+	case *ast.InfixExpression:
+		if err := checkExpression(scope, e.LeftOperand); err != nil {
+			return err
+		}
+
+		return checkExpression(scope, e.RightOperand)
+
+	case *ast.CallExpression:
+		if err := checkExpression(scope, e.Callee); err != nil {
+			return err
+		}
+
+		if err := checkModuleCall(scope, e); err != nil {
+			return err
+		}
+
+		if err := checkBuiltinCall(scope, e); err != nil {
+			return err
+		}
+
+		return checkExpressionList(scope, e.Arguments)
+
+	case *ast.MemberExpression:
+		if module, found := moduleOf(scope, e); found {
+			_, err := checkModuleMember(module, e)
+			return err
+		}
+
+		return checkExpression(scope, e.Object)
+
+	case *ast.IndexExpression:
+		if err := checkExpression(scope, e.Object); err != nil {
+			return err
+		}
+
+		if err := checkExpression(scope, e.Index); err != nil {
+			return err
+		}
+
+		return checkIndexBounds(scope, e)
+
+	case *ast.ArrayLiteral:
+		return checkArrayLiteral(scope, e, false)
+
+	case *ast.StructLiteral:
+		return checkStructLiteral(scope, e)
+
+	case *ast.NewExpression:
+		return checkNewExpression(scope, e)
+
+	case *ast.PointerArithmeticExpression:
+		if err := checkExpression(scope, e.Pointer); err != nil {
+			return err
+		}
+
+		if err := checkExpression(scope, e.Offset); err != nil {
+			return err
+		}
+
+		return checkPointerArithmetic(scope, e)
+
+	case *ast.SizeofExpression:
+		// operand of sizeof is not evaluated, only types are checked
+		if t, _ := SizeofOperand(e, scope.variableType); t != nil {
+			return checkType(scope, t)
+		}
+	}
+
+	return nil
+}
+
+func checkExpressionList(scope *Scope, list *ast.ExpressionList) context.DiagnosticInfo {
+	if list == nil {
+		return nil
+	}
+
+	for _, item := range list.Expressions {
+		if err := checkExpression(scope, item.Expression); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// FlowBlock is a basic block in control flow graph, whose statements are executed in sequence. Statements with
+// bodies, like if and loops, are in the block where their conditions are evaluated.
+type FlowBlock struct {
+	Statements []ast.Statement
+	Successors []*FlowBlock
+}
+
+// FlowLoop is a loop in control flow graph, Header evaluates the condition, and After is reached when loop exits.
+type FlowLoop struct {
+	Statement ast.Statement
+	Header    *FlowBlock
+	After     *FlowBlock
+
+	// Forever tells whether condition of loop is constantly true.
+	Forever bool
+}
+
+// FlowGraph is control flow graph of a function. Return statements go to Exit, and End is reached by running off
+// the end of function body.
+type FlowGraph struct {
+	Entry   *FlowBlock
+	End     *FlowBlock
+	Exit    *FlowBlock
+	Blocks  []*FlowBlock
+	Loops   []*FlowLoop
+	Returns []*ast.ReturnStatement
+
+	blockOf map[ast.Statement]*FlowBlock
+}
+
+type flowBuilder struct {
+	graph   *FlowGraph
+	current *FlowBlock
+
+	// breaks and continues are targets of loop control statements in nested loops, the innermost is the last.
+	breaks    []*FlowBlock
+	continues []*FlowBlock
+}
+
+// BuildFlowGraph builds control flow graph of function body.
+func BuildFlowGraph(d *ast.FunctionDeclaration) *FlowGraph {
+	g := &FlowGraph{
+		blockOf: make(map[ast.Statement]*FlowBlock),
+	}
+
+	b := &flowBuilder{graph: g}
+	g.Entry = b.newBlock()
+	g.Exit = b.newBlock()
+	b.current = g.Entry
+	b.statements(d.Statements)
+
+	g.End = b.newBlock()
+	b.jump(g.End)
+	b.link(g.End, g.Exit)
+	return g
+}
+
+func (b *flowBuilder) newBlock() *FlowBlock {
+	block := &FlowBlock{}
+	b.graph.Blocks = append(b.graph.Blocks, block)
+	return block
+}
+
+func (b *flowBuilder) link(from *FlowBlock, to *FlowBlock) {
+	from.Successors = append(from.Successors, to)
+}
+
+// jump links current block to target, and statements following are in a new block without predecessor.
+func (b *flowBuilder) jump(target *FlowBlock) {
+	b.link(b.current, target)
+	b.current = b.newBlock()
+}
+
+func (b *flowBuilder) add(stmt ast.Statement) {
+	b.current.Statements = append(b.current.Statements, stmt)
+	b.graph.blockOf[stmt] = b.current
+}
+
+func (b *flowBuilder) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		b.statement(stmt)
+	}
+}
+
+// branch builds body starting from block, and links its end to join.
+func (b *flowBuilder) branch(from *FlowBlock, stmts []ast.Statement, join *FlowBlock) {
+	start := b.newBlock()
+	b.link(from, start)
+	b.current = start
+	b.statements(stmts)
+	b.link(b.current, join)
+}
+
+func (b *flowBuilder) statement(stmt ast.Statement) {
+	b.add(stmt)
+	switch s := stmt.(type) {
+	case *ast.ReturnStatement:
+		b.graph.Returns = append(b.graph.Returns, s)
+		b.jump(b.graph.Exit)
+
+	case *ast.LoopControlStatement:
+		targets := b.continues
+		if s.IsBreak() {
+			targets = b.breaks
+		}
+
+		if len(targets) > 0 {
+			// loop control out of loop is reported by checkLoopControlStatement
+			b.jump(targets[len(targets)-1])
+		}
+
+	case *ast.IfStatement:
+		b.ifStatement(s)
+
+	case *ast.WhileStatement:
+		header := b.newBlock()
+		b.link(b.current, header)
+		run, exit := loopCondition(s.Condition)
+		b.loop(s, header, header, run, exit, s.Body.Statements)
+
+	case *ast.DoWhileStatement:
+		body, header := b.newBlock(), b.newBlock()
+		b.link(b.current, body)
+		b.current = body
+		_, exit := loopCondition(s.Condition)
+		after := b.enterLoop(s, header, header, exit)
+		b.statements(s.Body.Statements)
+		b.link(b.current, header)
+		b.exitLoop(header, body, after, true, exit)
+
+	case *ast.ForStatement:
+		if s.Initializer != nil {
+			b.add(s.Initializer)
+		}
+
+		header, post := b.newBlock(), b.newBlock()
+		b.link(b.current, header)
+		if s.Post != nil {
+			post.Statements = append(post.Statements, s.Post)
+			b.graph.blockOf[s.Post] = post
+		}
+
+		b.link(post, header)
+		run, exit := true, false
+		if s.Condition != nil {
+			run, exit = loopCondition(s.Condition)
+		}
+
+		b.loop(s, header, post, run, exit, s.Body.Statements)
+
+	case *ast.ForeachStatement:
+		// iteration ends after the last element
+		header := b.newBlock()
+		b.link(b.current, header)
+		b.loop(s, header, header, true, true, s.Body.Statements)
+	}
+}
+
+func (b *flowBuilder) ifStatement(s *ast.IfStatement) {
+	join := b.newBlock()
+	for _, branch := range s.Branches {
+		cond := b.current
+		value, constant := ConstantBoolean(branch.Condition)
+		if !constant || value {
+			b.branch(cond, branch.Body.Statements, join)
+
+		} else {
+			// body of branch never runs, which is still built for unreachable statements
+			b.current = b.newBlock()
+			b.statements(branch.Body.Statements)
+			b.link(b.current, join)
+		}
+
+		b.current = b.newBlock()
+		if !constant || !value {
+			b.link(cond, b.current)
+		}
+	}
+
+	if s.ElseBody != nil {
+		b.statements(s.ElseBody.Statements)
+	}
+
+	b.link(b.current, join)
+	b.current = join
+}
+
+// enterLoop registers a loop whose condition is evaluated in header, and returns block after the loop. Continue
+// goes to next, and the loop never terminates by condition if it can not exit.
+func (b *flowBuilder) enterLoop(stmt ast.Statement, header *FlowBlock, next *FlowBlock, exit bool) *FlowBlock {
+	after := b.newBlock()
+	b.graph.Loops = append(b.graph.Loops, &FlowLoop{
+		Statement: stmt,
+		Header:    header,
+		After:     after,
+		Forever:   !exit,
+	})
+
+	b.breaks = append(b.breaks, after)
+	b.continues = append(b.continues, next)
+	return after
+}
+
+// exitLoop links condition in header to body and block after loop, and statements following are after the loop.
+func (b *flowBuilder) exitLoop(header *FlowBlock, body *FlowBlock, after *FlowBlock, run bool, exit bool) {
+	if run {
+		b.link(header, body)
+	}
+
+	if exit {
+		b.link(header, after)
+	}
+
+	b.breaks = b.breaks[:len(b.breaks)-1]
+	b.continues = b.continues[:len(b.continues)-1]
+	b.current = after
+}
+
+// loop builds loop checking condition in header before body.
+func (b *flowBuilder) loop(stmt ast.Statement, header *FlowBlock, next *FlowBlock, run bool, exit bool, stmts []ast.Statement) {
+	after := b.enterLoop(stmt, header, next, exit)
+	body := b.newBlock()
+	b.current = body
+	b.statements(stmts)
+	b.link(b.current, next)
+	b.exitLoop(header, body, after, run, exit)
+}
+
+// loopCondition tells whether condition of loop may be true to run the body, or false to exit the loop.
+func loopCondition(cond ast.Expression) (bool, bool) {
+	value, constant := ConstantBoolean(cond)
+	if !constant {
+		return true, true
+	}
+
+	return value, !value
+}
+
+// Reachable returns blocks reachable from block.
+func (g *FlowGraph) Reachable(from *FlowBlock) map[*FlowBlock]bool {
+	visited := make(map[*FlowBlock]bool)
+	stack := []*FlowBlock{from}
+	for len(stack) > 0 {
+		block := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[block] {
+			continue
+		}
+
+		visited[block] = true
+		stack = append(stack, block.Successors...)
+	}
+
+	return visited
+}
+
+// checkFunctionReturnValue checks all paths of function with return value types return the number of values
+// declared.
+func checkFunctionReturnValue(d *ast.FunctionDeclaration) context.DiagnosticInfo {
+	if d.ReturnTypes == nil {
+		return nil
+	}
+
+	count := d.ReturnTypes.Length()
+	g := BuildFlowGraph(d)
+	for _, ret := range g.Returns {
+		got := 0
+		if ret.Value != nil {
+			got = ret.Value.Length()
+		}
+
+		if got == count {
+			continue
+		}
+
+		c2 := ret.Return.Context()
+		if got > 0 {
+			c2 = ret.Value.Context()
+		}
+
+		err := c2.Error("function return value count mismatch, expect %d, got %d", count, got).
+			With("SHALL return %d values", count).
+			ByRule(RuleReturnCount, context.Error).
+			For(d.ReturnTypes.Context().Note("return value types is declared here"))
+		return err
+	}
+
+	if g.Reachable(g.Entry)[g.End] {
+		c1 := d.ReturnTypes.Context()
+		c2 := d.RBrace.Context()
+		err := c2.Error("function missing return statement and reach the end of function").
+			ByRule(RuleMissingReturn, context.Error).
+			For(c1.Note("function return value types is declared here"))
+		return err
+	}
+
+	return nil
+}
+
+// checkFunctionFlow reports statements never executed, and loops never terminate, which are warnings.
+func checkFunctionFlow(conf *CheckConfigure, d *ast.FunctionDeclaration) *context.DiagnosticContainer {
+	c := context.NewDiagnosticContainer(conf.Level)
+	g := BuildFlowGraph(d)
+	reachable := g.Reachable(g.Entry)
+	suppressions := FunctionSuppressions(d)
+
+	dead := false
+	var walk func(stmts []ast.Statement)
+	walk = func(stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			live := reachable[g.blockOf[stmt]]
+			if !live && !dead {
+				err := ruleDiagnostic(conf, suppressions, RuleUnreachableCode,
+					stmt.Context().Warning("unreachable code").With("never executed"))
+				if err != nil {
+					_ = c.Add(err)
+				}
+			}
+
+			dead = !live
+			for _, body := range statementBodies(stmt) {
+				walk(body)
+			}
+		}
+	}
+
+	walk(d.Statements)
+	for _, loop := range g.Loops {
+		if !loop.Forever || !reachable[loop.Header] {
+			continue
+		}
+
+		from := g.Reachable(loop.Header)
+		if from[loop.After] || from[g.Exit] {
+			continue
+		}
+
+		err := ruleDiagnostic(conf, suppressions, RuleEndlessLoop,
+			loopHead(loop.Statement).Warning("loop never terminates").
+				With("condition is always true, and no break or return in loop"))
+		if err != nil {
+			_ = c.Add(err)
+		}
+	}
+
+	return c
+}
+
+// loopHead returns context of loop keyword and condition.
+func loopHead(stmt ast.Statement) *context.Context {
+	switch s := stmt.(type) {
+	case *ast.WhileStatement:
+		return context.JoinObjects(s.Keyword, s.Condition)
+
+	case *ast.DoWhileStatement:
+		return context.JoinObjects(s.While, s.Condition)
+
+	case *ast.ForStatement:
+		return context.JoinObjects(s.Keyword, s.LParen, s.Condition, s.RParen)
+	}
+
+	return stmt.Context()
+}
+
+// statementBodies returns statements nested in a statement, like bodies of branches and loops.
+func statementBodies(stmt ast.Statement) [][]ast.Statement {
+	switch s := stmt.(type) {
+	case *ast.IfStatement:
+		bodies := make([][]ast.Statement, 0, len(s.Branches)+1)
+		for _, branch := range s.Branches {
+			bodies = append(bodies, branch.Body.Statements)
+		}
+
+		if s.ElseBody != nil {
+			bodies = append(bodies, s.ElseBody.Statements)
+		}
+
+		return bodies
+
+	case *ast.WhileStatement:
+		return [][]ast.Statement{s.Body.Statements}
+
+	case *ast.DoWhileStatement:
+		return [][]ast.Statement{s.Body.Statements}
+
+	case *ast.ForStatement:
+		return [][]ast.Statement{s.Body.Statements}
+
+	case *ast.ForeachStatement:
+		return [][]ast.Statement{s.Body.Statements}
+	}
+
+	return nil
+}
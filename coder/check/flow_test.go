@@ -0,0 +1,180 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/context"
+)
+
+func TestCheckFlowCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sign(n int) (int) {",
+		"    if n > 0 {",
+		"        return 1",
+		"    } elif n < 0 {",
+		"        return -1",
+		"    } else {",
+		"        return 0",
+		"    }",
+		"}",
+		"fun first(n int) (int) {",
+		"    while true {",
+		"        if n > 10 {",
+		"            return n",
+		"        }",
+		"        n = n + 1",
+		"    }",
+		"}",
+		"fun count(n int) (int) {",
+		"    i := 0",
+		"    for (; true; i = i + 1) {",
+		"        if i >= n {",
+		"            break",
+		"        }",
+		"    }",
+		"    return i",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	container := NewCodeChecker(NewDefaultCheckConfigure(), doc).Check()
+	if container.Count(context.Warning) > 0 {
+		t.Fatalf("code check expected to succeed, but got:\n%s", container.Error())
+	}
+}
+
+func TestCheckFlowErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun abs(n int) (int) {",
+				"    if n < 0 {",
+				"        return -n",
+				"    }",
+				"}",
+			},
+			[]string{
+				"test.mc:5:1: error: function missing return statement and reach the end of function [missing-return]",
+				"    5 | }",
+				"      | ^",
+				"test.mc:1:17: note: function return value types is declared here",
+				"    1 | fun abs(n int) (int) {",
+				"      |                 ^^^",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    return n",
+				"    n = n + 1",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:5: warning: unreachable code [unreachable-code]",
+				"    3 |     n = n + 1",
+				"      |     ^ ^ ^ ^ ^",
+				"      |     never executed",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    while n > 0 {",
+				"        break",
+				"        n = n - 1",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:4:9: warning: unreachable code [unreachable-code]",
+				"    4 |         n = n - 1",
+				"      |         ^ ^ ^ ^ ^",
+				"      |         never executed",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    while true {",
+				"        n = n + 1",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:5:5: warning: unreachable code [unreachable-code]",
+				"    5 |     return n",
+				"      |     ^^^^^^ ^",
+				"      |     never executed",
+				"test.mc:2:5: warning: loop never terminates [endless-loop]",
+				"    2 |     while true {",
+				"      |     ^^^^^ ^^^^",
+				"      |     condition is always true, and no break or return in loop",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    while 1 == 1 {",
+				"        n = n + 1",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:5:5: warning: unreachable code [unreachable-code]",
+				"    5 |     return n",
+				"      |     ^^^^^^ ^",
+				"      |     never executed",
+				"test.mc:2:5: warning: loop never terminates [endless-loop]",
+				"    2 |     while 1 == 1 {",
+				"      |     ^^^^^ ^ ^^ ^",
+				"      |     condition is always true, and no break or return in loop",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    if n > 0 and (1 << 2) < 3 {",
+				"        n = 0",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:9: warning: unreachable code [unreachable-code]",
+				"    3 |         n = 0",
+				"      |         ^ ^ ^",
+				"      |         never executed",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    if false {",
+				"        n = 0",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:9: warning: unreachable code [unreachable-code]",
+				"    3 |         n = 0",
+				"      |         ^ ^ ^",
+				"      |         never executed",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join(c.code, "\n")
+		expected := strings.Join(c.expected, "\n")
+		checkCodeError(t, code, expected)
+	}
+}
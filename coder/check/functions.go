@@ -21,6 +21,7 @@ func checkFunctionDeclarationNameDuplicate(d *ast.FunctionDeclaration) context.D
 			ectx := name.Context()
 			err := ectx.Error("duplicated function argument name: '%s'", name.Name).
 				With("duplicated name").
+				ByRule(RuleDuplicatedName, context.Error).
 				For(ctx.Note("first declared here"))
 			return err
 		}
@@ -31,46 +32,6 @@ func checkFunctionDeclarationNameDuplicate(d *ast.FunctionDeclaration) context.D
 	return nil
 }
 
-func checkFunctionReturnValue(d *ast.FunctionDeclaration) context.DiagnosticInfo {
-	if d.ReturnTypes == nil {
-		return nil
-	}
-
-	count := d.ReturnTypes.Length()
-
-	retFound := false
-	for _, stmt := range d.Statements {
-		retStmt, ok := stmt.(*ast.ReturnStatement)
-		if !ok {
-			continue
-		}
-
-		retFound = true
-		if len(retStmt.Value.Expressions) != count {
-			c1 := d.ReturnTypes.Context()
-			c2 := retStmt.Return.Context()
-			if len(retStmt.Value.Expressions) > 0 {
-				c2 = retStmt.Value.Context()
-			}
-
-			err := c2.Error("function return value count mismatch, expect %d, got %d", count, len(retStmt.Value.Expressions)).
-				With("SHALL return %d values", count).
-				For(c1.Note("return value types is declared here"))
-			return err
-		}
-	}
-
-	if !retFound {
-		c1 := d.ReturnTypes.Context()
-		c2 := d.RBrace.Context()
-		err := c2.Error("function missing return statement and reach the end of function").
-			For(c1.Note("function return value types is declared here"))
-		return err
-	}
-
-	return nil
-}
-
 func checkFunctionMainDeclaration(d *ast.FunctionDeclaration) context.DiagnosticInfo {
 	if d.Name.Name != "main" {
 		return nil
@@ -84,7 +45,8 @@ func checkFunctionMainDeclaration(d *ast.FunctionDeclaration) context.Diagnostic
 	if d.ReturnTypes.Length() != 1 {
 		ctx := d.ReturnTypes.Context()
 		err := ctx.Error("function 'main' must have return type 'int' or no return type, got %d return types", d.ReturnTypes.Length()).
-			With("int or no return type")
+			With("int or no return type").
+			ByRule(RuleMainSignature, context.Error)
 		return err
 	}
 
@@ -92,12 +54,72 @@ func checkFunctionMainDeclaration(d *ast.FunctionDeclaration) context.Diagnostic
 	return nil
 }
 
-func checkFunctionDeclaration(conf *CheckConfigure, d *ast.FunctionDeclaration) *context.DiagnosticContainer {
+func checkFunctionDeclaration(conf *CheckConfigure, globals *Scope, d *ast.FunctionDeclaration) *context.DiagnosticContainer {
 	l := NewCheckList(
+		checkFunctionBuiltinName,
 		checkFunctionDeclarationNameDuplicate,
 		checkFunctionReturnValue,
 		checkFunctionMainDeclaration,
+		func(d *ast.FunctionDeclaration) context.DiagnosticInfo { return checkFunctionBody(globals, d) },
+	)
+
+	r := NewCheckRunner(
+		l.Check,
+		checkFunctionFlow,
+	)
+
+	return r.Run(conf, d)
+}
+
+// checkFunctionNameDuplicate reports functions declared more than once, methods can not be overridden either.
+func checkFunctionNameDuplicate(doc *ast.Document) context.DiagnosticInfo {
+	functions := make(map[string]*ast.FunctionDeclaration)
+	for _, decl := range doc.Declarations {
+		d, ok := decl.(*ast.FunctionDeclaration)
+		if !ok {
+			continue
+		}
+
+		name := d.FullName()
+		first, found := functions[name]
+		if !found {
+			functions[name] = d
+			continue
+		}
+
+		note := first.Name.Context().Note("first declared here")
+		if d.IsMethod() {
+			return d.Name.Context().Error("duplicated method '%s'", name).
+				With("method can not be overridden").
+				ByRule(RuleDuplicatedName, context.Error).
+				For(note)
+		}
+
+		return d.Name.Context().Error("duplicated function '%s'", name).
+			With("duplicated name").
+			ByRule(RuleDuplicatedName, context.Error).
+			For(note)
+	}
+
+	return nil
+}
+
+func checkDocumentFunctions(conf *CheckConfigure, doc *ast.Document) *context.DiagnosticContainer {
+	l := NewCheckList(
+		checkFunctionNameDuplicate,
 	)
 
-	return l.Check(conf, d)
+	return l.Check(conf, doc)
+}
+
+// DocumentFunctions returns functions declared in document by full name, methods are named like 'Point.Distance'.
+func DocumentFunctions(doc *ast.Document) map[string]*ast.FunctionDeclaration {
+	functions := make(map[string]*ast.FunctionDeclaration)
+	for _, decl := range doc.Declarations {
+		if d, ok := decl.(*ast.FunctionDeclaration); ok {
+			functions[d.FullName()] = d
+		}
+	}
+
+	return functions
 }
@@ -24,7 +24,7 @@ func TestCheckFunctionDeclarationNameDuplicated(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:1:23: error: duplicated function argument name: 'a'",
+		"test.mc:1:23: error: duplicated function argument name: 'a' [duplicated-name]",
 		"    1 | fun add(a int, b int, a int) (int) {",
 		"      |                       ^",
 		"      |                       duplicated name",
@@ -54,7 +54,7 @@ func TestCheckFunctionReturnValueCountMismatched1(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:2:12: error: function return value count mismatch, expect 2, got 1",
+		"test.mc:2:12: error: function return value count mismatch, expect 2, got 1 [return-count]",
 		"    2 |     return a + b",
 		"      |            ^ ^ ^",
 		"      |            SHALL return 2 values",
@@ -74,7 +74,7 @@ func TestCheckFunctionReturnValueCountMismatched2(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:2:5: error: function return value count mismatch, expect 2, got 0",
+		"test.mc:2:5: error: function return value count mismatch, expect 2, got 0 [return-count]",
 		"    2 |     return",
 		"      |     ^^^^^^",
 		"      |     SHALL return 2 values",
@@ -93,7 +93,7 @@ func TestCheckFunctionMissingReturnStatement1(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:2:1: error: function missing return statement and reach the end of function",
+		"test.mc:2:1: error: function missing return statement and reach the end of function [missing-return]",
 		"    2 | }",
 		"      | ^",
 		"test.mc:1:24: note: function return value types is declared here",
@@ -111,7 +111,7 @@ func TestCheckFunctionMissingReturnStatement2(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:2:1: error: function missing return statement and reach the end of function",
+		"test.mc:2:1: error: function missing return statement and reach the end of function [missing-return]",
 		"    2 | }",
 		"      | ^",
 		"test.mc:1:24: note: function return value types is declared here",
@@ -150,7 +150,7 @@ func TestCheckFunctionMainDeclarationWithMultipleReturnTypes(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
-		"test.mc:1:13: error: function 'main' must have return type 'int' or no return type, got 2 return types",
+		"test.mc:1:13: error: function 'main' must have return type 'int' or no return type, got 2 return types [main-signature]",
 		"    1 | fun main() (int, int) {",
 		"      |             ^^^^ ^^^",
 		"      |             int or no return type",
@@ -158,3 +158,68 @@ func TestCheckFunctionMainDeclarationWithMultipleReturnTypes(t *testing.T) {
 
 	checkCodeError(t, code, expected)
 }
+
+func TestCheckMethodsCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"struct Point { x int; y int }",
+		"",
+		"fun (p *Point) Sum() (int) {",
+		"    return p.x + p.y",
+		"}",
+		"",
+		"fun Point.New() (*Point) {",
+		"    return null",
+		"}",
+		"",
+		"fun New() (int) {",
+		"    return 0",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckMethodDuplicated(t *testing.T) {
+	code := strings.Join([]string{
+		"fun (p *Point) Sum() (int) {",
+		"    return 0",
+		"}",
+		"",
+		"fun Point.Sum() (int) {",
+		"    return 1",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:5:11: error: duplicated method 'Point.Sum' [duplicated-name]",
+		"    5 | fun Point.Sum() (int) {",
+		"      |           ^^^",
+		"      |           method can not be overridden",
+		"test.mc:1:16: note: first declared here",
+		"    1 | fun (p *Point) Sum() (int) {",
+		"      |                ^^^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckFunctionDuplicated(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f() {",
+		"}",
+		"fun f() {",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:5: error: duplicated function 'f' [duplicated-name]",
+		"    3 | fun f() {",
+		"      |     ^",
+		"      |     duplicated name",
+		"test.mc:1:5: note: first declared here",
+		"    1 | fun f() {",
+		"      |     ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
@@ -0,0 +1,188 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// isConstantExpression tells whether an expression can be evaluated at compile time, which is required by static
+// initializers in C.
+func isConstantExpression(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.BooleanLiteral, *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.NullLiteral,
+		*ast.SizeofExpression:
+		return true
+
+	case *ast.ParenthesizedExpression:
+		return isConstantExpression(e.Expression)
+
+	case *ast.PrefixExpression:
+		return e.Operator.Token != ast.Asterisk && e.Operator.Token != ast.Ampersand &&
+			isConstantExpression(e.Operand)
+
+	case *ast.InfixExpression:
+		return isConstantExpression(e.LeftOperand) && isConstantExpression(e.RightOperand)
+
+	case *ast.ArrayLiteral:
+		for _, item := range e.Elements.Expressions {
+			if !isConstantExpression(item.Expression) {
+				return false
+			}
+		}
+
+		return true
+
+	case *ast.StructLiteral:
+		for _, field := range e.Fields {
+			if !isConstantExpression(field.Value) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func sameType(scope *Scope, a ast.Type, b ast.Type) bool {
+	if la, ok := a.(*ast.ListType); ok {
+		lb, ok := b.(*ast.ListType)
+		return ok && sameType(scope, la.ElementType, lb.ElementType)
+	}
+
+	if aa, ok := a.(*ast.ArrayType); ok {
+		ab, ok := b.(*ast.ArrayType)
+		return ok && sameType(scope, aa.ElementType, ab.ElementType) && typeLength(scope, aa) == typeLength(scope, ab)
+	}
+
+	ta, ok1 := a.(*ast.SimpleType)
+	tb, ok2 := b.(*ast.SimpleType)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	return ta.Identifier.Name == tb.Identifier.Name && len(ta.PointerAsterisk) == len(tb.PointerAsterisk)
+}
+
+func checkGlobalDeclaration(globals *Scope, d *ast.GlobalDeclaration) context.DiagnosticInfo {
+	v := d.Variable
+	if err := checkType(globals, v.Type); err != nil {
+		return err
+	}
+
+	if v.IsConst() {
+		return v.Keyword.Context().Error("global constant is not supported").
+			With("use 'var' or a local constant").
+			ByRule(RuleUnsupportedSyntax, context.Error)
+	}
+
+	if v.Value == nil {
+		if v.IsAuto() {
+			return v.Name.Context().Error("missing value to infer type of '%s'", v.Name.Name).
+				With("type or value required").
+				ByRule(RuleMissingValue, context.Error)
+		}
+
+		return nil
+	}
+
+	if !isConstantExpression(v.Value) {
+		return v.Value.Context().Error("initializer of global variable '%s' is not a constant", v.Name.Name).
+			With("SHALL be a constant expression").
+			ByRule(RuleGlobalInitializer, context.Error)
+	}
+
+	if l, ok := v.Value.(*ast.ArrayLiteral); ok {
+		if err := checkArrayLiteral(globals, l, false); err != nil {
+			return err
+		}
+	}
+
+	if err := checkArrayInitializer(globals, v.Type, v.Value); err != nil {
+		return err
+	}
+
+	if v.IsAuto() {
+		_, err := typeOf(globals, v.Value)
+		return err
+	}
+
+	usage := fmt.Sprintf("declaration of '%s'", v.Name.Name)
+	_, err := checkValueType(globals, v.Type, v.Value, usage)
+	return err
+}
+
+// declareGlobals declares global variables of document in a scope, which is parent of all function scopes.
+func declareGlobals(conf *CheckConfigure, doc *ast.Document) (*Scope, context.DiagnosticInfo) {
+	scope := NewScope(nil)
+	scope.Types = DocumentTypes(doc)
+	scope.Layout = NewLayout(conf.Target, scope.Types)
+	scope.Functions = DocumentFunctions(doc)
+	for _, decl := range doc.Declarations {
+		d, ok := decl.(*ast.GlobalDeclaration)
+		if !ok {
+			continue
+		}
+
+		symbol, err := scope.Declare(d.Variable.Name, SymbolGlobal)
+		if err != nil {
+			return nil, err
+		}
+
+		symbol.Type = d.Variable.Type
+		symbol.Owner = valueOwnership(scope, d.Variable.Type, d.Variable.Value)
+		symbol.Length = valueLength(scope, d.Variable.Type, d.Variable.Value)
+		symbol.List = isList(d.Variable.Type, d.Variable.Value)
+		if d.Variable.IsAuto() {
+			symbol.Value = expressionValueKind(scope, d.Variable.Value)
+
+		} else {
+			symbol.Value = typeValueKind(d.Variable.Type)
+		}
+	}
+
+	return scope, nil
+}
+
+// declareGlobalAccess declares a global variable used in function, the variable SHALL be declared in document in
+// the same type.
+func declareGlobalAccess(scope *Scope, name *ast.Identifier, t ast.Type) context.DiagnosticInfo {
+	global, found := scope.Root().Symbols[name.Name]
+	if !found || global.Kind != SymbolGlobal {
+		return name.Context().Error("undefined global variable '%s'", name.Name).
+			With("not declared in document").
+			ByRule(RuleGlobalAccess, context.Error)
+	}
+
+	if t != nil && global.Type != nil && !sameType(scope, t, global.Type) {
+		return t.Context().Error("type of global variable '%s' mismatch", name.Name).
+			With("SHALL be the same as declaration").
+			ByRule(RuleGlobalAccess, context.Error).
+			For(global.Type.Context().Note("declared here"))
+	}
+
+	symbol, err := scope.Declare(name, SymbolVariable)
+	if symbol != nil {
+		symbol.Type = global.Type
+		symbol.Global = global
+		symbol.Owner = global.Owner
+		symbol.Value = global.Value
+		symbol.Length = global.Length
+		symbol.List = global.List
+	}
+
+	return err
+}
+
+func checkGlobalAccessStatement(scope *Scope, d *ast.GlobalDeclaration) context.DiagnosticInfo {
+	if d.Variable.Value != nil {
+		return d.Variable.Value.Context().Error("global variable can not be initialized in function").
+			With("initialize it in declaration in document").
+			ByRule(RuleGlobalAccess, context.Error)
+	}
+
+	return declareGlobalAccess(scope, d.Variable.Name, d.Variable.Type)
+}
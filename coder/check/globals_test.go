@@ -0,0 +1,138 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckGlobalVariablesCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"global var gbr int32 = 1",
+		"global var gbi int32 = -(2 * 3)",
+		"",
+		"fun calc1(ar int32) with(gbr int32) (int32) {",
+		"    return ar + gbr",
+		"}",
+		"",
+		"fun [gbr int32, gbi int32]calc2() (int32) {",
+		"    gbi = gbr",
+		"    return gbi",
+		"}",
+		"",
+		"fun calc3() (int32) {",
+		"    global var gbi int32",
+		"    gbi++",
+		"    var gbr = 2",
+		"    return gbr + gbi",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckGlobalVariableErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"global var count int32 = 0",
+				"fun bump() {",
+				"    count++",
+				"}",
+			},
+			[]string{
+				"test.mc:3:5: error: global variable 'count' is used without declaration [global-access]",
+				"    3 |     count++",
+				"      |     ^^^^^",
+				"      |     declare it in 'with(...)' or by 'global var count'",
+				"test.mc:1:12: note: declared as global here",
+				"    1 | global var count int32 = 0",
+				"      |            ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"global var count int32 = 0",
+				"fun get() (int32) {",
+				"    if count > 0 {",
+				"    }",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:8: error: global variable 'count' is used without declaration [global-access]",
+				"    3 |     if count > 0 {",
+				"      |        ^^^^^",
+				"      |        declare it in 'with(...)' or by 'global var count'",
+				"test.mc:1:12: note: declared as global here",
+				"    1 | global var count int32 = 0",
+				"      |            ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun get() with(count int32) (int32) {",
+				"    return count",
+				"}",
+			},
+			[]string{
+				"test.mc:1:16: error: undefined global variable 'count' [global-access]",
+				"    1 | fun get() with(count int32) (int32) {",
+				"      |                ^^^^^",
+				"      |                not declared in document",
+			},
+		},
+		{
+			[]string{
+				"global var count int32 = 0",
+				"fun get() (int64) {",
+				"    global var count int64",
+				"    return count",
+				"}",
+			},
+			[]string{
+				"test.mc:3:22: error: type of global variable 'count' mismatch [global-access]",
+				"    3 |     global var count int64",
+				"      |                      ^^^^^",
+				"      |                      SHALL be the same as declaration",
+				"test.mc:1:18: note: declared here",
+				"    1 | global var count int32 = 0",
+				"      |                  ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"global var count int32 = 0",
+				"fun get() {",
+				"    global var count int32 = 1",
+				"}",
+			},
+			[]string{
+				"test.mc:3:30: error: global variable can not be initialized in function [global-access]",
+				"    3 |     global var count int32 = 1",
+				"      |                              ^",
+				"      |                              initialize it in declaration in document",
+			},
+		},
+		{
+			[]string{
+				"fun one() (int32) {",
+				"    return 1",
+				"}",
+				"global var count int32 = one()",
+			},
+			[]string{
+				"test.mc:4:26: error: initializer of global variable 'count' is not a constant [global-initializer]",
+				"    4 | global var count int32 = one()",
+				"      |                          ^^^^^",
+				"      |                          SHALL be a constant expression",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
@@ -0,0 +1,353 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+)
+
+// TargetProfile describes sizes of types depending on target platform, a size of 0 is unknown. Types in fixed sizes,
+// like int32 and float64, are known on all targets.
+type TargetProfile struct {
+	Name        string
+	IntSize     int64
+	PointerSize int64
+
+	// MaxAlign is the maximum alignment of scalar types in structures, which are aligned to their sizes up to it.
+	MaxAlign int64
+}
+
+var targetProfiles = []*TargetProfile{
+	{Name: "portable"},
+	{Name: "i386", IntSize: 4, PointerSize: 4, MaxAlign: 4},
+	{Name: "x86_64", IntSize: 4, PointerSize: 8, MaxAlign: 8},
+	{Name: "arm", IntSize: 4, PointerSize: 4, MaxAlign: 8},
+	{Name: "aarch64", IntSize: 4, PointerSize: 8, MaxAlign: 8},
+}
+
+// DefaultTargetProfile knows only sizes of fixed size types, other sizes are got by C 'sizeof' at compile time.
+var DefaultTargetProfile = targetProfiles[0]
+
+// LookupTargetProfile finds target profile by name.
+func LookupTargetProfile(name string) (*TargetProfile, bool) {
+	for _, profile := range targetProfiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+
+	return nil, false
+}
+
+// TargetProfileNames returns names of all target profiles.
+func TargetProfileNames() []string {
+	names := make([]string, 0, len(targetProfiles))
+	for _, profile := range targetProfiles {
+		names = append(names, profile.Name)
+	}
+
+	return names
+}
+
+var fixedTypeSizes = map[string]int64{
+	"int8":    1,
+	"int16":   2,
+	"int32":   4,
+	"int64":   8,
+	"uint8":   1,
+	"uint16":  2,
+	"uint32":  4,
+	"uint64":  8,
+	"float32": 4,
+	"float64": 8,
+}
+
+// isBasicType tells whether name is a builtin type.
+func isBasicType(name string) bool {
+	_, found := fixedTypeSizes[name]
+	return found || name == "int" || name == "bool"
+}
+
+// VariableLookup returns declared type of a variable, found is false if name is not a variable. Type is nil if it
+// is not declared, like variables declared by inference.
+type VariableLookup func(name string) (t ast.Type, found bool)
+
+// Layout computes sizes of types for a target profile, named types are looked up in declarations of document.
+type Layout struct {
+	Target *TargetProfile
+	Types  map[string]*ast.TypeDeclaration
+}
+
+func NewLayout(target *TargetProfile, types map[string]*ast.TypeDeclaration) *Layout {
+	l := &Layout{
+		Target: target,
+		Types:  types,
+	}
+
+	return l
+}
+
+// DocumentTypes returns types declared in document by name.
+func DocumentTypes(doc *ast.Document) map[string]*ast.TypeDeclaration {
+	types := make(map[string]*ast.TypeDeclaration)
+	for _, decl := range doc.Declarations {
+		if d, ok := decl.(*ast.TypeDeclaration); ok {
+			types[d.Name.Name] = d
+		}
+	}
+
+	return types
+}
+
+// SizeOf returns size of type in bytes, false is returned if it is unknown for the target.
+func (l *Layout) SizeOf(t ast.Type) (int64, bool) {
+	size, _, ok := l.layoutOf(t, make(map[string]bool))
+	return size, ok
+}
+
+// layoutOf returns size and alignment of type, visiting records named types on path to stop at recursive types.
+func (l *Layout) layoutOf(t ast.Type, visiting map[string]bool) (int64, int64, bool) {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		if len(typ.PointerAsterisk) > 0 {
+			return l.scalar(l.Target.PointerSize)
+		}
+
+		return l.namedLayout(typ.Identifier.Name, visiting)
+
+	case *ast.ArrayType:
+		if typ.Size == nil {
+			return 0, 0, false
+		}
+
+		count, ok := l.ConstantInteger(typ.Size, nil)
+		if !ok || count <= 0 {
+			return 0, 0, false
+		}
+
+		size, align, ok := l.layoutOf(typ.ElementType, visiting)
+		return size * count, align, ok
+
+	case *ast.ListType:
+		// lists are handles of runtime
+		return l.scalar(l.Target.PointerSize)
+	}
+
+	return 0, 0, false
+}
+
+func (l *Layout) namedLayout(name string, visiting map[string]bool) (int64, int64, bool) {
+	if size, found := fixedTypeSizes[name]; found {
+		return l.scalar(size)
+	}
+
+	switch name {
+	case "int", "bool":
+		return l.scalar(l.Target.IntSize)
+	}
+
+	d, found := l.Types[name]
+	if !found || visiting[name] {
+		return 0, 0, false
+	}
+
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	s, ok := d.StructType()
+	if !ok {
+		return l.layoutOf(d.Definition, visiting)
+	}
+
+	if l.Target.MaxAlign <= 0 {
+		// padding of structures depends on target
+		return 0, 0, false
+	}
+
+	var size, maxAlign int64 = 0, 1
+	for _, field := range s.Fields {
+		fieldSize, align, ok := l.layoutOf(field.Type, visiting)
+		if !ok {
+			return 0, 0, false
+		}
+
+		size = alignTo(size, align) + fieldSize
+		maxAlign = max(maxAlign, align)
+	}
+
+	return alignTo(size, maxAlign), maxAlign, true
+}
+
+// scalar returns layout of a scalar type in size, which is aligned to its size up to maximum alignment of target.
+// Alignment of fixed size types is unknown if the target is unknown.
+func (l *Layout) scalar(size int64) (int64, int64, bool) {
+	if size <= 0 {
+		return 0, 0, false
+	}
+
+	if l.Target.MaxAlign <= 0 {
+		// only size is known, which is enough out of structures
+		return size, 0, true
+	}
+
+	return size, min(size, l.Target.MaxAlign), true
+}
+
+func alignTo(offset int64, align int64) int64 {
+	if align <= 1 {
+		return offset
+	}
+
+	return (offset + align - 1) / align * align
+}
+
+// SizeofOperand resolves operand of sizeof to a type or an expression. Operand in form of a type is an expression if
+// it is named by a variable, like 'sizeof(*p)' and 'sizeof(buf[1])'.
+func SizeofOperand(e *ast.SizeofExpression, lookup VariableLookup) (ast.Type, ast.Expression) {
+	if e.Type == nil {
+		return nil, e.Value
+	}
+
+	if lookup != nil {
+		if _, found := lookup(typeRootName(e.Type)); found {
+			if expr, ok := typeAsExpression(e.Type); ok {
+				return nil, expr
+			}
+		}
+	}
+
+	return e.Type, nil
+}
+
+func typeRootName(t ast.Type) string {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		return typ.Identifier.Name
+
+	case *ast.ArrayType:
+		return typ.ElementType.Identifier.Name
+
+	case *ast.ListType:
+		return typ.ElementType.Identifier.Name
+	}
+
+	return ""
+}
+
+// typeAsExpression converts operand of sizeof parsed as a type to expression, like '*p' and 'buf[1]'.
+func typeAsExpression(t ast.Type) (ast.Expression, bool) {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		var expr ast.Expression = typ.Identifier
+		for i := len(typ.PointerAsterisk) - 1; i >= 0; i-- {
+			expr = ast.NewPrefixExpression(typ.PointerAsterisk[i], expr)
+		}
+
+		return expr, true
+
+	case *ast.ArrayType:
+		object, ok := typeAsExpression(typ.ElementType)
+		if !ok || typ.Size == nil {
+			return nil, false
+		}
+
+		return ast.NewIndexExpression(object, typ.LBracket, typ.Size, typ.RBracket), true
+	}
+
+	return nil, false
+}
+
+// Sizeof evaluates size of type or expression in sizeof, false is returned if it is unknown for the target.
+func (l *Layout) Sizeof(e *ast.SizeofExpression, lookup VariableLookup) (int64, bool) {
+	t, expr := SizeofOperand(e, lookup)
+	if expr != nil {
+		t = l.expressionType(expr, lookup)
+	}
+
+	if t == nil {
+		return 0, false
+	}
+
+	return l.SizeOf(t)
+}
+
+// expressionType returns type of expression in sizeof, which is not evaluated. Types of variables, elements and
+// fields are known by declarations, nil is returned if it is unknown.
+func (l *Layout) expressionType(expr ast.Expression, lookup VariableLookup) ast.Type {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return ast.ASTBuildSimpleType("int")
+
+	case *ast.FloatLiteral:
+		return ast.ASTBuildSimpleType("float64")
+
+	case *ast.BooleanLiteral:
+		return ast.ASTBuildSimpleType("bool")
+
+	case *ast.Identifier:
+		if lookup == nil {
+			return nil
+		}
+
+		t, _ := lookup(e.Name)
+		return t
+
+	case *ast.ParenthesizedExpression:
+		return l.expressionType(e.Expression, lookup)
+
+	case *ast.PrefixExpression:
+		if e.Operator.Token == ast.Asterisk {
+			return dereference(l.expressionType(e.Operand, lookup))
+		}
+
+	case *ast.IndexExpression:
+		switch t := l.expressionType(e.Object, lookup).(type) {
+		case *ast.ArrayType:
+			return t.ElementType
+
+		case *ast.ListType:
+			return t.ElementType
+
+		case *ast.SimpleType:
+			return dereference(t)
+		}
+
+	case *ast.MemberExpression:
+		t, ok := l.expressionType(e.Object, lookup).(*ast.SimpleType)
+		if !ok || len(t.PointerAsterisk) > 1 {
+			return nil
+		}
+
+		d, found := l.Types[t.Identifier.Name]
+		if !found {
+			return nil
+		}
+
+		if s, ok := d.StructType(); ok {
+			if field, found := s.Field(e.Member.Name); found {
+				return field.Type
+			}
+		}
+	}
+
+	return nil
+}
+
+// dereference returns type pointed to by a pointer type, or nil if t is not a pointer.
+func dereference(t ast.Type) ast.Type {
+	if p, ok := t.(*ast.SimpleType); ok && len(p.PointerAsterisk) > 0 {
+		return ast.NewSimpleType(p.PointerAsterisk[1:], p.Identifier)
+	}
+
+	return nil
+}
+
+// ConstantInteger evaluates an integer constant expression like ConstantInteger, and sizes known for the target are
+// constants. Layout may be nil, and no size is known.
+func (l *Layout) ConstantInteger(expr ast.Expression, lookup VariableLookup) (int64, bool) {
+	if l == nil {
+		return ConstantInteger(expr)
+	}
+
+	return evaluateInteger(expr, func(e *ast.SizeofExpression) (int64, bool) {
+		return l.Sizeof(e, lookup)
+	})
+}
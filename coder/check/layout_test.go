@@ -0,0 +1,195 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func TestLayoutSizeOf(t *testing.T) {
+	code := strings.Join([]string{
+		"type Header struct {",
+		"    kind uint8",
+		"    length uint32",
+		"    next *Header",
+		"}",
+		"type Packet struct {",
+		"    header Header",
+		"    flag bool",
+		"    payload uint8[sizeof(uint32) * 3]",
+		"}",
+		"type Id uint16",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	types := DocumentTypes(doc)
+
+	cases := []struct {
+		target string
+		typ    ast.Type
+		size   int64
+		known  bool
+	}{
+		{"portable", ast.ASTBuildSimpleType("int32"), 4, true},
+		{"portable", ast.ASTBuildSimpleType("Id"), 2, true},
+		{"portable", ast.ASTBuildArrayType("float64", 4), 32, true},
+		{"portable", ast.ASTBuildSimpleType("int"), 0, false},
+		{"portable", ast.ASTBuildSimpleType("*uint8"), 0, false},
+		{"portable", ast.ASTBuildSimpleType("Header"), 0, false},
+		{"portable", ast.ASTBuildSimpleType("Unknown"), 0, false},
+		{"x86_64", ast.ASTBuildSimpleType("*uint8"), 8, true},
+		{"x86_64", ast.ASTBuildListType("int32"), 8, true},
+		{"x86_64", ast.ASTBuildSimpleType("Header"), 16, true},
+		{"x86_64", ast.ASTBuildSimpleType("Packet"), 32, true},
+		{"i386", ast.ASTBuildSimpleType("Header"), 12, true},
+		{"i386", ast.ASTBuildSimpleType("Packet"), 28, true},
+	}
+
+	for i, c := range cases {
+		target, found := LookupTargetProfile(c.target)
+		if !found {
+			t.Fatalf("target profile '%s' not found", c.target)
+		}
+
+		size, known := NewLayout(target, types).SizeOf(c.typ)
+		if size != c.size || known != c.known {
+			t.Errorf("case %d: size on %s, expected (%d, %v), got (%d, %v)",
+				i, c.target, c.size, c.known, size, known)
+		}
+	}
+}
+
+func TestCheckSizeofCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Header struct {",
+		"    kind uint8",
+		"    length uint32",
+		"}",
+		"global var table uint16[sizeof(uint64) / 2] = uint16[]{1, 2}",
+		"fun main() {",
+		"    var buf uint8[sizeof(uint32) * 4]",
+		"    var p *Header = new Header()",
+		"    n := sizeof(Header) + sizeof(*p) + sizeof(buf[0])",
+		"    if n > 0 {",
+		"        buf[n] = 1",
+		"    }",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckSizeofErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"type Header struct {",
+				"    kind uint8",
+				"    length uint32",
+				"}",
+				"fun main() {",
+				"    var buf uint8[sizeof(Header)]",
+				"}",
+			},
+			[]string{
+				"test.mc:6:19: error: size is unknown for target 'portable' [array-size]",
+				"    6 |     var buf uint8[sizeof(Header)]",
+				"      |                   ^^^^^^^^^^^^^^",
+				"      |                   array size SHALL be known at compile time, select a target profile to evaluate it",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var buf uint8[sizeof(int32) - 4]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:19: error: invalid array size 0 [array-size]",
+				"    2 |     var buf uint8[sizeof(int32) - 4]",
+				"      |                   ^^^^^^^^^^^^^ ^ ^",
+				"      |                   SHALL be a positive integer constant",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 4",
+				"    m := sizeof(int32[n])",
+				"}",
+			},
+			[]string{
+				"test.mc:3:23: error: array size is not a constant [array-size]",
+				"    3 |     m := sizeof(int32[n])",
+				"      |                       ^",
+				"      |                       SHALL be a positive integer constant",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var buf uint8[sizeof(uint32) * 2]",
+				"    buf[8] = 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:9: error: invalid array index 8, out of bounds for 8-element array [index-out-of-bounds]",
+				"    3 |     buf[8] = 0",
+				"      |         ^",
+				"      |         SHALL be in range [0, 8)",
+				"test.mc:2:9: note: declared here",
+				"    2 |     var buf uint8[sizeof(uint32) * 2]",
+				"      |         ^^^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join(c.code, "\n")
+		expected := strings.Join(c.expected, "\n")
+		checkCodeError(t, code, expected)
+	}
+}
+
+func TestCheckSizeofOnTarget(t *testing.T) {
+	code := strings.Join([]string{
+		"type Header struct {",
+		"    kind uint8",
+		"    length uint32",
+		"    next *Header",
+		"}",
+		"fun main() {",
+		"    var buf uint8[sizeof(Header) * 2]",
+		"    buf[31] = 0",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	conf := NewDefaultCheckConfigure()
+	conf.Target, _ = LookupTargetProfile("x86_64")
+	container := NewCodeChecker(conf, doc).Check()
+	if container.Count(context.Error) > 0 {
+		t.Fatalf("code check expected to succeed, but got errors:\n%s", container.Error())
+	}
+
+	conf.Target, _ = LookupTargetProfile("i386")
+	container = NewCodeChecker(conf, doc).Check()
+	expected := strings.Join([]string{
+		"test.mc:8:9: error: invalid array index 31, out of bounds for 24-element array [index-out-of-bounds]",
+		"    8 |     buf[31] = 0",
+		"      |         ^^",
+		"      |         SHALL be in range [0, 24)",
+		"test.mc:7:9: note: declared here",
+		"    7 |     var buf uint8[sizeof(Header) * 2]",
+		"      |         ^^^",
+	}, "\n")
+
+	if container.Error() != expected {
+		t.Fatalf("code check error mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
+	}
+}
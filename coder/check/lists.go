@@ -0,0 +1,128 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+const (
+	BuiltinLength = "len"
+	BuiltinCap    = "cap"
+	BuiltinAppend = "append"
+	BuiltinRemove = "remove"
+)
+
+// builtinArguments are the minimum and maximum number of arguments of builtin functions, -1 for no maximum.
+var builtinArguments = map[string][2]int{
+	BuiltinLength: {1, 1},
+	BuiltinCap:    {1, 1},
+	BuiltinAppend: {2, -1},
+	BuiltinRemove: {2, 2},
+}
+
+// IsBuiltinFunction tells whether name is a function of lists and arrays provided by compiler.
+func IsBuiltinFunction(name string) bool {
+	_, found := builtinArguments[name]
+	return found
+}
+
+// BuiltinCall returns name of builtin function called by e.
+func BuiltinCall(e *ast.CallExpression) (string, bool) {
+	id, ok := e.Callee.(*ast.Identifier)
+	if !ok || !IsBuiltinFunction(id.Name) {
+		return "", false
+	}
+
+	return id.Name, true
+}
+
+// isList tells whether a variable declared in type t and initialized with value is a list.
+func isList(t ast.Type, value ast.Expression) bool {
+	if _, ok := t.(*ast.ListType); ok {
+		return true
+	}
+
+	if e, ok := value.(*ast.NewExpression); ok {
+		_, ok := e.ListLiteral()
+		return ok
+	}
+
+	return false
+}
+
+func checkNewExpression(scope *Scope, e *ast.NewExpression) context.DiagnosticInfo {
+	if l, ok := e.ListLiteral(); ok {
+		return checkArrayLiteral(scope, l, true)
+	}
+
+	if l, ok := e.StructLiteral(); ok {
+		return checkStructLiteral(scope, l)
+	}
+
+	if call, ok := e.Constructor(); ok {
+		if err := checkExpressionList(scope, call.Arguments); err != nil {
+			return err
+		}
+
+		return checkNewConstructor(call)
+	}
+
+	return checkExpression(scope, e.Value)
+}
+
+func checkFunctionBuiltinName(d *ast.FunctionDeclaration) context.DiagnosticInfo {
+	if name := d.FullName(); IsBuiltinFunction(name) {
+		return d.Name.Context().Error("cannot declare function '%s'", name).
+			With("'%s' is a builtin function", name).
+			ByRule(RuleBuiltinName, context.Error)
+	}
+
+	return nil
+}
+
+// checkBuiltinCall checks number of arguments of builtin functions, and lists resized by 'append' and 'remove'.
+func checkBuiltinCall(scope *Scope, e *ast.CallExpression) context.DiagnosticInfo {
+	name, ok := BuiltinCall(e)
+	if !ok {
+		return nil
+	}
+
+	count, limit := e.Arguments.Length(), builtinArguments[name]
+	if count < limit[0] || (limit[1] >= 0 && count > limit[1]) {
+		expect := "at least"
+		if limit[0] == limit[1] {
+			expect = "exactly"
+		}
+
+		return e.Context().Error("wrong number of arguments in call to '%s', expect %s %d, got %d", name, expect, limit[0], count).
+			With("SHALL be %s %d", expect, limit[0]).
+			ByRule(RuleArgumentCount, context.Error)
+	}
+
+	if name != BuiltinAppend && name != BuiltinRemove {
+		return nil
+	}
+
+	action := "append to"
+	if name == BuiltinRemove {
+		action = "remove from"
+	}
+
+	list := e.Arguments.Expressions[0].Expression
+	if !ast.IsAssignable(list) {
+		return list.Context().Error("cannot %s non-variable list", action).
+			With("expect a variable, dereference, member or element").
+			ByRule(RuleListResize, context.Error)
+	}
+
+	if id, ok := list.(*ast.Identifier); ok {
+		if symbol, found := scope.Lookup(id.Name); found && symbol.Length > 0 {
+			return list.Context().Error("cannot %s fixed size array '%s'", action, id.Name).
+				With("only lists are resizable").
+				ByRule(RuleListResize, context.Error).
+				For(symbol.Context.Note("declared here"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckListsCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Queue struct {",
+		"    items int32[]",
+		"}",
+		"",
+		"global var pending int32[]",
+		"",
+		"fun drain(q *Queue) with(pending int32[]) (int32) {",
+		"    var total int32 = 0",
+		"    l := new int32[4]{1, 2}",
+		"    append(l, 3, 4)",
+		"    append(q.items, len(l))",
+		"    remove(pending, 0)",
+		"    foreach (x in l) {",
+		"        total += x",
+		"    }",
+		"    empty := new int32[]{}",
+		"    return total + l[0] + len(empty) + cap(q.items)",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckListErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    l := new int32[]{1}",
+				"    return len(l, 1)",
+				"}",
+			},
+			[]string{
+				"test.mc:3:12: error: wrong number of arguments in call to 'len', expect exactly 1, got 2 [argument-count]",
+				"    3 |     return len(l, 1)",
+				"      |            ^^^^^^ ^^",
+				"      |            SHALL be exactly 1",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    l := new int32[]{1}",
+				"    append(l)",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:5: error: wrong number of arguments in call to 'append', expect at least 2, got 1 [argument-count]",
+				"    3 |     append(l)",
+				"      |     ^^^^^^^^^",
+				"      |     SHALL be at least 2",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    arr := int32[]{1, 2}",
+				"    append(arr, 3)",
+				"    return arr[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:3:12: error: cannot append to fixed size array 'arr' [list-resize]",
+				"    3 |     append(arr, 3)",
+				"      |            ^^^",
+				"      |            only lists are resizable",
+				"test.mc:2:5: note: declared here",
+				"    2 |     arr := int32[]{1, 2}",
+				"      |     ^^^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    remove(new int32[]{1}, 0)",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: cannot remove from non-variable list [list-resize]",
+				"    2 |     remove(new int32[]{1}, 0)",
+				"      |            ^^^ ^^^^^^^^^^",
+				"      |            expect a variable, dereference, member or element",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    var l int32[] = int32[]{1, 2}",
+				"    return l[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:21: error: cannot use array literal as list [array-literal]",
+				"    2 |     var l int32[] = int32[]{1, 2}",
+				"      |                     ^^^^^^^^^^ ^^",
+				"      |                     allocate list by 'new int32[]{...}'",
+			},
+		},
+		{
+			[]string{
+				"fun len(a int32) (int32) {",
+				"    return a",
+				"}",
+			},
+			[]string{
+				"test.mc:1:5: error: cannot declare function 'len' [builtin-name]",
+				"    1 | fun len(a int32) (int32) {",
+				"      |     ^^^",
+				"      |     'len' is a builtin function",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    l := new int32[2]{1, 2, 3}",
+				"    return l[0]",
+				"}",
+			},
+			[]string{
+				"test.mc:2:29: error: too many elements in array literal, expect at most 2, got 3 [array-literal]",
+				"    2 |     l := new int32[2]{1, 2, 3}",
+				"      |                             ^",
+				"      |                             array index 2 out of bounds",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
@@ -0,0 +1,122 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// declareImports declares imported modules in global scope, members of a module are referenced as 'module.name'.
+func declareImports(scope *Scope, imports map[string]*ast.Document, doc *ast.Document) context.DiagnosticInfo {
+	for _, decl := range doc.Declarations {
+		d, ok := decl.(*ast.ImportDeclaration)
+		if !ok {
+			continue
+		}
+
+		module, found := imports[d.Name.Name]
+		if !found {
+			return d.Name.Context().Error("module '%s' not found", d.Name.Name).
+				With("imported here").
+				ByRule(RuleUndefinedName, context.Error)
+		}
+
+		symbol, err := scope.Declare(d.Name, SymbolModule)
+		if err != nil {
+			return err
+		}
+
+		symbol.Module = module
+	}
+
+	return nil
+}
+
+// moduleOf returns the imported module if expression is in form of 'module.name'.
+func moduleOf(scope *Scope, e *ast.MemberExpression) (*Symbol, bool) {
+	id, ok := e.Object.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found || symbol.Kind != SymbolModule {
+		return nil, false
+	}
+
+	return symbol, true
+}
+
+// moduleMember finds a function or a global variable declared in module.
+func moduleMember(doc *ast.Document, name string) (ast.Declaration, *ast.Identifier, bool) {
+	for _, decl := range doc.Declarations {
+		switch d := decl.(type) {
+		case *ast.FunctionDeclaration:
+			if !d.IsMethod() && d.Name.Name == name {
+				return d, d.Name, d.IsExported()
+			}
+
+		case *ast.GlobalDeclaration:
+			if d.Variable.Name.Name == name {
+				return d, d.Variable.Name, d.IsExported()
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+func checkModuleMember(module *Symbol, e *ast.MemberExpression) (ast.Declaration, context.DiagnosticInfo) {
+	decl, name, exported := moduleMember(module.Module, e.Member.Name)
+	if decl == nil {
+		return nil, e.Member.Context().Error("undefined: '%s.%s'", module.Name, e.Member.Name).
+			With("not declared in module '%s'", module.Name).
+			ByRule(RuleUndefinedName, context.Error)
+	}
+
+	if !exported {
+		return nil, e.Member.Context().Error("'%s' is not exported by module '%s'", e.Member.Name, module.Name).
+			With("not exported").
+			ByRule(RuleUnexportedName, context.Error).
+			For(name.Context().Note("declared here"))
+	}
+
+	return decl, nil
+}
+
+// checkModuleCall checks number of arguments in call to a function of imported module.
+func checkModuleCall(scope *Scope, e *ast.CallExpression) context.DiagnosticInfo {
+	member, ok := e.Callee.(*ast.MemberExpression)
+	if !ok {
+		return nil
+	}
+
+	module, found := moduleOf(scope, member)
+	if !found {
+		return nil
+	}
+
+	decl, _, _ := moduleMember(module.Module, member.Member.Name)
+	f, ok := decl.(*ast.FunctionDeclaration)
+	if !ok {
+		return nil
+	}
+
+	expected, got := 0, 0
+	if f.Arguments != nil {
+		expected = len(f.Arguments.Arguments)
+	}
+
+	if e.Arguments != nil {
+		got = len(e.Arguments.Expressions)
+	}
+
+	if expected != got {
+		return e.Context().Error("wrong number of arguments in call to '%s.%s', expect %d, got %d",
+			module.Name, member.Member.Name, expected, got).
+			With("wrong number of arguments").
+			ByRule(RuleArgumentCount, context.Error).
+			For(f.Name.Context().Note("declared here"))
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/context"
+)
+
+var testModuleMath = strings.Join([]string{
+	"module math",
+	"export global var count int32 = 0",
+	"global var base int32 = 1",
+	"export fun add(a int32, b int32) (int32) {",
+	"    return a + b",
+	"}",
+	"fun inc(a int32) (int32) {",
+	"    return a + 1",
+	"}",
+}, "\n")
+
+func checkModuleCode(t *testing.T, source string) *context.DiagnosticContainer {
+	t.Helper()
+
+	doc := parseCode(t, source)
+	checker := NewCodeChecker(NewDefaultCheckConfigure(), doc)
+	checker.AddImport("math", parseCodeFile(t, testModuleMath, "math.mc"))
+	return checker.Check()
+}
+
+func TestCheckModuleCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"import math",
+		"fun main() {",
+		"    var x = math.add(1, 2)",
+		"    var c = math.count + x",
+		"}",
+	}, "\n")
+
+	container := checkModuleCode(t, code)
+	if container.Count(context.Error) > 0 {
+		t.Fatalf("code check expected to succeed, but got errors:\n%s", container.Error())
+	}
+}
+
+func TestCheckModuleErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"import math",
+				"import io",
+			},
+			[]string{
+				"test.mc:2:8: error: module 'io' not found [undefined-name]",
+				"    2 | import io",
+				"      |        ^^",
+				"      |        imported here",
+			},
+		},
+		{
+			[]string{
+				"import math",
+				"fun main() {",
+				"    math.sub(1, 2)",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: undefined: 'math.sub' [undefined-name]",
+				"    3 |     math.sub(1, 2)",
+				"      |          ^^^",
+				"      |          not declared in module 'math'",
+			},
+		},
+		{
+			[]string{
+				"import math",
+				"fun main() {",
+				"    var b = math.base",
+				"}",
+			},
+			[]string{
+				"test.mc:3:18: error: 'base' is not exported by module 'math' [unexported-name]",
+				"    3 |     var b = math.base",
+				"      |                  ^^^^",
+				"      |                  not exported",
+				"math.mc:3:12: note: declared here",
+				"    3 | global var base int32 = 1",
+				"      |            ^^^^",
+			},
+		},
+		{
+			[]string{
+				"import math",
+				"fun main() {",
+				"    math.add(1)",
+				"}",
+			},
+			[]string{
+				"test.mc:3:5: error: wrong number of arguments in call to 'math.add', expect 2, got 1 [argument-count]",
+				"    3 |     math.add(1)",
+				"      |     ^^^^^^^^^^^",
+				"      |     wrong number of arguments",
+				"math.mc:4:12: note: declared here",
+				"    4 | export fun add(a int32, b int32) (int32) {",
+				"      |            ^^^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		container := checkModuleCode(t, strings.Join(c.code, "\n"))
+		expected := strings.Join(c.expected, "\n")
+		if container.Error() != expected {
+			t.Errorf("code check error mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
+		}
+	}
+}
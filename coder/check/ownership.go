@@ -0,0 +1,699 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// isPointerType tells whether values in type t are pointers or lists, which may own data in heap.
+func isPointerType(t ast.Type) bool {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		return len(typ.PointerAsterisk) > 0
+
+	case *ast.ListType:
+		return true
+	}
+
+	return false
+}
+
+// isAllocation tells whether expr allocates new data in heap.
+func isAllocation(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.NewExpression:
+		return true
+
+	case *ast.ParenthesizedExpression:
+		return isAllocation(e.Expression)
+	}
+
+	return false
+}
+
+// isBorrowed tells whether expr is a pointer which SHALL NOT be owned, like address of variable '&n', pointer
+// shifted by pointer arithmetic and string literal in static storage.
+func isBorrowed(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.PrefixExpression:
+		return e.Operator.Token == ast.Ampersand
+
+	case *ast.ParenthesizedExpression:
+		return isBorrowed(e.Expression)
+
+	case *ast.PointerArithmeticExpression, *ast.StringLiteral:
+		return true
+	}
+
+	return false
+}
+
+// valueOwnership returns ownership of a pointer initialized with value, references are copied as references.
+func valueOwnership(scope *Scope, t ast.Type, value ast.Expression) Ownership {
+	switch e := value.(type) {
+	case *ast.NewExpression:
+		return OwnershipOwner
+
+	case *ast.ParenthesizedExpression:
+		return valueOwnership(scope, t, e.Expression)
+
+	case *ast.PrefixExpression:
+		if e.Operator.Token == ast.Ref {
+			return OwnershipReference
+		}
+
+	case *ast.Identifier:
+		if symbol, found := scope.Lookup(e.Name); found && symbol.Owner != OwnershipNone {
+			return symbol.Owner
+		}
+
+	case *ast.CallExpression:
+		if owner := callOwnership(scope, e); owner != OwnershipNone {
+			return owner
+		}
+	}
+
+	if isPointerType(t) {
+		return OwnershipOwner
+	}
+
+	return OwnershipNone
+}
+
+// callOwnership returns ownership of pointer returned by call, which is owned only if the function called returns
+// owned data on all paths. Pointers returned by C functions are not known to be owned.
+func callOwnership(scope *Scope, call *ast.CallExpression) Ownership {
+	t, err := typeOf(scope, call)
+	if err != nil || !isPointerType(t) {
+		return OwnershipNone
+	}
+
+	return resultOwnership(scope, call)
+}
+
+// resultOwnership returns ownership of pointers returned by call, which may return multiple values.
+func resultOwnership(scope *Scope, call *ast.CallExpression) Ownership {
+	f, found, _ := calledFunction(scope, call)
+	if found && returnsOwned(scope.Root().Functions, f, make(map[*ast.FunctionDeclaration]bool)) {
+		return OwnershipOwner
+	}
+
+	return OwnershipReference
+}
+
+// checkResultsOwnership checks pointers returned by call to multiple variables declared, which SHALL be references
+// if the function does not return owned data.
+func checkResultsOwnership(scope *Scope, call *ast.CallExpression, names []*ast.Identifier, types []ast.Type) context.DiagnosticInfo {
+	if resultOwnership(scope, call) == OwnershipOwner {
+		return nil
+	}
+
+	for i, name := range names {
+		if i < len(types) && isPointerType(types[i]) && !name.IsDummy() {
+			return call.Context().Error("cannot take ownership of pointer returned by function").
+				With("declare '%s' with 'ref', the function does not return owned data", name.Name).
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+	}
+
+	return nil
+}
+
+// checkAssignResultsOwnership checks pointers returned by call to multiple targets, owners and fields SHALL NOT be
+// assigned if the function does not return owned data.
+func checkAssignResultsOwnership(scope *Scope, call *ast.CallExpression, targets []ast.Expression, types []ast.Type) context.DiagnosticInfo {
+	if resultOwnership(scope, call) == OwnershipOwner {
+		return nil
+	}
+
+	for i, target := range targets {
+		if i >= len(types) || !isPointerType(types[i]) {
+			continue
+		}
+
+		switch e := target.(type) {
+		case *ast.Identifier:
+			if symbol, found := scope.Lookup(e.Name); found && symbol.Owner == OwnershipOwner {
+				return call.Context().Error("cannot assign borrowed pointer to owner '%s'", e.Name).
+					With("'%s' is not a reference, the function does not return owned data", e.Name).
+					ByRule(RuleOwnershipTransfer, context.Error).
+					For(symbol.Context.Note("declared here"))
+			}
+
+		case *ast.MemberExpression:
+			return call.Context().Error("cannot assign borrowed pointer to field '%s'", e.Member.Name).
+				With("fields own their values, the function does not return owned data").
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+	}
+
+	return nil
+}
+
+// localDeclaration is a local variable declared in function, value is nil if it is declared without value, and
+// known is false if its value is not known, like variable of foreach and values returned by a call.
+type localDeclaration struct {
+	ref   bool
+	known bool
+	value ast.Expression
+}
+
+// localDeclarations returns declarations of local variables in function by name, names may be declared more than
+// once in nested blocks.
+func localDeclarations(f *ast.FunctionDeclaration) map[string][]localDeclaration {
+	locals := make(map[string][]localDeclaration)
+	var walk func(stmts []ast.Statement)
+	walk = func(stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			switch s := stmt.(type) {
+			case *ast.VariableDeclaration:
+				locals[s.Name.Name] = append(locals[s.Name.Name], localDeclaration{s.IsRef(), true, s.Value})
+
+			case *ast.InferenceDeclaration:
+				names, values := s.Identifiers(), s.Values.Length()
+				for i, name := range names {
+					decl := localDeclaration{ref: s.Ref != nil, known: len(names) == values}
+					if decl.known {
+						decl.value = s.Values.Expressions[i].Expression
+					}
+
+					locals[name.Name] = append(locals[name.Name], decl)
+				}
+
+			case *ast.ForStatement:
+				if s.Initializer != nil {
+					walk([]ast.Statement{s.Initializer})
+				}
+
+			case *ast.ForeachStatement:
+				locals[s.Variable.Name] = append(locals[s.Variable.Name], localDeclaration{})
+			}
+
+			for _, body := range statementBodies(stmt) {
+				walk(body)
+			}
+		}
+	}
+
+	walk(f.Statements)
+	return locals
+}
+
+// returnsOwned tells whether pointers returned by f are owned data on all paths, which are new allocations, null,
+// owners declared in f and results of functions returning owned data. Arguments, globals and references are
+// borrowed. A function being analysed is assumed to return owned data in recursive calls.
+func returnsOwned(functions map[string]*ast.FunctionDeclaration, f *ast.FunctionDeclaration, visiting map[*ast.FunctionDeclaration]bool) bool {
+	if visiting[f] {
+		return true
+	}
+
+	visiting[f] = true
+	if functions[f.FullName()] != f {
+		// calls in functions of imported modules are not resolved
+		functions = nil
+	}
+
+	locals := localDeclarations(f)
+	var owned func(expr ast.Expression) bool
+	owned = func(expr ast.Expression) bool {
+		switch e := expr.(type) {
+		case *ast.NewExpression, *ast.NullLiteral:
+			return true
+
+		case *ast.ParenthesizedExpression:
+			return owned(e.Expression)
+
+		case *ast.CallExpression:
+			id, ok := e.Callee.(*ast.Identifier)
+			if !ok {
+				return false
+			}
+
+			callee, found := functions[id.Name]
+			return found && returnsOwned(functions, callee, visiting)
+
+		case *ast.Identifier:
+			decls, found := locals[e.Name]
+			if !found {
+				return false
+			}
+
+			for _, decl := range decls {
+				switch decl.value.(type) {
+				case *ast.MemberExpression, *ast.IndexExpression:
+					// pointers read from fields and elements are moved out of them
+					continue
+				}
+
+				if decl.ref || !decl.known || (decl.value != nil && !owned(decl.value)) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		return false
+	}
+
+	for _, ret := range BuildFlowGraph(f).Returns {
+		if ret.Value == nil || f.ReturnTypes == nil {
+			continue
+		}
+
+		for i, item := range ret.Value.Expressions {
+			if i < f.ReturnTypes.Length() && isPointerType(f.ReturnTypes.Types[i].Type) && !owned(item.Expression) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// globalOwner returns the owning global variable named by value, which SHALL NOT be owned by locals. Locals are
+// freed when function returns, while the global still points to the value.
+func globalOwner(scope *Scope, value ast.Expression) (*Symbol, bool) {
+	switch e := value.(type) {
+	case *ast.ParenthesizedExpression:
+		return globalOwner(scope, e.Expression)
+
+	case *ast.Identifier:
+		symbol, found := scope.Lookup(e.Name)
+		if found && symbol.Owner == OwnershipOwner && (symbol.Kind == SymbolGlobal || symbol.Global != nil) {
+			return symbol, true
+		}
+	}
+
+	return nil, false
+}
+
+// argumentOwnership returns ownership of pointer arguments, which are references borrowed from caller.
+func argumentOwnership(t ast.Type) Ownership {
+	if isPointerType(t) {
+		return OwnershipReference
+	}
+
+	return OwnershipNone
+}
+
+// checkDeclarationOwnership checks pointer declared with value, and returns its ownership. Heap data SHALL be owned
+// by an owning pointer, and borrowed pointers SHALL be references.
+func checkDeclarationOwnership(scope *Scope, name *ast.Identifier, ref *ast.TerminalToken, t ast.Type, value ast.Expression) (Ownership, context.DiagnosticInfo) {
+	if ref == nil {
+		if value != nil && isBorrowed(value) {
+			return OwnershipNone, value.Context().Error("cannot take ownership of borrowed pointer").
+				With("declare '%s' with 'ref'", name.Name).
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+
+		if global, ok := globalOwner(scope, value); ok {
+			return OwnershipNone, value.Context().Error("cannot take ownership of global variable '%s'", global.Name).
+				With("declare '%s' with 'ref'", name.Name).
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+
+		if call, ok := value.(*ast.CallExpression); ok && callOwnership(scope, call) == OwnershipReference {
+			return OwnershipNone, value.Context().Error("cannot take ownership of pointer returned by function").
+				With("declare '%s' with 'ref', the function does not return owned data", name.Name).
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+
+		return valueOwnership(scope, t, value), nil
+	}
+
+	if st, ok := t.(*ast.SimpleType); ok && len(st.PointerAsterisk) == 0 {
+		return OwnershipNone, t.Context().Error("invalid type of reference '%s'", name.Name).
+			With("SHALL be a pointer").
+			ByRule(RuleReferenceType, context.Error)
+	}
+
+	if value != nil && isAllocation(value) {
+		return OwnershipNone, value.Context().Error("cannot reference new allocation").
+			With("heap data SHALL be owned, remove 'ref'").
+			ByRule(RuleOwnershipTransfer, context.Error).
+			For(ref.Context().Note("declared as reference here"))
+	}
+
+	return OwnershipReference, nil
+}
+
+// checkAssignOwnership checks ownership of pointer assigned to a variable or field, owners and fields SHALL NOT be
+// assigned with borrowed pointers, and references SHALL NOT be assigned with new allocations.
+func checkAssignOwnership(scope *Scope, target ast.Expression, value ast.Expression) context.DiagnosticInfo {
+	if member, ok := target.(*ast.MemberExpression); ok {
+		// fields own their values, which are freed with the structure
+		if isBorrowed(value) || valueOwnership(scope, nil, value) == OwnershipReference {
+			return value.Context().Error("cannot assign borrowed pointer to field '%s'", member.Member.Name).
+				With("fields own their values").
+				ByRule(RuleOwnershipTransfer, context.Error)
+		}
+
+		return nil
+	}
+
+	id, ok := target.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found {
+		return nil
+	}
+
+	switch symbol.Owner {
+	case OwnershipOwner:
+		if global, ok := globalOwner(scope, value); ok && symbol.Kind != SymbolGlobal && symbol.Global == nil {
+			return value.Context().Error("cannot assign global variable '%s' to owner '%s'", global.Name, id.Name).
+				With("'%s' is freed when function returns", id.Name).
+				ByRule(RuleOwnershipTransfer, context.Error).
+				For(symbol.Context.Note("declared here"))
+		}
+
+		if isBorrowed(value) || valueOwnership(scope, nil, value) == OwnershipReference {
+			return value.Context().Error("cannot assign borrowed pointer to owner '%s'", id.Name).
+				With("'%s' is not a reference", id.Name).
+				ByRule(RuleOwnershipTransfer, context.Error).
+				For(symbol.Context.Note("declared here"))
+		}
+
+	case OwnershipReference:
+		if isAllocation(value) {
+			return value.Context().Error("cannot assign new allocation to reference '%s'", id.Name).
+				With("a reference can not own heap data").
+				ByRule(RuleOwnershipTransfer, context.Error).
+				For(symbol.Context.Note("declared as reference here"))
+		}
+	}
+
+	return nil
+}
+
+// checkNewConstructor checks 'new T(...)', which is initialized with at most one value.
+func checkNewConstructor(call *ast.CallExpression) context.DiagnosticInfo {
+	if count := call.Arguments.Length(); count > 1 {
+		return call.Arguments.Context().Error("wrong number of arguments in new '%s', expect at most 1, got %d",
+			call.Callee.(*ast.Identifier).Name, count).
+			With("SHALL be at most 1").
+			ByRule(RuleArgumentCount, context.Error)
+	}
+
+	return nil
+}
+
+// checkDeleteStatement checks target of delete is an owning pointer, which is not deleted yet.
+func checkDeleteStatement(scope *Scope, s *ast.DeleteStatement) context.DiagnosticInfo {
+	if symbol, ok := ownedSymbol(scope, s.Target); ok && symbol.State == OwnerDeleted {
+		return s.Target.Context().Error("double delete of '%s'", symbol.Name).
+			With("already deleted").
+			ByRule(RuleDoubleDelete, context.Error).
+			For(symbol.StateAt.Note("first deleted here"))
+	}
+
+	if err := checkExpression(scope, s.Target); err != nil {
+		return err
+	}
+
+	if symbol, ok := ownedSymbol(scope, s.Target); ok {
+		symbol.State, symbol.StateAt = OwnerDeleted, s.Context()
+		return nil
+	}
+
+	id, ok := s.Target.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found {
+		return nil
+	}
+
+	if symbol.Owner == OwnershipReference {
+		return id.Context().Error("cannot delete reference '%s'", id.Name).
+			With("a reference does not own the value").
+			ByRule(RuleInvalidDelete, context.Error).
+			For(symbol.Context.Note("declared as reference here"))
+	}
+
+	if symbol.Owner == OwnershipNone && (symbol.Length > 0 || (symbol.Type != nil && !isPointerType(symbol.Type))) {
+		return id.Context().Error("cannot delete non-pointer '%s'", id.Name).
+			With("SHALL be an owning pointer").
+			ByRule(RuleInvalidDelete, context.Error).
+			For(symbol.Context.Note("declared here"))
+	}
+
+	return nil
+}
+
+// ownedSymbol returns the owning pointer named by expr, whose state is tracked through statements.
+func ownedSymbol(scope *Scope, expr ast.Expression) (*Symbol, bool) {
+	id, ok := expr.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found || symbol.Kind == SymbolGlobal || symbol.Owner != OwnershipOwner {
+		return nil, false
+	}
+
+	return symbol, true
+}
+
+// movedOwners returns owning pointers moved by a value assigned to an owner, values of fields in struct literal are
+// moved into the new structure.
+func movedOwners(scope *Scope, value ast.Expression) []*ast.Identifier {
+	switch e := value.(type) {
+	case *ast.Identifier:
+		if _, ok := ownedSymbol(scope, e); ok {
+			return []*ast.Identifier{e}
+		}
+
+	case *ast.ParenthesizedExpression:
+		return movedOwners(scope, e.Expression)
+
+	case *ast.NewExpression:
+		literal, ok := e.StructLiteral()
+		if !ok {
+			return nil
+		}
+
+		var result []*ast.Identifier
+		for _, field := range literal.Fields {
+			result = append(result, movedOwners(scope, field.Value)...)
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+func markMoved(scope *Scope, moved []*ast.Identifier) {
+	for _, id := range moved {
+		if symbol, ok := ownedSymbol(scope, id); ok {
+			symbol.State, symbol.StateAt = OwnerMoved, id.Context()
+		}
+	}
+}
+
+// declareOwnership moves owning pointers in value to a new owner, and returns the local variable a new reference
+// points into.
+func declareOwnership(scope *Scope, owner Ownership, value ast.Expression) *Symbol {
+	if value == nil {
+		return nil
+	}
+
+	if owner == OwnershipReference {
+		return localReferent(scope, value)
+	}
+
+	markMoved(scope, movedOwners(scope, value))
+	return nil
+}
+
+// assignOwnership moves owning pointers assigned to owners or fields, and owners assigned own values again.
+func assignOwnership(scope *Scope, s *ast.AssignmentStatement) {
+	if s.Operator.Token != ast.Assign {
+		return
+	}
+
+	for i, target := range s.Targets.Expressions {
+		if i >= s.Values.Length() {
+			// values returned by call are not known
+			break
+		}
+
+		value := s.Values.Expressions[i].Expression
+		if id, ok := target.Expression.(*ast.Identifier); ok {
+			if symbol, found := scope.Lookup(id.Name); found && symbol.Owner == OwnershipReference {
+				symbol.Referent = localReferent(scope, value)
+				continue
+			}
+		}
+
+		markMoved(scope, movedOwners(scope, value))
+	}
+
+	for _, target := range s.Targets.Expressions {
+		if symbol, ok := ownedSymbol(scope, target.Expression); ok {
+			symbol.State, symbol.StateAt = OwnerLive, nil
+		}
+	}
+}
+
+// localReferent returns the local variable a pointer points into, or nil if it points to data of caller, globals or
+// heap data not owned by the function. Owning pointers are freed when function returns, so they are local too.
+func localReferent(scope *Scope, value ast.Expression) *Symbol {
+	switch e := value.(type) {
+	case *ast.ParenthesizedExpression:
+		return localReferent(scope, e.Expression)
+
+	case *ast.PointerArithmeticExpression:
+		return localReferent(scope, e.Pointer)
+
+	case *ast.Identifier:
+		if symbol, ok := ownedSymbol(scope, e); ok && symbol.Global == nil {
+			return symbol
+		}
+
+		if symbol, found := scope.Lookup(e.Name); found && symbol.Owner == OwnershipReference {
+			return symbol.Referent
+		}
+
+	case *ast.PrefixExpression:
+		switch e.Operator.Token {
+		case ast.Ref:
+			return localReferent(scope, e.Operand)
+
+		case ast.Ampersand:
+			root := ast.RootIdentifier(e.Operand)
+			if root == nil {
+				return nil
+			}
+
+			symbol, found := scope.Lookup(root.Name)
+			if !found || symbol.Kind == SymbolGlobal || symbol.Kind == SymbolModule || symbol.Global != nil {
+				return nil
+			}
+
+			if symbol.Owner == OwnershipReference {
+				return symbol.Referent
+			}
+
+			return symbol
+		}
+	}
+
+	return nil
+}
+
+// checkOwnerState checks an owning pointer is not used after it is moved or deleted.
+func checkOwnerState(scope *Scope, id *ast.Identifier) context.DiagnosticInfo {
+	symbol, ok := ownedSymbol(scope, id)
+	if !ok {
+		return nil
+	}
+
+	switch symbol.State {
+	case OwnerMoved:
+		return id.Context().Error("use of moved pointer '%s'", id.Name).
+			With("ownership is moved, and '%s' is null", id.Name).
+			ByRule(RuleUseAfterMove, context.Error).
+			For(symbol.StateAt.Note("moved here"))
+
+	case OwnerDeleted:
+		return id.Context().Error("use of deleted pointer '%s'", id.Name).
+			With("value is freed, and '%s' is null", id.Name).
+			ByRule(RuleUseAfterDelete, context.Error).
+			For(symbol.StateAt.Note("deleted here"))
+	}
+
+	return nil
+}
+
+// checkReturnOwnership checks references returned do not point to local data. Owning pointers returned are moved to
+// the caller, and are not freed.
+func checkReturnOwnership(scope *Scope, s *ast.ReturnStatement) context.DiagnosticInfo {
+	if s.Value == nil {
+		return nil
+	}
+
+	for _, item := range s.Value.Expressions {
+		value := item.Expression
+		if _, ok := ownedSymbol(scope, value); ok {
+			continue
+		}
+
+		if local := localReferent(scope, value); local != nil {
+			return value.Context().Error("cannot return reference to local '%s'", local.Name).
+				With("'%s' does not live after function returns", local.Name).
+				ByRule(RuleDanglingReference, context.Error).
+				For(local.Context.Note("declared here"))
+		}
+	}
+
+	return nil
+}
+
+type ownerState struct {
+	state OwnerState
+	at    *context.Context
+}
+
+// saveOwnerStates returns states of owning pointers visible in scope, to be restored or merged after branches.
+func saveOwnerStates(scope *Scope) map[*Symbol]ownerState {
+	states := make(map[*Symbol]ownerState)
+	for s := scope; s != nil; s = s.Parent {
+		for _, symbol := range s.Symbols {
+			if symbol.Kind != SymbolGlobal && symbol.Owner == OwnershipOwner {
+				states[symbol] = ownerState{symbol.State, symbol.StateAt}
+			}
+		}
+	}
+
+	return states
+}
+
+func restoreOwnerStates(states map[*Symbol]ownerState) {
+	for symbol, s := range states {
+		symbol.State, symbol.StateAt = s.state, s.at
+	}
+}
+
+// mergeOwnerStates joins states at the end of branches, a pointer is moved or deleted only if it is in all branches
+// reaching the join point, and the state of the first branch is kept. Pointers are considered live if they may be,
+// to avoid false errors.
+func mergeOwnerStates(before map[*Symbol]ownerState, branches []map[*Symbol]ownerState) {
+	if len(branches) == 0 {
+		restoreOwnerStates(before)
+		return
+	}
+
+	for symbol := range before {
+		merged := branches[0][symbol]
+		for _, branch := range branches[1:] {
+			if branch[symbol].state == OwnerLive {
+				merged = ownerState{OwnerLive, nil}
+			}
+		}
+
+		symbol.State, symbol.StateAt = merged.state, merged.at
+	}
+}
+
+// IsTerminated tells whether statements end with return, break or continue, which never reach the end of block.
+func IsTerminated(stmts []ast.Statement) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+
+	switch stmts[len(stmts)-1].(type) {
+	case *ast.ReturnStatement, *ast.LoopControlStatement:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,577 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckOwnershipCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Node struct {",
+		"    next *Node",
+		"}",
+		"",
+		"fun link(a *Node) {",
+		"    ref head := a",
+		"    b := new Node()",
+		"    head.next = b",
+		"}",
+		"",
+		"fun pick(p *int32) (int32, *int32) {",
+		"    return *p, p",
+		"}",
+		"",
+		"fun make() (int32, *int32) {",
+		"    return 1, new int32(2)",
+		"}",
+		"",
+		"fun main() {",
+		"    var n int32 = 5",
+		"    ref s := \"yo\"",
+		"    ref m, q1 := pick(&n)",
+		"    k, q2 := make()",
+		"    k, q2 = make()",
+		"    var ref p1 *int32 = &n",
+		"    var ref p2 *int32 = p1",
+		"    var p3 *int32 = new int32(5)",
+		"    ref r := &n",
+		"    r = p3",
+		"    d := ref p3",
+		"    a := new Node()",
+		"    ref b *Node = a.next",
+		"    l := new int32[]{1, 2}",
+		"    delete p3",
+		"    delete a",
+		"    delete l",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckOwnershipErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    var p *int32 = &n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:20: error: cannot take ownership of borrowed pointer [ownership-transfer]",
+				"    3 |     var p *int32 = &n",
+				"      |                    ^^",
+				"      |                    declare 'p' with 'ref'",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    c := &n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: cannot take ownership of borrowed pointer [ownership-transfer]",
+				"    3 |     c := &n",
+				"      |          ^^",
+				"      |          declare 'c' with 'ref'",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var ref p *int32 = new int32(5)",
+				"}",
+			},
+			[]string{
+				"test.mc:2:24: error: cannot reference new allocation [ownership-transfer]",
+				"    2 |     var ref p *int32 = new int32(5)",
+				"      |                        ^^^ ^^^^^^^^",
+				"      |                        heap data SHALL be owned, remove 'ref'",
+				"test.mc:2:9: note: declared as reference here",
+				"    2 |     var ref p *int32 = new int32(5)",
+				"      |         ^^^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    ref p := new int32(5)",
+				"}",
+			},
+			[]string{
+				"test.mc:2:14: error: cannot reference new allocation [ownership-transfer]",
+				"    2 |     ref p := new int32(5)",
+				"      |              ^^^ ^^^^^^^^",
+				"      |              heap data SHALL be owned, remove 'ref'",
+				"test.mc:2:5: note: declared as reference here",
+				"    2 |     ref p := new int32(5)",
+				"      |     ^^^",
+			},
+		},
+		{
+			[]string{
+				"fun pick(p *int32) (*int32) {",
+				"    return p",
+				"}",
+				"",
+				"fun main() {",
+				"    var x int32 = 3",
+				"    var q *int32 = pick(&x)",
+				"}",
+			},
+			[]string{
+				"test.mc:7:20: error: cannot take ownership of pointer returned by function [ownership-transfer]",
+				"    7 |     var q *int32 = pick(&x)",
+				"      |                    ^^^^^^^^",
+				"      |                    declare 'q' with 'ref', the function does not return owned data",
+			},
+		},
+		{
+			[]string{
+				"global var gp *int32",
+				"",
+				"fun main() with(gp *int32) {",
+				"    var p *int32 = gp",
+				"}",
+			},
+			[]string{
+				"test.mc:4:20: error: cannot take ownership of global variable 'gp' [ownership-transfer]",
+				"    4 |     var p *int32 = gp",
+				"      |                    ^^",
+				"      |                    declare 'p' with 'ref'",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var ref n int32 = 5",
+				"}",
+			},
+			[]string{
+				"test.mc:2:15: error: invalid type of reference 'n' [reference-type]",
+				"    2 |     var ref n int32 = 5",
+				"      |               ^^^^^",
+				"      |               SHALL be a pointer",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    p := new int32(1)",
+				"    p = &n",
+				"}",
+			},
+			[]string{
+				"test.mc:4:9: error: cannot assign borrowed pointer to owner 'p' [ownership-transfer]",
+				"    4 |     p = &n",
+				"      |         ^^",
+				"      |         'p' is not a reference",
+				"test.mc:3:5: note: declared here",
+				"    3 |     p := new int32(1)",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    ref r := &n",
+				"    r = new int32(1)",
+				"}",
+			},
+			[]string{
+				"test.mc:4:9: error: cannot assign new allocation to reference 'r' [ownership-transfer]",
+				"    4 |     r = new int32(1)",
+				"      |         ^^^ ^^^^^^^^",
+				"      |         a reference can not own heap data",
+				"test.mc:3:9: note: declared as reference here",
+				"    3 |     ref r := &n",
+				"      |         ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    ref r := &n",
+				"    delete r",
+				"}",
+			},
+			[]string{
+				"test.mc:4:12: error: cannot delete reference 'r' [invalid-delete]",
+				"    4 |     delete r",
+				"      |            ^",
+				"      |            a reference does not own the value",
+				"test.mc:3:9: note: declared as reference here",
+				"    3 |     ref r := &n",
+				"      |         ^",
+			},
+		},
+		{
+			[]string{
+				"fun free(node *Node) {",
+				"    delete node",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: cannot delete reference 'node' [invalid-delete]",
+				"    2 |     delete node",
+				"      |            ^^^^",
+				"      |            a reference does not own the value",
+				"test.mc:1:10: note: declared as reference here",
+				"    1 | fun free(node *Node) {",
+				"      |          ^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var n int32 = 5",
+				"    delete n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:12: error: cannot delete non-pointer 'n' [invalid-delete]",
+				"    3 |     delete n",
+				"      |            ^",
+				"      |            SHALL be an owning pointer",
+				"test.mc:2:9: note: declared here",
+				"    2 |     var n int32 = 5",
+				"      |         ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    p := new int32(1, 2)",
+				"}",
+			},
+			[]string{
+				"test.mc:2:20: error: wrong number of arguments in new 'int32', expect at most 1, got 2 [argument-count]",
+				"    2 |     p := new int32(1, 2)",
+				"      |                    ^^ ^",
+				"      |                    SHALL be at most 1",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    p := new int32(1)",
+				"    q := ref p",
+				"    p = q",
+				"}",
+			},
+			[]string{
+				"test.mc:4:9: error: cannot assign borrowed pointer to owner 'p' [ownership-transfer]",
+				"    4 |     p = q",
+				"      |         ^",
+				"      |         'p' is not a reference",
+				"test.mc:2:5: note: declared here",
+				"    2 |     p := new int32(1)",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    t := \"yo\"",
+				"}",
+			},
+			[]string{
+				"test.mc:2:10: error: cannot take ownership of borrowed pointer [ownership-transfer]",
+				"    2 |     t := \"yo\"",
+				"      |          ^^^^",
+				"      |          declare 't' with 'ref'",
+			},
+		},
+		{
+			[]string{
+				"type Box struct {",
+				"    name *uint8",
+				"}",
+				"",
+				"fun main() {",
+				"    var b Box",
+				"    b.name = \"box\"",
+				"}",
+			},
+			[]string{
+				"test.mc:7:14: error: cannot assign borrowed pointer to field 'name' [ownership-transfer]",
+				"    7 |     b.name = \"box\"",
+				"      |              ^^^^^",
+				"      |              fields own their values",
+			},
+		},
+		{
+			[]string{
+				"fun pick(p *int32) (int32, *int32) {",
+				"    return *p, p",
+				"}",
+				"",
+				"fun main() {",
+				"    var x int32 = 3",
+				"    n, q := pick(&x)",
+				"}",
+			},
+			[]string{
+				"test.mc:7:13: error: cannot take ownership of pointer returned by function [ownership-transfer]",
+				"    7 |     n, q := pick(&x)",
+				"      |             ^^^^^^^^",
+				"      |             declare 'q' with 'ref', the function does not return owned data",
+			},
+		},
+		{
+			[]string{
+				"fun pick(p *int32) (int32, *int32) {",
+				"    return *p, p",
+				"}",
+				"",
+				"fun main() {",
+				"    var x int32 = 3",
+				"    var n int32",
+				"    q := new int32(1)",
+				"    n, q = pick(&x)",
+				"}",
+			},
+			[]string{
+				"test.mc:9:12: error: cannot assign borrowed pointer to owner 'q' [ownership-transfer]",
+				"    9 |     n, q = pick(&x)",
+				"      |            ^^^^^^^^",
+				"      |            'q' is not a reference, the function does not return owned data",
+				"test.mc:8:5: note: declared here",
+				"    8 |     q := new int32(1)",
+				"      |     ^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
+
+func TestCheckOwnerMovesCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Node struct {",
+		"    next *Node",
+		"}",
+		"",
+		"fun first(list *Node) (*Node) {",
+		"    ref r := list",
+		"    return r",
+		"}",
+		"",
+		"fun make() (*Node) {",
+		"    a := new Node()",
+		"    return a",
+		"}",
+		"",
+		"fun main(c bool) {",
+		"    a := new Node()",
+		"    b := new Node()",
+		"    a.next = b",
+		"    b = new Node()",
+		"    if (c) {",
+		"        delete b",
+		"    }",
+		"    b.next = new Node()",
+		"    p := new int32(1)",
+		"    for (i := 0; i < 3; i++) {",
+		"        q := p",
+		"        p = new int32(i)",
+		"    }",
+		"    delete p",
+		"    p = new int32(2)",
+		"    delete p",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckOwnerMovesErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun main() (int) {",
+				"    p := new int32(5)",
+				"    q := p",
+				"    return *p",
+				"}",
+			},
+			[]string{
+				"test.mc:4:13: error: use of moved pointer 'p' [use-after-move]",
+				"    4 |     return *p",
+				"      |             ^",
+				"      |             ownership is moved, and 'p' is null",
+				"test.mc:3:10: note: moved here",
+				"    3 |     q := p",
+				"      |          ^",
+			},
+		},
+		{
+			[]string{
+				"type Node struct {",
+				"    next *Node",
+				"}",
+				"",
+				"fun main() {",
+				"    a := new Node()",
+				"    b := new Node()",
+				"    a.next = b",
+				"    b.next = a",
+				"}",
+			},
+			[]string{
+				"test.mc:9:5: error: use of moved pointer 'b' [use-after-move]",
+				"    9 |     b.next = a",
+				"      |     ^",
+				"      |     ownership is moved, and 'b' is null",
+				"test.mc:8:14: note: moved here",
+				"    8 |     a.next = b",
+				"      |              ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    p := new int32(5)",
+				"    delete p",
+				"    delete p",
+				"}",
+			},
+			[]string{
+				"test.mc:4:12: error: double delete of 'p' [double-delete]",
+				"    4 |     delete p",
+				"      |            ^",
+				"      |            already deleted",
+				"test.mc:3:5: note: first deleted here",
+				"    3 |     delete p",
+				"      |     ^^^^^^ ^",
+			},
+		},
+		{
+			[]string{
+				"fun main() (int32) {",
+				"    p := new int32(5)",
+				"    delete p",
+				"    return *p",
+				"}",
+			},
+			[]string{
+				"test.mc:4:13: error: use of deleted pointer 'p' [use-after-delete]",
+				"    4 |     return *p",
+				"      |             ^",
+				"      |             value is freed, and 'p' is null",
+				"test.mc:3:5: note: deleted here",
+				"    3 |     delete p",
+				"      |     ^^^^^^ ^",
+			},
+		},
+		{
+			[]string{
+				"fun main(c bool) (int32) {",
+				"    p := new int32(5)",
+				"    if (c) {",
+				"        delete p",
+				"    } else {",
+				"        q := p",
+				"    }",
+				"    return *p",
+				"}",
+			},
+			[]string{
+				"test.mc:8:13: error: use of deleted pointer 'p' [use-after-delete]",
+				"    8 |     return *p",
+				"      |             ^",
+				"      |             value is freed, and 'p' is null",
+				"test.mc:4:9: note: deleted here",
+				"    4 |         delete p",
+				"      |         ^^^^^^ ^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (*int32) {",
+				"    n := int32(5)",
+				"    return &n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:12: error: cannot return reference to local 'n' [dangling-reference]",
+				"    3 |     return &n",
+				"      |            ^^",
+				"      |            'n' does not live after function returns",
+				"test.mc:2:5: note: declared here",
+				"    2 |     n := int32(5)",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (*int32) {",
+				"    n := int32(5)",
+				"    ref r := &n",
+				"    return r",
+				"}",
+			},
+			[]string{
+				"test.mc:4:12: error: cannot return reference to local 'n' [dangling-reference]",
+				"    4 |     return r",
+				"      |            ^",
+				"      |            'n' does not live after function returns",
+				"test.mc:2:5: note: declared here",
+				"    2 |     n := int32(5)",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (*int32) {",
+				"    p := new int32(5)",
+				"    return ref p",
+				"}",
+			},
+			[]string{
+				"test.mc:3:12: error: cannot return reference to local 'p' [dangling-reference]",
+				"    3 |     return ref p",
+				"      |            ^^^ ^",
+				"      |            'p' does not live after function returns",
+				"test.mc:2:5: note: declared here",
+				"    2 |     p := new int32(5)",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"type Node struct {",
+				"    next *Node",
+				"}",
+				"",
+				"fun link(a *Node, b *Node) {",
+				"    a.next = b",
+				"}",
+			},
+			[]string{
+				"test.mc:6:14: error: cannot assign borrowed pointer to field 'next' [ownership-transfer]",
+				"    6 |     a.next = b",
+				"      |              ^",
+				"      |              fields own their values",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
@@ -0,0 +1,43 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// checkPointerArithmetic checks operand of pointer arithmetic SHALL be a pointer, arrays and lists are rejected
+// since they are not pointers in magi-c.
+func checkPointerArithmetic(scope *Scope, e *ast.PointerArithmeticExpression) context.DiagnosticInfo {
+	id, ok := e.Pointer.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(id.Name)
+	if !found {
+		return nil
+	}
+
+	switch {
+	case symbol.List:
+		return id.Context().Error("invalid operation: '%s' on list '%s'", e.Operator.Token, id.Name).
+			With("SHALL be a pointer").
+			ByRule(RulePointerArithmetic, context.Error).
+			For(symbol.Context.Note("declared here"))
+
+	case symbol.Length > 0:
+		return id.Context().Error("invalid operation: '%s' on array '%s'", e.Operator.Token, id.Name).
+			With("use '&%s[0]' to get pointer of the first element", id.Name).
+			ByRule(RulePointerArithmetic, context.Error).
+			For(symbol.Context.Note("declared here"))
+	}
+
+	if t, ok := symbol.Type.(*ast.SimpleType); ok && len(t.PointerAsterisk) == 0 {
+		return id.Context().Error("invalid operation: '%s' on non-pointer '%s'", e.Operator.Token, id.Name).
+			With("SHALL be a pointer").
+			ByRule(RulePointerArithmetic, context.Error).
+			For(symbol.Context.Note("declared here"))
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPointerArithmeticCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun get(data *uint8) (int32) {",
+		"    var buf uint32[4]",
+		"    ref p := &buf[0] +>> 4",
+		"    ref q := p -<< 1 uint32",
+		"    ref r := (data +>> 2 uint16) -<< 1",
+		"    return 0",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckPointerArithmeticErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    var n int32 = 1",
+				"    p := n +>> 4",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: invalid operation: '+>>' on non-pointer 'n' [pointer-arithmetic]",
+				"    3 |     p := n +>> 4",
+				"      |          ^",
+				"      |          SHALL be a pointer",
+				"test.mc:2:9: note: declared here",
+				"    2 |     var n int32 = 1",
+				"      |         ^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    arr := int32[4]{}",
+				"    p := arr +>> 4 int32",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: invalid operation: '+>>' on array 'arr' [pointer-arithmetic]",
+				"    3 |     p := arr +>> 4 int32",
+				"      |          ^^^",
+				"      |          use '&arr[0]' to get pointer of the first element",
+				"test.mc:2:5: note: declared here",
+				"    2 |     arr := int32[4]{}",
+				"      |     ^^^",
+			},
+		},
+		{
+			[]string{
+				"fun get() (int32) {",
+				"    l := new int32[]{1}",
+				"    p := l -<< 1",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: invalid operation: '-<<' on list 'l' [pointer-arithmetic]",
+				"    3 |     p := l -<< 1",
+				"      |          ^",
+				"      |          SHALL be a pointer",
+				"test.mc:2:5: note: declared here",
+				"    2 |     l := new int32[]{1}",
+				"      |     ^",
+			},
+		},
+		{
+			[]string{
+				"fun get(x int32) (int32) {",
+				"    p := (x +>> 1)",
+				"    return 0",
+				"}",
+			},
+			[]string{
+				"test.mc:2:11: error: invalid operation: '+>>' on non-pointer 'x' [pointer-arithmetic]",
+				"    2 |     p := (x +>> 1)",
+				"      |           ^",
+				"      |           SHALL be a pointer",
+				"test.mc:1:9: note: declared here",
+				"    1 | fun get(x int32) (int32) {",
+				"      |         ^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		checkCodeError(t, strings.Join(c.code, "\n"), strings.Join(c.expected, "\n"))
+	}
+}
@@ -0,0 +1,526 @@
+package check
+
+import (
+	"regexp"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// resolution records declarations bound to identifiers used in document, names of C headers are not bound.
+type resolution struct {
+	symbols map[*ast.Identifier]*Symbol
+}
+
+func newResolution() *resolution {
+	r := &resolution{
+		symbols: make(map[*ast.Identifier]*Symbol),
+	}
+
+	return r
+}
+
+// Resolver binds identifiers in functions to their declarations. Undefined names are errors, and unused locals and
+// arguments and names shadowing outer declarations are warnings.
+type Resolver struct {
+	config      *CheckConfigure
+	names       *resolution
+	diagnostics *context.DiagnosticContainer
+
+	// external tells whether functions may be declared in C headers included, which are not reported if undefined.
+	external bool
+
+	// inline records names in inline C code, which may be declared by C code.
+	inline map[string]bool
+
+	// suppressions are rules suppressed by comments in function resolving.
+	suppressions *Suppressions
+}
+
+var inlineIdentifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// declareDocument declares functions and types of document in a scope nested in scope of global variables.
+func declareDocument(globals *Scope, doc *ast.Document) *Scope {
+	scope := NewScope(globals)
+	for _, decl := range doc.Declarations {
+		switch d := decl.(type) {
+		case *ast.FunctionDeclaration:
+			if !d.IsMethod() {
+				_, _ = scope.Declare(d.Name, SymbolFunction)
+			}
+
+		case *ast.TypeDeclaration:
+			_, _ = scope.Declare(d.Name, SymbolType)
+		}
+	}
+
+	return scope
+}
+
+// hasInclude tells whether document includes C headers, whose names are not known by checker.
+func hasInclude(doc *ast.Document) bool {
+	for _, decl := range doc.Declarations {
+		if _, ok := decl.(*ast.PreprocessorInclude); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveDocument resolves names in all functions of document.
+func resolveDocument(conf *CheckConfigure, globals *Scope, names *resolution, doc *ast.Document) *context.DiagnosticContainer {
+	r := &Resolver{
+		config:      conf,
+		names:       names,
+		diagnostics: context.NewDiagnosticContainer(conf.Level),
+		external:    hasInclude(doc),
+		inline:      make(map[string]bool),
+	}
+
+	scope := declareDocument(globals, doc)
+	for _, decl := range doc.Declarations {
+		switch d := decl.(type) {
+		case *ast.PreprocessorInline:
+			r.inlineNames(scope, d)
+
+		case *ast.FunctionDeclaration:
+			if err := r.resolveFunction(scope, d); err != nil {
+				return r.diagnostics
+			}
+		}
+	}
+
+	return r.diagnostics
+}
+
+// report adds a diagnostic, and returns an error if it stops checking.
+func (r *Resolver) report(d context.DiagnosticInfo) error {
+	return r.diagnostics.Add(d)
+}
+
+// declare declares name in scope, and reports it if it shadows a declaration in outer scopes. Global variables are
+// not shadowed, which are accessible only after declared in function.
+func (r *Resolver) declare(scope *Scope, name *ast.Identifier, kind SymbolKind) (*Symbol, error) {
+	outer, shadowed := scope.Lookup(name.Name)
+	symbol, _ := scope.Declare(name, kind)
+	if symbol == nil {
+		// dummy names and duplicated names, which are reported by checks of statements
+		return nil, nil
+	}
+
+	r.names.symbols[name] = symbol
+	if !shadowed || outer.Kind == SymbolGlobal || outer.Kind == SymbolType {
+		return symbol, nil
+	}
+
+	d := ruleDiagnostic(r.config, r.suppressions, RuleShadowedName,
+		name.Context().Warning("declaration of '%s' shadows outer %s", name.Name, symbolKindNames[outer.Kind]).
+			With("rename it to avoid confusion"))
+	if d == nil {
+		return symbol, nil
+	}
+
+	return symbol, r.report(d.For(outer.Context.Note("shadowed declaration here")))
+}
+
+var symbolKindNames = map[SymbolKind]string{
+	SymbolArgument: "argument",
+	SymbolVariable: "variable",
+	SymbolConstant: "constant",
+	SymbolGlobal:   "global variable",
+	SymbolModule:   "module",
+	SymbolFunction: "function",
+	SymbolType:     "type",
+}
+
+// close reports locals and arguments in scope which are never used, names in form of dummy are not declared.
+func (r *Resolver) close(scope *Scope, declared []*Symbol) error {
+	for _, symbol := range declared {
+		if symbol.Used || (symbol.Kind != SymbolArgument && symbol.Kind != SymbolVariable && symbol.Kind != SymbolConstant) {
+			continue
+		}
+
+		rule := RuleUnusedVariable
+		if symbol.Kind == SymbolArgument {
+			rule = RuleUnusedArgument
+		}
+
+		d := ruleDiagnostic(r.config, r.suppressions, rule,
+			symbol.Context.Warning("%s '%s' declared and not used", symbolKindNames[symbol.Kind], symbol.Name).
+				With("use it or rename it to '_'"))
+		if d == nil {
+			continue
+		}
+
+		if e := r.report(d); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// resolveFunction resolves names in body of function, the receiver is not required to be used.
+func (r *Resolver) resolveFunction(document *Scope, d *ast.FunctionDeclaration) error {
+	r.suppressions = FunctionSuppressions(d)
+	scope := NewScope(document)
+	for _, g := range d.GlobalAccesses() {
+		if _, err := r.declare(scope, g.Name, SymbolGlobal); err != nil {
+			return err
+		}
+	}
+
+	if d.Receiver != nil {
+		symbol, err := r.declare(scope, d.Receiver.Name, SymbolArgument)
+		if err != nil {
+			return err
+		}
+
+		if symbol != nil {
+			symbol.Used = true
+		}
+	}
+
+	var args []*Symbol
+	if d.Arguments != nil {
+		for _, arg := range d.Arguments.Arguments {
+			symbol, err := r.declare(scope, arg.Name, SymbolArgument)
+			if err != nil {
+				return err
+			}
+
+			if symbol != nil {
+				args = append(args, symbol)
+			}
+		}
+	}
+
+	if err := r.resolveBlock(scope, d.Statements); err != nil {
+		return err
+	}
+
+	return r.close(scope, args)
+}
+
+// resolveBlock resolves statements in a new scope nested in scope.
+func (r *Resolver) resolveBlock(scope *Scope, stmts []ast.Statement) error {
+	return r.resolveStatements(NewScope(scope), stmts)
+}
+
+func (r *Resolver) resolveStatements(scope *Scope, stmts []ast.Statement) error {
+	var declared []*Symbol
+	declare := func(name *ast.Identifier, kind SymbolKind) error {
+		symbol, err := r.declare(scope, name, kind)
+		if symbol != nil {
+			declared = append(declared, symbol)
+		}
+
+		return err
+	}
+
+	for _, stmt := range stmts {
+		if err := r.resolveStatement(scope, stmt, declare); err != nil {
+			return err
+		}
+	}
+
+	return r.close(scope, declared)
+}
+
+func (r *Resolver) resolveStatement(scope *Scope, stmt ast.Statement, declare func(*ast.Identifier, SymbolKind) error) error {
+	switch s := stmt.(type) {
+	case *ast.VariableDeclaration:
+		if err := r.resolveExpression(scope, s.Value); err != nil {
+			return err
+		}
+
+		kind := SymbolVariable
+		if s.IsConst() {
+			kind = SymbolConstant
+		}
+
+		return declare(s.Name, kind)
+
+	case *ast.InferenceDeclaration:
+		if err := r.resolveList(scope, s.Values); err != nil {
+			return err
+		}
+
+		for _, name := range s.Identifiers() {
+			if err := declare(name, SymbolVariable); err != nil {
+				return err
+			}
+		}
+
+	case *ast.GlobalDeclaration:
+		return declare(s.Variable.Name, SymbolGlobal)
+
+	case *ast.AssignmentStatement:
+		if err := r.resolveList(scope, s.Values); err != nil {
+			return err
+		}
+
+		for _, target := range s.Targets.Expressions {
+			if err := r.resolveTarget(scope, target.Expression); err != nil {
+				return err
+			}
+		}
+
+	case *ast.IncrementStatement:
+		return r.resolveTarget(scope, s.Target)
+
+	case *ast.ExpressionStatement:
+		return r.resolveExpression(scope, s.Expression)
+
+	case *ast.ReturnStatement:
+		return r.resolveList(scope, s.Value)
+
+	case *ast.DeleteStatement:
+		return r.resolveExpression(scope, s.Target)
+
+	case *ast.IfStatement:
+		for _, branch := range s.Branches {
+			if err := r.resolveExpression(scope, branch.Condition); err != nil {
+				return err
+			}
+
+			if err := r.resolveBlock(scope, branch.Body.Statements); err != nil {
+				return err
+			}
+		}
+
+		if s.ElseBody != nil {
+			return r.resolveBlock(scope, s.ElseBody.Statements)
+		}
+
+	case *ast.WhileStatement:
+		if err := r.resolveExpression(scope, s.Condition); err != nil {
+			return err
+		}
+
+		return r.resolveBlock(scope, s.Body.Statements)
+
+	case *ast.DoWhileStatement:
+		if err := r.resolveBlock(scope, s.Body.Statements); err != nil {
+			return err
+		}
+
+		return r.resolveExpression(scope, s.Condition)
+
+	case *ast.ForStatement:
+		return r.resolveFor(scope, s)
+
+	case *ast.ForeachStatement:
+		if err := r.resolveExpression(scope, s.Iterable); err != nil {
+			return err
+		}
+
+		body := NewScope(scope)
+		variable, err := r.declare(body, s.Variable, SymbolVariable)
+		if err != nil {
+			return err
+		}
+
+		if err := r.resolveStatements(body, s.Body.Statements); err != nil {
+			return err
+		}
+
+		if variable != nil {
+			return r.close(body, []*Symbol{variable})
+		}
+
+	case *ast.PreprocessorInline:
+		r.inlineNames(scope, s)
+	}
+
+	return nil
+}
+
+// inlineNames marks names in inline C code used, inline C code is not parsed and names in it may be declared by C.
+func (r *Resolver) inlineNames(scope *Scope, p *ast.PreprocessorInline) {
+	for _, name := range inlineIdentifier.FindAllString(p.Content, -1) {
+		r.inline[name] = true
+		if symbol, found := scope.Lookup(name); found {
+			symbol.Used = true
+		}
+	}
+}
+
+// resolveFor resolves for loop, variable declared by initializer is in scope of header.
+func (r *Resolver) resolveFor(scope *Scope, s *ast.ForStatement) error {
+	header := NewScope(scope)
+	var declared []*Symbol
+	declare := func(name *ast.Identifier, kind SymbolKind) error {
+		symbol, err := r.declare(header, name, kind)
+		if symbol != nil {
+			declared = append(declared, symbol)
+		}
+
+		return err
+	}
+
+	if s.Initializer != nil {
+		if err := r.resolveStatement(header, s.Initializer, declare); err != nil {
+			return err
+		}
+	}
+
+	if err := r.resolveExpression(header, s.Condition); err != nil {
+		return err
+	}
+
+	if s.Post != nil {
+		if err := r.resolveStatement(header, s.Post, declare); err != nil {
+			return err
+		}
+	}
+
+	if err := r.resolveBlock(header, s.Body.Statements); err != nil {
+		return err
+	}
+
+	return r.close(header, declared)
+}
+
+// resolveTarget resolves target of assignment, a variable assigned is not used, but the variable of a member or an
+// element assigned is.
+func (r *Resolver) resolveTarget(scope *Scope, target ast.Expression) error {
+	if id, ok := target.(*ast.Identifier); ok {
+		used := false
+		if symbol, found := scope.Lookup(id.Name); found {
+			used = symbol.Used
+		}
+
+		if err := r.resolveIdentifier(scope, id); err != nil {
+			return err
+		}
+
+		if symbol, found := scope.Lookup(id.Name); found {
+			symbol.Used = used
+		}
+
+		return nil
+	}
+
+	return r.resolveExpression(scope, target)
+}
+
+func (r *Resolver) resolveList(scope *Scope, list *ast.ExpressionList) error {
+	if list == nil {
+		return nil
+	}
+
+	for _, item := range list.Expressions {
+		if err := r.resolveExpression(scope, item.Expression); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveIdentifier binds identifier to its declaration. Builtin functions and basic types are not declared, and
+// names not found may be declared in inline C code.
+func (r *Resolver) resolveIdentifier(scope *Scope, id *ast.Identifier) error {
+	if id.IsDummy() {
+		return nil
+	}
+
+	if symbol, found := scope.Lookup(id.Name); found {
+		symbol.Used = true
+		r.names.symbols[id] = symbol
+		return nil
+	}
+
+	if isBasicType(id.Name) || IsBuiltinFunction(id.Name) || r.inline[id.Name] {
+		return nil
+	}
+
+	err := id.Context().Error("undefined: '%s'", id.Name).
+		With("not declared").
+		ByRule(RuleUndefinedName, context.Error)
+	return r.report(err)
+}
+
+// resolveCallee binds function called to its declaration, functions not found may be declared in C headers included.
+func (r *Resolver) resolveCallee(scope *Scope, callee ast.Expression) error {
+	if id, ok := callee.(*ast.Identifier); ok && r.external {
+		if _, found := scope.Lookup(id.Name); !found {
+			return nil
+		}
+	}
+
+	return r.resolveExpression(scope, callee)
+}
+
+func (r *Resolver) resolveExpression(scope *Scope, expr ast.Expression) error {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return r.resolveIdentifier(scope, e)
+
+	case *ast.ParenthesizedExpression:
+		return r.resolveExpression(scope, e.Expression)
+
+	case *ast.PrefixExpression:
+		return r.resolveExpression(scope, e.Operand)
+
+	case *ast.InfixExpression:
+		if err := r.resolveExpression(scope, e.LeftOperand); err != nil {
+			return err
+		}
+
+		return r.resolveExpression(scope, e.RightOperand)
+
+	case *ast.CallExpression:
+		if err := r.resolveCallee(scope, e.Callee); err != nil {
+			return err
+		}
+
+		return r.resolveList(scope, e.Arguments)
+
+	case *ast.MemberExpression:
+		// members are resolved by types
+		return r.resolveExpression(scope, e.Object)
+
+	case *ast.IndexExpression:
+		if err := r.resolveExpression(scope, e.Object); err != nil {
+			return err
+		}
+
+		return r.resolveExpression(scope, e.Index)
+
+	case *ast.ArrayLiteral:
+		return r.resolveList(scope, e.Elements)
+
+	case *ast.StructLiteral:
+		for _, field := range e.Fields {
+			if err := r.resolveExpression(scope, field.Value); err != nil {
+				return err
+			}
+		}
+
+	case *ast.NewExpression:
+		if call, ok := e.Constructor(); ok {
+			// callee of constructor is a type
+			return r.resolveList(scope, call.Arguments)
+		}
+
+		return r.resolveExpression(scope, e.Value)
+
+	case *ast.PointerArithmeticExpression:
+		if err := r.resolveExpression(scope, e.Pointer); err != nil {
+			return err
+		}
+
+		return r.resolveExpression(scope, e.Offset)
+
+	case *ast.SizeofExpression:
+		// operand of sizeof is not evaluated, but variables in it are used
+		if _, value := SizeofOperand(e, scope.variableType); value != nil {
+			return r.resolveExpression(scope, value)
+		}
+	}
+
+	return nil
+}
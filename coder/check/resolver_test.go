@@ -0,0 +1,155 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func TestResolveNamesCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"#include <stdio.h>",
+		"fun area(w int, h int) (int) {",
+		"    return w * h",
+		"}",
+		"fun main() {",
+		"    #inline c",
+		"    int n = 3;",
+		"    #end-inline c",
+		"    a := area(n, 2)",
+		"    _ := a",
+		"    printf(\"%d\\n\", a)",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	container := NewCodeChecker(NewDefaultCheckConfigure(), doc).Check()
+	if container.Count(context.Warning) > 0 {
+		t.Fatalf("code check expected to succeed, but got:\n%s", container.Error())
+	}
+}
+
+func TestResolveNamesErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun main() (int) {",
+				"    return x",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: undefined: 'x' [undefined-name]",
+				"    2 |     return x",
+				"      |            ^",
+				"      |            not declared",
+			},
+		},
+		{
+			[]string{
+				"#include <stdio.h>",
+				"fun sum(a int, b int) (int) {",
+				"    total := a + b",
+				"    printf(\"%d\\n\", total)",
+				"    return totl",
+				"}",
+			},
+			[]string{
+				"test.mc:5:12: error: undefined: 'totl' [undefined-name]",
+				"    5 |     return totl",
+				"      |            ^^^^",
+				"      |            not declared",
+			},
+		},
+		{
+			[]string{
+				"fun add(a int, b int) (int) {",
+				"    c := a",
+				"    c = b",
+				"    return a",
+				"}",
+			},
+			[]string{
+				"test.mc:2:5: warning: variable 'c' declared and not used [unused-variable]",
+				"    2 |     c := a",
+				"      |     ^",
+				"      |     use it or rename it to '_'",
+			},
+		},
+		{
+			[]string{
+				"fun f(n int) (int) {",
+				"    if n > 0 {",
+				"        n := 1",
+				"        return n",
+				"    }",
+				"    return n",
+				"}",
+			},
+			[]string{
+				"test.mc:3:9: warning: declaration of 'n' shadows outer argument [shadowed-name]",
+				"    3 |         n := 1",
+				"      |         ^",
+				"      |         rename it to avoid confusion",
+				"test.mc:1:7: note: shadowed declaration here",
+				"    1 | fun f(n int) (int) {",
+				"      |       ^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join(c.code, "\n")
+		expected := strings.Join(c.expected, "\n")
+		checkCodeError(t, code, expected)
+	}
+}
+
+func TestResolveShadowDisabled(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f(n int) (int) {",
+		"    if n > 0 {",
+		"        n := 1",
+		"        return n",
+		"    }",
+		"    return n",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	conf := NewDefaultCheckConfigure()
+	conf.Rules[RuleShadowedName] = context.Ignored
+	container := NewCodeChecker(conf, doc).Check()
+	if container.Count(context.Warning) > 0 {
+		t.Fatalf("code check expected to succeed, but got:\n%s", container.Error())
+	}
+}
+
+func TestResolution(t *testing.T) {
+	code := strings.Join([]string{
+		"fun area(w int, h int) (int) {",
+		"    return w * h",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	checker := NewCodeChecker(NewDefaultCheckConfigure(), doc)
+	if container := checker.Check(); container.Count(context.Warning) > 0 {
+		t.Fatalf("code check expected to succeed, but got:\n%s", container.Error())
+	}
+
+	f := doc.Declarations[0].(*ast.FunctionDeclaration)
+	ret := f.Statements[0].(*ast.ReturnStatement)
+	value := ret.Value.Expressions[0].Expression.(*ast.InfixExpression)
+	for i, arg := range f.Arguments.Arguments {
+		operand := []ast.Expression{value.LeftOperand, value.RightOperand}[i]
+		symbol, found := checker.names.symbols[operand.(*ast.Identifier)]
+		if !found || symbol.Kind != SymbolArgument || symbol.Context != arg.Name.Context() {
+			t.Errorf("argument '%s' is not bound", arg.Name.Name)
+		}
+	}
+}
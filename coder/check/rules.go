@@ -0,0 +1,325 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// Rule is a check whose diagnostics can be disabled, or raised to errors by configure. Rules of errors are never
+// disabled.
+type Rule struct {
+	ID          string
+	Level       context.ErrorLevel
+	Description string
+}
+
+const (
+	RuleUnusedVariable  = "unused-variable"
+	RuleUnusedArgument  = "unused-argument"
+	RuleShadowedName    = "shadowed-name"
+	RuleUnreachableCode = "unreachable-code"
+	RuleEndlessLoop     = "endless-loop"
+)
+
+const (
+	RuleUndefinedName      = "undefined-name"
+	RuleUnexportedName     = "unexported-name"
+	RuleModuleName         = "module-name"
+	RuleImportCycle        = "import-cycle"
+	RuleDuplicatedName     = "duplicated-name"
+	RuleUnsupportedSyntax  = "unsupported-syntax"
+	RuleGlobalAccess       = "global-access"
+	RuleGlobalInitializer  = "global-initializer"
+	RuleMissingValue       = "missing-value"
+	RuleMainSignature      = "main-signature"
+	RuleReturnCount        = "return-count"
+	RuleMissingReturn      = "missing-return"
+	RuleArraySize          = "array-size"
+	RuleArrayLiteral       = "array-literal"
+	RuleArrayCopy          = "array-copy"
+	RuleIndexOutOfBounds   = "index-out-of-bounds"
+	RuleArgumentCount      = "argument-count"
+	RuleBuiltinName        = "builtin-name"
+	RuleListResize         = "list-resize"
+	RulePointerArithmetic  = "pointer-arithmetic"
+	RuleOwnershipTransfer  = "ownership-transfer"
+	RuleReferenceType      = "reference-type"
+	RuleDoubleDelete       = "double-delete"
+	RuleInvalidDelete      = "invalid-delete"
+	RuleUseAfterMove       = "use-after-move"
+	RuleUseAfterDelete     = "use-after-delete"
+	RuleDanglingReference  = "dangling-reference"
+	RuleAssignmentMismatch = "assignment-mismatch"
+	RuleConstantAssignment = "constant-assignment"
+	RuleNonBooleanCond     = "non-boolean-condition"
+	RuleLoopHeader         = "loop-header"
+	RuleBranchOutsideLoop  = "branch-outside-loop"
+	RuleStructLiteral      = "struct-literal"
+	RuleRecursiveType      = "recursive-type"
+	RuleTypeMismatch       = "type-mismatch"
+	RuleInvalidOperation   = "invalid-operation"
+	RuleInvalidConversion  = "invalid-conversion"
+	RuleMultipleValue      = "multiple-value"
+	RuleUndefinedMember    = "undefined-member"
+)
+
+var rules = []*Rule{
+	{ID: RuleUnusedVariable, Level: context.Warning, Description: "local variables and constants never used"},
+	{ID: RuleUnusedArgument, Level: context.Warning, Description: "arguments never used in function"},
+	{ID: RuleShadowedName, Level: context.Warning, Description: "names shadowing declarations in outer scopes"},
+	{ID: RuleUnreachableCode, Level: context.Warning, Description: "statements never executed"},
+	{ID: RuleEndlessLoop, Level: context.Warning, Description: "loops with constant true condition never terminate"},
+
+	{ID: RuleUndefinedName, Level: context.Error, Description: "names, modules and module members not declared"},
+	{ID: RuleUnexportedName, Level: context.Error, Description: "module members not exported"},
+	{ID: RuleModuleName, Level: context.Error, Description: "source files imported declaring another module"},
+	{ID: RuleImportCycle, Level: context.Error, Description: "modules importing themselves"},
+	{ID: RuleDuplicatedName, Level: context.Error, Description: "names and fields declared twice in the same scope"},
+	{ID: RuleUnsupportedSyntax, Level: context.Error, Description: "declarations not supported yet"},
+	{ID: RuleGlobalAccess, Level: context.Error, Description: "global variables used without declaration in function"},
+	{ID: RuleGlobalInitializer, Level: context.Error, Description: "global variables initialized by non-constant values"},
+	{ID: RuleMissingValue, Level: context.Error, Description: "constants and inferred variables without value"},
+	{ID: RuleMainSignature, Level: context.Error, Description: "function 'main' returning values other than 'int'"},
+	{ID: RuleReturnCount, Level: context.Error, Description: "return statements in wrong number of values"},
+	{ID: RuleMissingReturn, Level: context.Error, Description: "functions reaching the end without return value"},
+	{ID: RuleArraySize, Level: context.Error, Description: "sizes of arrays missing or not positive constants"},
+	{ID: RuleArrayLiteral, Level: context.Error, Description: "array literals not matching arrays initialized"},
+	{ID: RuleArrayCopy, Level: context.Error, Description: "arrays copied or assigned as a whole"},
+	{ID: RuleIndexOutOfBounds, Level: context.Error, Description: "constant indexes out of bounds of arrays"},
+	{ID: RuleArgumentCount, Level: context.Error, Description: "calls, conversions and new in wrong number of arguments"},
+	{ID: RuleBuiltinName, Level: context.Error, Description: "functions declared in names of builtin functions"},
+	{ID: RuleListResize, Level: context.Error, Description: "non-variable lists and fixed size arrays resized"},
+	{ID: RulePointerArithmetic, Level: context.Error, Description: "arithmetic on non-pointers and by arithmetic operators"},
+	{ID: RuleOwnershipTransfer, Level: context.Error, Description: "borrowed pointers taken by owners, or allocations by references"},
+	{ID: RuleReferenceType, Level: context.Error, Description: "references declared in non-pointer types"},
+	{ID: RuleDoubleDelete, Level: context.Error, Description: "pointers deleted twice"},
+	{ID: RuleInvalidDelete, Level: context.Error, Description: "references and non-pointers deleted"},
+	{ID: RuleUseAfterMove, Level: context.Error, Description: "owners used after ownership moved"},
+	{ID: RuleUseAfterDelete, Level: context.Error, Description: "pointers used after deleted"},
+	{ID: RuleDanglingReference, Level: context.Error, Description: "references to locals returned"},
+	{ID: RuleAssignmentMismatch, Level: context.Error, Description: "assignments in different numbers of targets and values"},
+	{ID: RuleConstantAssignment, Level: context.Error, Description: "constants assigned"},
+	{ID: RuleNonBooleanCond, Level: context.Error, Description: "conditions not in boolean type"},
+	{ID: RuleLoopHeader, Level: context.Error, Description: "headers of for loops declaring in wrong places"},
+	{ID: RuleBranchOutsideLoop, Level: context.Error, Description: "'break' and 'continue' outside loops"},
+	{ID: RuleStructLiteral, Level: context.Error, Description: "struct literals of non-structures or unknown fields"},
+	{ID: RuleRecursiveType, Level: context.Error, Description: "structures containing themselves by value"},
+	{ID: RuleTypeMismatch, Level: context.Error, Description: "values and operands in incompatible types"},
+	{ID: RuleInvalidOperation, Level: context.Error, Description: "operators, indexes and iterations on values of wrong types"},
+	{ID: RuleInvalidConversion, Level: context.Error, Description: "conversions of non-numbers, or to 'bool'"},
+	{ID: RuleMultipleValue, Level: context.Error, Description: "calls returning multiple values used as single values"},
+	{ID: RuleUndefinedMember, Level: context.Error, Description: "members not found in types of values"},
+}
+
+// LookupRule finds rule by ID.
+func LookupRule(id string) (*Rule, bool) {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// RuleIDs returns IDs of rules overridden by options, rules of errors are not included.
+func RuleIDs() []string {
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Level != context.Error {
+			ids = append(ids, rule.ID)
+		}
+	}
+
+	return ids
+}
+
+// RuleFileName is name of project config in source directory, which contains rule options one per line.
+const RuleFileName = "magi-c.rules"
+
+// ParseRuleOption parses an option of rule, which is 'ID' enabling the rule as warning, 'no-ID' disabling it, or
+// 'error=ID' raising it to error.
+func ParseRuleOption(option string) (string, context.ErrorLevel, error) {
+	id, level := option, context.Warning
+	if rest, found := strings.CutPrefix(option, "no-"); found {
+		id, level = rest, context.Ignored
+
+	} else if rest, found := strings.CutPrefix(option, "error="); found {
+		id, level = rest, context.Error
+	}
+
+	rule, found := LookupRule(id)
+	if !found {
+		return "", context.Ignored, fmt.Errorf("unknown rule '%s' in option '%s'", id, option)
+	}
+
+	if rule.Level == context.Error {
+		return "", context.Ignored, fmt.Errorf("rule '%s' reports errors, which can not be overridden", id)
+	}
+
+	return id, level, nil
+}
+
+// ReadRuleOptions reads rule options from file, blank lines and lines starting with '#' are skipped.
+func ReadRuleOptions(filename string) ([]string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, "#") {
+			options = append(options, line)
+		}
+	}
+
+	return options, nil
+}
+
+// RuleLevel returns level of diagnostics produced by rule, the rule is disabled in level Ignored.
+func (c *CheckConfigure) RuleLevel(id string) context.ErrorLevel {
+	if rule, ok := LookupRule(id); ok && rule.Level == context.Error {
+		return context.Error
+	}
+
+	level, found := c.Rules[id]
+	if !found {
+		if rule, ok := LookupRule(id); ok {
+			level = rule.Level
+		}
+	}
+
+	if c.WarningAsError && level == context.Warning {
+		return context.Error
+	}
+
+	return level
+}
+
+// SetRuleOption overrides level of rule by an option in form accepted by ParseRuleOption.
+func (c *CheckConfigure) SetRuleOption(option string) error {
+	id, level, err := ParseRuleOption(option)
+	if err != nil {
+		return err
+	}
+
+	if c.Rules == nil {
+		c.Rules = make(map[string]context.ErrorLevel)
+	}
+
+	c.Rules[id] = level
+	return nil
+}
+
+// ReadRuleFile overrides levels of rules by options in file, which is read by ReadRuleOptions.
+func (c *CheckConfigure) ReadRuleFile(filename string) error {
+	options, err := ReadRuleOptions(filename)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		if err := c.SetRuleOption(option); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SuppressPrefix starts comments suppressing rules, which are followed by IDs of rules, or nothing for all rules.
+const SuppressPrefix = "magi-c:ignore"
+
+type suppression struct {
+	first int
+	last  int
+	rules []string
+}
+
+// Suppressions are rules suppressed by comments in lines of statements and declarations.
+type Suppressions struct {
+	items []suppression
+}
+
+// FunctionSuppressions collects rules suppressed in function, by comments before or after the function, and
+// statements in its body.
+func FunctionSuppressions(d *ast.FunctionDeclaration) *Suppressions {
+	s := &Suppressions{}
+	s.collect(d)
+
+	var walk func(stmts []ast.Statement)
+	walk = func(stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			s.collect(stmt)
+			for _, body := range statementBodies(stmt) {
+				walk(body)
+			}
+		}
+	}
+
+	walk(d.Statements)
+	return s
+}
+
+func (s *Suppressions) collect(node ast.Node) {
+	commentable, ok := node.(ast.Commentable)
+	if !ok {
+		return
+	}
+
+	comments := slices.Concat(commentable.LeadingComments(), commentable.TrailingComments())
+	for _, comment := range comments {
+		for _, line := range comment.Lines {
+			rest, found := strings.CutPrefix(strings.TrimSpace(line), SuppressPrefix)
+			if !found || (len(rest) > 0 && rest[0] != ' ' && rest[0] != '\t') {
+				continue
+			}
+
+			_, first, _ := node.Context().Position()
+			last, _ := node.Context().Last()
+			s.items = append(s.items, suppression{
+				first: first,
+				last:  last,
+				rules: strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }),
+			})
+		}
+	}
+}
+
+// Suppressed tells whether diagnostics of rule at ctx are suppressed.
+func (s *Suppressions) Suppressed(rule string, ctx *context.Context) bool {
+	_, line, _ := ctx.Position()
+	for _, item := range s.items {
+		if line < item.first || line > item.last {
+			continue
+		}
+
+		if len(item.rules) == 0 {
+			return true
+		}
+
+		if slices.Contains(item.rules, rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ruleDiagnostic marks warning d produced by rule in level configured, nil is returned if the rule is disabled,
+// or suppressed where d is reported.
+func ruleDiagnostic(conf *CheckConfigure, suppressions *Suppressions, rule string, d *context.Diagnostic) *context.Diagnostic {
+	level := conf.RuleLevel(rule)
+	if level == context.Ignored || suppressions.Suppressed(rule, d.Context()) {
+		return nil
+	}
+
+	return d.ByRule(rule, level)
+}
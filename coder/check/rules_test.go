@@ -0,0 +1,192 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/context"
+)
+
+func TestParseRuleOption(t *testing.T) {
+	cases := []struct {
+		option string
+		id     string
+		level  context.ErrorLevel
+	}{
+		{"unused-variable", RuleUnusedVariable, context.Warning},
+		{"no-shadowed-name", RuleShadowedName, context.Ignored},
+		{"error=endless-loop", RuleEndlessLoop, context.Error},
+	}
+
+	for _, c := range cases {
+		id, level, err := ParseRuleOption(c.option)
+		if err != nil {
+			t.Fatalf("parse option '%s' failed: %s", c.option, err)
+		}
+
+		if id != c.id || level != c.level {
+			t.Errorf("option '%s' expected (%s, %s), got (%s, %s)", c.option, c.id, c.level, id, level)
+		}
+	}
+
+	if _, _, err := ParseRuleOption("no-such-rule"); err == nil {
+		t.Errorf("unknown rule expected to fail")
+	}
+
+	if _, _, err := ParseRuleOption("no-" + RuleTypeMismatch); err == nil {
+		t.Errorf("option of error rule expected to fail")
+	}
+}
+
+func TestRuleLevel(t *testing.T) {
+	conf := NewDefaultCheckConfigure()
+	if level := conf.RuleLevel(RuleUnusedVariable); level != context.Warning {
+		t.Errorf("default level of %s expected warning, got %s", RuleUnusedVariable, level)
+	}
+
+	if err := conf.SetRuleOption("no-unused-variable"); err != nil {
+		t.Fatalf("set rule option failed: %s", err)
+	}
+
+	conf.WarningAsError = true
+	if level := conf.RuleLevel(RuleUnusedVariable); level != context.Ignored {
+		t.Errorf("level of disabled rule expected ignored, got %s", level)
+	}
+
+	if level := conf.RuleLevel(RuleShadowedName); level != context.Error {
+		t.Errorf("level of warning expected to be raised to error, got %s", level)
+	}
+
+	if !slices.Contains(RuleIDs(), RuleEndlessLoop) {
+		t.Errorf("rule %s not found in %v", RuleEndlessLoop, RuleIDs())
+	}
+
+	conf.Rules[RuleUseAfterMove] = context.Ignored
+	if level := conf.RuleLevel(RuleUseAfterMove); level != context.Error {
+		t.Errorf("level of error rule expected error, got %s", level)
+	}
+
+	if slices.Contains(RuleIDs(), RuleUseAfterMove) {
+		t.Errorf("error rule %s expected not to be overridden", RuleUseAfterMove)
+	}
+}
+
+func TestRuleIDsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if seen[rule.ID] {
+			t.Errorf("rule %s declared twice", rule.ID)
+		}
+
+		seen[rule.ID] = true
+	}
+}
+
+func TestReadRuleOptions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), RuleFileName)
+	content := strings.Join([]string{
+		"# rules of project",
+		"no-unused-argument",
+		"",
+		"  error=unreachable-code  ",
+	}, "\n")
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("write rule file failed: %s", err)
+	}
+
+	options, err := ReadRuleOptions(filename)
+	if err != nil {
+		t.Fatalf("read rule file failed: %s", err)
+	}
+
+	expected := []string{"no-unused-argument", "error=unreachable-code"}
+	if !slices.Equal(options, expected) {
+		t.Fatalf("rule options expected %v, got %v", expected, options)
+	}
+
+	conf := NewDefaultCheckConfigure()
+	if err := conf.ReadRuleFile(filename); err != nil {
+		t.Fatalf("read rule file failed: %s", err)
+	}
+
+	if level := conf.RuleLevel(RuleUnreachableCode); level != context.Error {
+		t.Errorf("level of %s expected error, got %s", RuleUnreachableCode, level)
+	}
+
+	if level := conf.RuleLevel(RuleUnusedArgument); level != context.Ignored {
+		t.Errorf("level of %s expected ignored, got %s", RuleUnusedArgument, level)
+	}
+}
+
+func TestRuleRaisedToError(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f(n int) (int) {",
+		"    return n",
+		"    n = 0",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	conf := NewDefaultCheckConfigure()
+	conf.Rules[RuleUnreachableCode] = context.Error
+	container := NewCodeChecker(conf, doc).Check()
+	expected := strings.Join([]string{
+		"test.mc:3:5: error: unreachable code [unreachable-code]",
+		"    3 |     n = 0",
+		"      |     ^ ^ ^",
+		"      |     never executed",
+	}, "\n")
+
+	if container.Error() != expected {
+		t.Fatalf("code check error mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
+	}
+
+	if container.Count(context.Error) != 1 || container.CountRule(RuleUnreachableCode) != 1 {
+		t.Fatalf("one error of rule %s expected, got:\n%s", RuleUnreachableCode, container.Error())
+	}
+}
+
+func TestRuleSuppressed(t *testing.T) {
+	code := strings.Join([]string{
+		"// magi-c:ignore unused-argument",
+		"fun f(a int, b int) (int) {",
+		"    c := 0 // magi-c:ignore unused-variable",
+		"    // magi-c:ignore",
+		"    while true {",
+		"    }",
+		"}",
+		"fun g(n int) (int) {",
+		"    // magi-c:ignore shadowed-name, unreachable-code",
+		"    if n > 0 {",
+		"        n := 1",
+		"        return n",
+		"    }",
+		"    return n",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	container := NewCodeChecker(NewDefaultCheckConfigure(), doc).Check()
+	if container.Count(context.Warning) > 0 {
+		t.Fatalf("code check expected to succeed, but got:\n%s", container.Error())
+	}
+}
+
+func TestRuleSuppressedOtherRule(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f(n int) (int) {",
+		"    c := n // magi-c:ignore shadowed-name",
+		"    return n",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	container := NewCodeChecker(NewDefaultCheckConfigure(), doc).Check()
+	if container.CountRule(RuleUnusedVariable) != 1 {
+		t.Fatalf("warning of rule %s expected, but got:\n%s", RuleUnusedVariable, container.Error())
+	}
+}
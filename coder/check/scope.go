@@ -0,0 +1,186 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+type SymbolKind int
+
+const (
+	SymbolArgument SymbolKind = iota
+	SymbolVariable
+	SymbolConstant
+	SymbolGlobal
+	SymbolModule
+	SymbolFunction
+	SymbolType
+)
+
+// ValueKind tells whether a value is known to be boolean, before types are fully checked.
+type ValueKind int
+
+const (
+	ValueUnknown ValueKind = iota
+	ValueBoolean
+	ValueNonBoolean
+)
+
+// Ownership tells whether a pointer owns the value pointed to. Values in heap are freed by their owners, and
+// references never free them.
+type Ownership int
+
+const (
+	OwnershipNone Ownership = iota
+	OwnershipOwner
+	OwnershipReference
+)
+
+// OwnerState tells whether an owning pointer still owns its value, at the point a statement is checked.
+type OwnerState int
+
+const (
+	OwnerLive OwnerState = iota
+	OwnerMoved
+	OwnerDeleted
+)
+
+type Symbol struct {
+	Name    string
+	Kind    SymbolKind
+	Type    ast.Type
+	Value   ValueKind
+	Length  int
+	List    bool
+	Owner   Ownership
+	Module  *ast.Document
+	Context *context.Context
+
+	// Used tells whether the name is used after declared, which is recorded by resolver.
+	Used bool
+
+	// State and StateAt record where an owning pointer is moved or deleted.
+	State   OwnerState
+	StateAt *context.Context
+
+	// Global is the global variable accessed, if the symbol is declared by global access of function.
+	Global *Symbol
+
+	// Referent is the local variable a reference points into, which does not live after function returns.
+	Referent *Symbol
+}
+
+type Scope struct {
+	Symbols map[string]*Symbol
+	Parent  *Scope
+	Loop    bool
+
+	// Types and Layout are types declared in document and their sizes, which are kept by the global scope.
+	Types  map[string]*ast.TypeDeclaration
+	Layout *Layout
+
+	// Functions are functions declared in document by full name, and Info records types of expressions checked.
+	Functions map[string]*ast.FunctionDeclaration
+	Info      *TypeInfo
+
+	// Function is the function declared the scope of its body.
+	Function *ast.FunctionDeclaration
+}
+
+func NewScope(parent *Scope) *Scope {
+	s := &Scope{
+		Symbols: make(map[string]*Symbol),
+		Parent:  parent,
+	}
+
+	return s
+}
+
+// NewLoopScope creates scope of a loop body, in which 'break' and 'continue' are allowed.
+func NewLoopScope(parent *Scope) *Scope {
+	s := NewScope(parent)
+	s.Loop = true
+	return s
+}
+
+func (s *Scope) InLoop() bool {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if scope.Loop {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnclosingFunction returns the function in which the scope is nested.
+func (s *Scope) EnclosingFunction() (*ast.FunctionDeclaration, bool) {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if scope.Function != nil {
+			return scope.Function, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *Scope) Root() *Scope {
+	root := s
+	for root.Parent != nil {
+		root = root.Parent
+	}
+
+	return root
+}
+
+// ConstantInteger evaluates an integer constant expression, in which sizes known for the target are constants.
+func (s *Scope) ConstantInteger(expr ast.Expression) (int64, bool) {
+	if s == nil {
+		return ConstantInteger(expr)
+	}
+
+	return s.Root().Layout.ConstantInteger(expr, s.variableType)
+}
+
+// variableType returns declared type of variable, modules are not variables.
+func (s *Scope) variableType(name string) (ast.Type, bool) {
+	symbol, found := s.Lookup(name)
+	if !found || symbol.Kind == SymbolModule {
+		return nil, false
+	}
+
+	return symbol.Type, true
+}
+
+// Declare adds a name into the scope, an error is returned if the name is already declared in the same scope.
+func (s *Scope) Declare(name *ast.Identifier, kind SymbolKind) (*Symbol, context.DiagnosticInfo) {
+	if name.IsDummy() {
+		return nil, nil
+	}
+
+	if first, found := s.Symbols[name.Name]; found {
+		err := name.Context().Error("duplicated variable name: '%s'", name.Name).
+			With("duplicated name").
+			ByRule(RuleDuplicatedName, context.Error).
+			For(first.Context.Note("first declared here"))
+		return nil, err
+	}
+
+	symbol := &Symbol{
+		Name:    name.Name,
+		Kind:    kind,
+		Context: name.Context(),
+	}
+	s.Symbols[name.Name] = symbol
+	return symbol, nil
+}
+
+func (s *Scope) Lookup(name string) (*Symbol, bool) {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if symbol, found := scope.Symbols[name]; found {
+			return symbol, true
+		}
+	}
+
+	return nil, false
+}
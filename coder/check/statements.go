@@ -0,0 +1,506 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func checkVariableDeclaration(scope *Scope, d *ast.VariableDeclaration) context.DiagnosticInfo {
+	if err := checkType(scope, d.Type); err != nil {
+		return err
+	}
+
+	if d.Value != nil {
+		if err := checkExpression(scope, d.Value); err != nil {
+			return err
+		}
+
+		if err := checkArrayInitializer(scope, d.Type, d.Value); err != nil {
+			return err
+		}
+
+		if err := checkArrayCopy(scope, d.Value); err != nil {
+			return err
+		}
+
+	} else {
+		if d.IsConst() {
+			return d.Name.Context().Error("missing value in const declaration of '%s'", d.Name.Name).
+				With("constant must be initialized").
+				ByRule(RuleMissingValue, context.Error)
+		}
+
+		if d.IsAuto() {
+			return d.Name.Context().Error("missing value to infer type of '%s'", d.Name.Name).
+				With("type or value required").
+				ByRule(RuleMissingValue, context.Error)
+		}
+	}
+
+	t, err := checkDeclarationType(scope, d)
+	if err != nil {
+		return err
+	}
+
+	kind := SymbolVariable
+	if d.IsConst() {
+		kind = SymbolConstant
+	}
+
+	ref := d.Ref
+	if d.IsRef() && ref == nil {
+		ref = d.Keyword
+	}
+
+	owner, err := checkDeclarationOwnership(scope, d.Name, ref, d.Type, d.Value)
+	if err != nil {
+		return err
+	}
+
+	referent := declareOwnership(scope, owner, d.Value)
+	symbol, err := scope.Declare(d.Name, kind)
+	if symbol != nil {
+		symbol.Owner = owner
+		symbol.Referent = referent
+		symbol.Length = valueLength(scope, d.Type, d.Value)
+		symbol.List = isList(d.Type, d.Value)
+		symbol.Type = t
+		if d.IsAuto() {
+			symbol.Value = expressionValueKind(scope, d.Value)
+
+		} else {
+			symbol.Value = typeValueKind(d.Type)
+		}
+	}
+
+	return err
+}
+
+func checkInferenceDeclaration(scope *Scope, d *ast.InferenceDeclaration) context.DiagnosticInfo {
+	if err := checkExpressionList(scope, d.Values); err != nil {
+		return err
+	}
+
+	names, values := d.Names.Length(), d.Values.Length()
+	_, isCall := d.Values.Expressions[0].Expression.(*ast.CallExpression)
+	if names != values && !(values == 1 && isCall) {
+		return d.Assign.Context().Error("assignment mismatch: %d variables but %d values", names, values).
+			With("SHALL be %d values", names).
+			ByRule(RuleAssignmentMismatch, context.Error)
+	}
+
+	for _, item := range d.Values.Expressions {
+		if err := checkArrayCopy(scope, item.Expression); err != nil {
+			return err
+		}
+	}
+
+	types, err := inferredTypes(scope, d)
+	if err != nil {
+		return err
+	}
+
+	if call, ok := d.Values.Expressions[0].Expression.(*ast.CallExpression); ok && names != values && d.Ref == nil {
+		if err := checkResultsOwnership(scope, call, d.Identifiers(), types); err != nil {
+			return err
+		}
+	}
+
+	for i, name := range d.Identifiers() {
+		var value ast.Expression
+		var t ast.Type
+		if names == values {
+			value = d.Values.Expressions[i].Expression
+
+		} else if d.Ref == nil {
+			// pointers returned by call are owned
+			t = types[i]
+		}
+
+		owner, err := checkDeclarationOwnership(scope, name, d.Ref, t, value)
+		if err != nil {
+			return err
+		}
+
+		referent := declareOwnership(scope, owner, value)
+		symbol, err := scope.Declare(name, SymbolVariable)
+		if err != nil {
+			return err
+		}
+
+		if symbol != nil {
+			symbol.Type = types[i]
+			symbol.Owner = owner
+			symbol.Referent = referent
+		}
+
+		if symbol != nil && value != nil {
+			symbol.Value = expressionValueKind(scope, value)
+			symbol.Length = valueLength(scope, nil, value)
+			symbol.List = isList(nil, value)
+		}
+	}
+
+	return nil
+}
+
+func checkAssignmentStatement(scope *Scope, s *ast.AssignmentStatement) context.DiagnosticInfo {
+	if err := checkExpressionList(scope, s.Values); err != nil {
+		return err
+	}
+
+	targets, values := s.Targets.Length(), s.Values.Length()
+	_, isCall := s.Values.Expressions[0].Expression.(*ast.CallExpression)
+	if targets != values && !(values == 1 && isCall) {
+		return s.Operator.Context().Error("assignment mismatch: %d variables but %d values", targets, values).
+			With("SHALL be %d values", targets).
+			ByRule(RuleAssignmentMismatch, context.Error)
+	}
+
+	for i, target := range s.Targets.Expressions {
+		if err := checkAssignTarget(scope, target.Expression); err != nil {
+			return err
+		}
+
+		if targets == values {
+			if err := checkAssignOwnership(scope, target.Expression, s.Values.Expressions[i].Expression); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := checkAssignmentTypes(scope, s); err != nil {
+		return err
+	}
+
+	if call, ok := s.Values.Expressions[0].Expression.(*ast.CallExpression); ok && targets != values {
+		types, _, err := resultTypes(scope, call)
+		if err != nil {
+			return err
+		}
+
+		exprs := make([]ast.Expression, 0, targets)
+		for _, target := range s.Targets.Expressions {
+			exprs = append(exprs, target.Expression)
+		}
+
+		if err := checkAssignResultsOwnership(scope, call, exprs, types); err != nil {
+			return err
+		}
+	}
+
+	assignOwnership(scope, s)
+
+	return nil
+}
+
+func checkAssignTarget(scope *Scope, target ast.Expression) context.DiagnosticInfo {
+	// a variable assigned is not used, even if it is moved or deleted
+	if id, ok := target.(*ast.Identifier); ok {
+		if err := checkIdentifier(scope, id); err != nil {
+			return err
+		}
+
+	} else if err := checkExpression(scope, target); err != nil {
+		return err
+	}
+
+	root := ast.RootIdentifier(target)
+	if root == nil {
+		return nil
+	}
+
+	symbol, found := scope.Lookup(root.Name)
+	if found && symbol.Kind == SymbolConstant {
+		return target.Context().Error("cannot assign to constant '%s'", root.Name).
+			With("constant can not be modified").
+			ByRule(RuleConstantAssignment, context.Error).
+			For(symbol.Context.Note("declared as constant here"))
+	}
+
+	if found && symbol.Length > 0 && target == ast.Expression(root) {
+		return target.Context().Error("cannot assign to array '%s'", root.Name).
+			With("assign to its elements instead").
+			ByRule(RuleArrayCopy, context.Error).
+			For(symbol.Context.Note("declared here"))
+	}
+
+	return nil
+}
+
+func checkCondition(scope *Scope, keyword *ast.TerminalToken, cond ast.Expression) context.DiagnosticInfo {
+	if err := checkExpression(scope, cond); err != nil {
+		return err
+	}
+
+	if expressionValueKind(scope, cond) == ValueNonBoolean {
+		return cond.Context().Error("non-boolean condition in '%s' statement", keyword.Token).
+			With("condition must be a boolean type").
+			ByRule(RuleNonBooleanCond, context.Error)
+	}
+
+	return checkConditionType(scope, keyword, cond)
+}
+
+// checkBlock checks statements in a block with a new scope nested in scope.
+func checkBlock(scope *Scope, block *ast.BlockStatement) context.DiagnosticInfo {
+	return checkStatements(NewScope(scope), block.Statements)
+}
+
+// checkIfStatement checks branches from the same states of owning pointers, and merges states of branches at the
+// end.
+func checkIfStatement(scope *Scope, s *ast.IfStatement) context.DiagnosticInfo {
+	before := saveOwnerStates(scope)
+	branches := make([]map[*Symbol]ownerState, 0, len(s.Branches)+1)
+	for _, branch := range s.Branches {
+		if err := checkCondition(scope, branch.Keyword, branch.Condition); err != nil {
+			return err
+		}
+
+		if err := checkBlock(scope, branch.Body); err != nil {
+			return err
+		}
+
+		if !IsTerminated(branch.Body.Statements) {
+			branches = append(branches, saveOwnerStates(scope))
+		}
+
+		restoreOwnerStates(before)
+	}
+
+	if s.ElseBody != nil {
+		if err := checkBlock(scope, s.ElseBody); err != nil {
+			return err
+		}
+
+		if !IsTerminated(s.ElseBody.Statements) {
+			branches = append(branches, saveOwnerStates(scope))
+		}
+
+	} else {
+		branches = append(branches, before)
+	}
+
+	mergeOwnerStates(before, branches)
+	return nil
+}
+
+// checkLoopBody checks body of loop, which may be executed or not.
+func checkLoopBody(scope *Scope, body *Scope, stmts []ast.Statement) context.DiagnosticInfo {
+	before := saveOwnerStates(scope)
+	if err := checkStatements(body, stmts); err != nil {
+		return err
+	}
+
+	mergeOwnerStates(before, []map[*Symbol]ownerState{before, saveOwnerStates(scope)})
+	return nil
+}
+
+func checkWhileStatement(scope *Scope, s *ast.WhileStatement) context.DiagnosticInfo {
+	if err := checkCondition(scope, s.Keyword, s.Condition); err != nil {
+		return err
+	}
+
+	return checkLoopBody(scope, NewLoopScope(scope), s.Body.Statements)
+}
+
+func checkDoWhileStatement(scope *Scope, s *ast.DoWhileStatement) context.DiagnosticInfo {
+	if err := checkStatements(NewLoopScope(scope), s.Body.Statements); err != nil {
+		return err
+	}
+
+	return checkCondition(scope, s.While, s.Condition)
+}
+
+// checkForHeader checks initializer and post statement of for loop, which are output as C expressions.
+func checkForHeader(stmt ast.Statement, isPost bool) context.DiagnosticInfo {
+	count := 1
+	switch s := stmt.(type) {
+	case *ast.VariableDeclaration, *ast.InferenceDeclaration:
+		if isPost {
+			return stmt.Context().Error("declaration in post statement of for loop").
+				With("expect an assignment or increment").
+				ByRule(RuleLoopHeader, context.Error)
+		}
+
+		if d, ok := s.(*ast.InferenceDeclaration); ok {
+			count = d.Names.Length()
+		}
+
+	case *ast.AssignmentStatement:
+		count = s.Targets.Length()
+	}
+
+	if count > 1 {
+		return stmt.Context().Error("multiple variables in header of for loop").
+			With("SHALL be one variable").
+			ByRule(RuleLoopHeader, context.Error)
+	}
+
+	return nil
+}
+
+func checkForStatement(scope *Scope, s *ast.ForStatement) context.DiagnosticInfo {
+	header := NewScope(scope)
+	if s.Initializer != nil {
+		if err := checkForHeader(s.Initializer, false); err != nil {
+			return err
+		}
+
+		if err := checkStatements(header, []ast.Statement{s.Initializer}); err != nil {
+			return err
+		}
+	}
+
+	if s.Condition != nil {
+		if err := checkCondition(header, s.Keyword, s.Condition); err != nil {
+			return err
+		}
+	}
+
+	if s.Post != nil {
+		if err := checkForHeader(s.Post, true); err != nil {
+			return err
+		}
+
+		if err := checkStatements(header, []ast.Statement{s.Post}); err != nil {
+			return err
+		}
+	}
+
+	return checkLoopBody(scope, NewLoopScope(header), s.Body.Statements)
+}
+
+func checkForeachStatement(scope *Scope, s *ast.ForeachStatement) context.DiagnosticInfo {
+	if err := checkExpression(scope, s.Iterable); err != nil {
+		return err
+	}
+
+	element, err := iterableElement(scope, s.Iterable)
+	if err != nil {
+		return err
+	}
+
+	body := NewLoopScope(scope)
+	symbol, err := body.Declare(s.Variable, SymbolVariable)
+	if err != nil {
+		return err
+	}
+
+	if symbol != nil {
+		symbol.Type = element
+	}
+
+	return checkLoopBody(scope, body, s.Body.Statements)
+}
+
+func checkLoopControlStatement(scope *Scope, s *ast.LoopControlStatement) context.DiagnosticInfo {
+	if !scope.InLoop() {
+		return s.Context().Error("'%s' statement not in loop", s.Keyword.Token).
+			With("only allowed in while, do-while, for and foreach").
+			ByRule(RuleBranchOutsideLoop, context.Error)
+	}
+
+	return nil
+}
+
+func checkStatements(scope *Scope, stmts []ast.Statement) context.DiagnosticInfo {
+	for _, stmt := range stmts {
+		var err context.DiagnosticInfo
+		switch s := stmt.(type) {
+		case *ast.VariableDeclaration:
+			err = checkVariableDeclaration(scope, s)
+
+		case *ast.InferenceDeclaration:
+			err = checkInferenceDeclaration(scope, s)
+
+		case *ast.AssignmentStatement:
+			err = checkAssignmentStatement(scope, s)
+
+		case *ast.IncrementStatement:
+			err = checkAssignTarget(scope, s.Target)
+			if err == nil {
+				err = checkOperand(scope, s.Target, s.Operator, ClassFloat)
+			}
+
+		case *ast.ExpressionStatement:
+			err = checkExpression(scope, s.Expression)
+			if err == nil {
+				// values returned by call are discarded
+				_, _, err = resultTypes(scope, s.Expression)
+			}
+
+		case *ast.ReturnStatement:
+			err = checkExpressionList(scope, s.Value)
+			if err == nil {
+				err = checkReturnOwnership(scope, s)
+			}
+
+			if err == nil {
+				err = checkReturnTypes(scope, s)
+			}
+
+		case *ast.GlobalDeclaration:
+			err = checkGlobalAccessStatement(scope, s)
+
+		case *ast.IfStatement:
+			err = checkIfStatement(scope, s)
+
+		case *ast.WhileStatement:
+			err = checkWhileStatement(scope, s)
+
+		case *ast.DoWhileStatement:
+			err = checkDoWhileStatement(scope, s)
+
+		case *ast.ForStatement:
+			err = checkForStatement(scope, s)
+
+		case *ast.ForeachStatement:
+			err = checkForeachStatement(scope, s)
+
+		case *ast.LoopControlStatement:
+			err = checkLoopControlStatement(scope, s)
+
+		case *ast.DeleteStatement:
+			err = checkDeleteStatement(scope, s)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFunctionBody checks statements of function, in a scope nested in scope of global variables.
+func checkFunctionBody(globals *Scope, d *ast.FunctionDeclaration) context.DiagnosticInfo {
+	scope := NewScope(globals)
+	scope.Function = d
+	for _, g := range d.GlobalAccesses() {
+		if err := declareGlobalAccess(scope, g.Name, g.Type); err != nil {
+			return err
+		}
+	}
+
+	if d.Receiver != nil {
+		if symbol, _ := scope.Declare(d.Receiver.Name, SymbolArgument); symbol != nil {
+			symbol.Type = d.Receiver.Type
+			symbol.Value = typeValueKind(d.Receiver.Type)
+			symbol.Owner = argumentOwnership(d.Receiver.Type)
+		}
+	}
+
+	if d.Arguments != nil {
+		for _, arg := range d.Arguments.Arguments {
+			// duplicated arguments are reported by checkFunctionDeclarationNameDuplicate
+			if symbol, _ := scope.Declare(arg.Name, SymbolArgument); symbol != nil {
+				symbol.Type = arg.Type
+				symbol.Value = typeValueKind(arg.Type)
+				symbol.Owner = argumentOwnership(arg.Type)
+			}
+		}
+	}
+
+	return checkStatements(scope, d.Statements)
+}
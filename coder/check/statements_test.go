@@ -0,0 +1,350 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckVariableDeclarationsCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    const c int32 = a + b",
+		"    var d int32",
+		"    e, _ := c, d",
+		"    q, r := divmod(e, 2)",
+		"    return q + r",
+		"}",
+		"fun divmod(a int, b int) (int, int) {",
+		"    return a / b, a % b",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckVariableDeclarationDuplicated(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    var c = a",
+		"    const c int = b",
+		"    return c",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:11: error: duplicated variable name: 'c' [duplicated-name]",
+		"    3 |     const c int = b",
+		"      |           ^",
+		"      |           duplicated name",
+		"test.mc:2:9: note: first declared here",
+		"    2 |     var c = a",
+		"      |         ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckVariableDeclarationDuplicatedWithArgument(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    x, b := a, a",
+		"    return x + b",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:8: error: duplicated variable name: 'b' [duplicated-name]",
+		"    2 |     x, b := a, a",
+		"      |        ^",
+		"      |        duplicated name",
+		"test.mc:1:16: note: first declared here",
+		"    1 | fun add(a int, b int) (int) {",
+		"      |                ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckConstDeclarationWithoutValue(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    const c int32",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:11: error: missing value in const declaration of 'c' [missing-value]",
+		"    2 |     const c int32",
+		"      |           ^",
+		"      |           constant must be initialized",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckAutoDeclarationWithoutValue(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    var v auto",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:9: error: missing value to infer type of 'v' [missing-value]",
+		"    2 |     var v auto",
+		"      |         ^",
+		"      |         type or value required",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckInferenceDeclarationCountMismatch(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    a, b, c := 1, 2",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:13: error: assignment mismatch: 3 variables but 2 values [assignment-mismatch]",
+		"    2 |     a, b, c := 1, 2",
+		"      |             ^^",
+		"      |             SHALL be 3 values",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckAssignmentCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    var c int32 = a",
+		"    c += b",
+		"    a, b = b, a",
+		"    a, _ = divmod(a, b)",
+		"    return c",
+		"}",
+		"fun divmod(a int, b int) (int, int) {",
+		"    return a / b, a % b",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckAssignmentToConstant(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    const c int = a",
+		"    c += b",
+		"    return c",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:5: error: cannot assign to constant 'c' [constant-assignment]",
+		"    3 |     c += b",
+		"      |     ^",
+		"      |     constant can not be modified",
+		"test.mc:2:11: note: declared as constant here",
+		"    2 |     const c int = a",
+		"      |           ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckAssignmentMismatch(t *testing.T) {
+	code := strings.Join([]string{
+		"fun add(a int, b int) (int) {",
+		"    a, b = 1, 2, 3",
+		"    return a",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:10: error: assignment mismatch: 2 variables but 3 values [assignment-mismatch]",
+		"    2 |     a, b = 1, 2, 3",
+		"      |          ^",
+		"      |          SHALL be 2 values",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckIfStatementCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sign(a int, ok bool) (int) {",
+		"    done := a == 0",
+		"    if (a > 0) {",
+		"        var r = 1",
+		"        return r",
+		"    } elif not ok or done {",
+		"        var r = 0",
+		"        return r",
+		"    } elif f(a) {",
+		"        return 2",
+		"    }",
+		"    return -1",
+		"}",
+		"fun f(a int) (bool) {",
+		"    return a > 10",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckIfStatementImplicitCondition(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sign(a int) (int) {",
+		"    if a > 0 {",
+		"        return 1",
+		"    } elif (a) {",
+		"        return 0",
+		"    }",
+		"    return -1",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:4:12: error: non-boolean condition in 'elif' statement [non-boolean-condition]",
+		"    4 |     } elif (a) {",
+		"      |            ^^^",
+		"      |            condition must be a boolean type",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckIfStatementNestedScope(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sign(a int) (int) {",
+		"    const c = 1",
+		"    if a > 0 {",
+		"        var b = a",
+		"        var b = c",
+		"    }",
+		"    return c",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:5:13: error: duplicated variable name: 'b' [duplicated-name]",
+		"    5 |         var b = c",
+		"      |             ^",
+		"      |             duplicated name",
+		"test.mc:4:13: note: first declared here",
+		"    4 |         var b = a",
+		"      |             ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckLoopStatementsCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sum(n int) (int) {",
+		"    var s = 0",
+		"    for (i := 0; i < n; i++) {",
+		"        if i == 3 {",
+		"            continue",
+		"        }",
+		"        s += i",
+		"    }",
+		"    while s > 100 {",
+		"        s -= 100",
+		"    }",
+		"    do {",
+		"        break",
+		"    } while (true)",
+		"    return s",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckBreakOutsideLoop(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sum(n int) (int) {",
+		"    while n > 0 {",
+		"        n--",
+		"    }",
+		"    if n == 0 {",
+		"        break",
+		"    }",
+		"    return n",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:6:9: error: 'break' statement not in loop [branch-outside-loop]",
+		"    6 |         break",
+		"      |         ^^^^^",
+		"      |         only allowed in while, do-while, for and foreach",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckLoopConditionNotBoolean(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sum(n int) (int) {",
+		"    for (i := 0; i; i++) {",
+		"    }",
+		"    return n",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:18: error: non-boolean condition in 'for' statement [non-boolean-condition]",
+		"    2 |     for (i := 0; i; i++) {",
+		"      |                  ^",
+		"      |                  condition must be a boolean type",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckForHeaderWithMultipleVariables(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sum(n int) (int) {",
+		"    for (i, j := 0, n; i < j; i++) {",
+		"    }",
+		"    return n",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:10: error: multiple variables in header of for loop [loop-header]",
+		"    2 |     for (i, j := 0, n; i < j; i++) {",
+		"      |          ^^ ^ ^^ ^^ ^",
+		"      |          SHALL be one variable",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckIncrementConstant(t *testing.T) {
+	code := strings.Join([]string{
+		"fun sum(n int) (int) {",
+		"    const c = 1",
+		"    c++",
+		"    return n",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:5: error: cannot assign to constant 'c' [constant-assignment]",
+		"    3 |     c++",
+		"      |     ^",
+		"      |     constant can not be modified",
+		"test.mc:2:11: note: declared as constant here",
+		"    2 |     const c = 1",
+		"      |           ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
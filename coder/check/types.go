@@ -0,0 +1,232 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func checkStructFieldNameDuplicate(d *ast.TypeDeclaration) context.DiagnosticInfo {
+	s, ok := d.StructType()
+	if !ok {
+		return nil
+	}
+
+	nameMaps := make(map[string]*context.Context)
+	for _, field := range s.Fields {
+		name := field.Name
+		if ctx, found := nameMaps[name.Name]; found {
+			err := name.Context().Error("duplicated field name: '%s'", name.Name).
+				With("duplicated name").
+				ByRule(RuleDuplicatedName, context.Error).
+				For(ctx.Note("first declared here"))
+			return err
+		}
+
+		nameMaps[name.Name] = name.Context()
+	}
+
+	return nil
+}
+
+func checkStructFieldTypes(globals *Scope, d *ast.TypeDeclaration) context.DiagnosticInfo {
+	s, ok := d.StructType()
+	if !ok {
+		return nil
+	}
+
+	for _, field := range s.Fields {
+		if err := checkType(globals, field.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConversionCall returns name of basic type which e converts its argument to, like 'int16(a)'.
+func ConversionCall(e *ast.CallExpression) (string, bool) {
+	id, ok := e.Callee.(*ast.Identifier)
+	if !ok || !isBasicType(id.Name) {
+		return "", false
+	}
+
+	return id.Name, true
+}
+
+// structOf returns structure of a type declared in document, aliases are followed. Declaration of the type is
+// returned if it is not a structure, and nil is returned if the type is unknown.
+func structOf(scope *Scope, name string) (*ast.TypeDeclaration, *ast.StructType, bool) {
+	types := scope.Root().Types
+	var prev *ast.TypeDeclaration
+	for i := 0; i <= len(types); i++ {
+		d, found := types[name]
+		if !found {
+			if prev != nil && isBasicType(name) {
+				return prev, nil, false
+			}
+
+			return nil, nil, false
+		}
+
+		if s, ok := d.StructType(); ok {
+			return d, s, true
+		}
+
+		alias, ok := d.Definition.(*ast.SimpleType)
+		if !ok || len(alias.PointerAsterisk) > 0 {
+			return d, nil, false
+		}
+
+		prev, name = d, alias.Identifier.Name
+	}
+
+	// aliases in cycle are reported by checks of types
+	return nil, nil, false
+}
+
+// checkStructLiteral checks fields in struct literal SHALL be declared by the structure and initialized at most once.
+// Types not declared in document, like those of C headers, are not checked.
+func checkStructLiteral(scope *Scope, l *ast.StructLiteral) context.DiagnosticInfo {
+	for _, field := range l.Fields {
+		if err := checkExpression(scope, field.Value); err != nil {
+			return err
+		}
+	}
+
+	name := l.Type.Identifier.Name
+	d, s, found := structOf(scope, name)
+	if !found {
+		if d != nil {
+			return l.Type.Context().Error("invalid struct literal of non-structure type '%s'", name).
+				With("SHALL be a structure").
+				ByRule(RuleStructLiteral, context.Error).
+				For(d.Name.Context().Note("declared here"))
+		}
+
+		return nil
+	}
+
+	initialized := make(map[string]*context.Context)
+	for _, field := range l.Fields {
+		if first, found := initialized[field.Name.Name]; found {
+			return field.Name.Context().Error("duplicated field '%s' in struct literal", field.Name.Name).
+				With("duplicated field").
+				ByRule(RuleDuplicatedName, context.Error).
+				For(first.Note("first initialized here"))
+		}
+
+		if _, found := s.Field(field.Name.Name); !found {
+			return field.Name.Context().Error("unknown field '%s' in struct literal of '%s'", field.Name.Name, name).
+				With("no such field").
+				ByRule(RuleStructLiteral, context.Error).
+				For(d.Name.Context().Note("'%s' declared here", name))
+		}
+
+		initialized[field.Name.Name] = field.Name.Context()
+	}
+
+	return nil
+}
+
+func checkTypeDeclaration(conf *CheckConfigure, globals *Scope, d *ast.TypeDeclaration) *context.DiagnosticContainer {
+	l := NewCheckList(
+		checkStructFieldNameDuplicate,
+		func(d *ast.TypeDeclaration) context.DiagnosticInfo {
+			return checkStructFieldTypes(globals, d)
+		},
+	)
+
+	return l.Check(conf, d)
+}
+
+// valueDependencies returns types contained by value, fields of pointers are not included.
+func valueDependencies(d *ast.TypeDeclaration) []*ast.SimpleType {
+	if s, ok := d.StructType(); ok {
+		result := make([]*ast.SimpleType, 0, len(s.Fields))
+		for _, field := range s.Fields {
+			if t := ast.ValueType(field.Type); t != nil {
+				result = append(result, t)
+			}
+		}
+
+		return result
+	}
+
+	if t, ok := d.Definition.(*ast.SimpleType); ok && len(t.PointerAsterisk) == 0 {
+		return []*ast.SimpleType{t}
+	}
+
+	return nil
+}
+
+func checkTypeNameDuplicate(types map[string]*ast.TypeDeclaration, doc *ast.Document) context.DiagnosticInfo {
+	for _, decl := range doc.Declarations {
+		d, ok := decl.(*ast.TypeDeclaration)
+		if !ok {
+			continue
+		}
+
+		name := d.Name
+		if first, found := types[name.Name]; found {
+			err := name.Context().Error("duplicated type name: '%s'", name.Name).
+				With("duplicated name").
+				ByRule(RuleDuplicatedName, context.Error).
+				For(first.Name.Context().Note("first declared here"))
+			return err
+		}
+
+		types[name.Name] = d
+	}
+
+	return nil
+}
+
+// checkTypeRecursive finds types contain themselves by value, which have infinite size.
+func checkTypeRecursive(types map[string]*ast.TypeDeclaration, doc *ast.Document) context.DiagnosticInfo {
+	for _, decl := range doc.Declarations {
+		d, ok := decl.(*ast.TypeDeclaration)
+		if !ok {
+			continue
+		}
+
+		visited := make(map[string]bool)
+		var contains func(t *ast.TypeDeclaration) *ast.SimpleType
+		contains = func(t *ast.TypeDeclaration) *ast.SimpleType {
+			for _, dep := range valueDependencies(t) {
+				name := dep.Identifier.Name
+				if name == d.Name.Name {
+					return dep
+				}
+
+				if next, found := types[name]; found && !visited[name] {
+					visited[name] = true
+					if contains(next) != nil {
+						return dep
+					}
+				}
+			}
+
+			return nil
+		}
+
+		if dep := contains(d); dep != nil {
+			err := d.Name.Context().Error("invalid recursive type '%s'", d.Name.Name).
+				With("type contains itself by value").
+				ByRule(RuleRecursiveType, context.Error).
+				For(dep.Context().Note("use a pointer here to refer to '%s'", d.Name.Name))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkDocumentTypes(conf *CheckConfigure, doc *ast.Document) *context.DiagnosticContainer {
+	types := make(map[string]*ast.TypeDeclaration)
+	l := NewCheckList(
+		func(doc *ast.Document) context.DiagnosticInfo { return checkTypeNameDuplicate(types, doc) },
+		func(doc *ast.Document) context.DiagnosticInfo { return checkTypeRecursive(types, doc) },
+	)
+
+	return l.Check(conf, doc)
+}
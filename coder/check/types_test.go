@@ -0,0 +1,160 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTypeDeclarationsCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Node struct {",
+		"    value int",
+		"    next  *Node",
+		"}",
+		"",
+		"struct List { head Node; size int }",
+		"",
+		"type Celsius float64",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckStructFieldNameDuplicated(t *testing.T) {
+	code := strings.Join([]string{
+		"struct Point { x int; y int; x int }",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:1:30: error: duplicated field name: 'x' [duplicated-name]",
+		"    1 | struct Point { x int; y int; x int }",
+		"      |                              ^",
+		"      |                              duplicated name",
+		"test.mc:1:16: note: first declared here",
+		"    1 | struct Point { x int; y int; x int }",
+		"      |                ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckTypeNameDuplicated(t *testing.T) {
+	code := strings.Join([]string{
+		"struct Point { x int }",
+		"type Point int",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:6: error: duplicated type name: 'Point' [duplicated-name]",
+		"    2 | type Point int",
+		"      |      ^^^^^",
+		"      |      duplicated name",
+		"test.mc:1:8: note: first declared here",
+		"    1 | struct Point { x int }",
+		"      |        ^^^^^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckTypeRecursive(t *testing.T) {
+	code := strings.Join([]string{
+		"struct A { b B }",
+		"struct B { a A }",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:1:8: error: invalid recursive type 'A' [recursive-type]",
+		"    1 | struct A { b B }",
+		"      |        ^",
+		"      |        type contains itself by value",
+		"test.mc:1:14: note: use a pointer here to refer to 'A'",
+		"    1 | struct A { b B }",
+		"      |              ^",
+	}, "\n")
+
+	checkCodeError(t, code, expected)
+}
+
+func TestCheckStructLiteralCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Point struct {",
+		"    x int32",
+		"    y int32",
+		"}",
+		"type Vertex Point",
+		"global var origin Point = Point{x: 0, y: 0}",
+		"fun main() {",
+		"    p := Point{x: 1}",
+		"    var v Vertex = Vertex{y: p.x}",
+		"    q := new Point{y: 2}",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckStructLiteralErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"struct Point { x int32; y int32 }",
+				"fun main() {",
+				"    p := Point{x: 1, x: 2}",
+				"}",
+			},
+			[]string{
+				"test.mc:3:22: error: duplicated field 'x' in struct literal [duplicated-name]",
+				"    3 |     p := Point{x: 1, x: 2}",
+				"      |                      ^",
+				"      |                      duplicated field",
+				"test.mc:3:16: note: first initialized here",
+				"    3 |     p := Point{x: 1, x: 2}",
+				"      |                ^",
+			},
+		},
+		{
+			[]string{
+				"struct Point { x int32; y int32 }",
+				"fun main() {",
+				"    p := new Point{z: 1}",
+				"}",
+			},
+			[]string{
+				"test.mc:3:20: error: unknown field 'z' in struct literal of 'Point' [struct-literal]",
+				"    3 |     p := new Point{z: 1}",
+				"      |                    ^",
+				"      |                    no such field",
+				"test.mc:1:8: note: 'Point' declared here",
+				"    1 | struct Point { x int32; y int32 }",
+				"      |        ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"type Id uint32",
+				"fun main() {",
+				"    n := Id{x: 1}",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: invalid struct literal of non-structure type 'Id' [struct-literal]",
+				"    3 |     n := Id{x: 1}",
+				"      |          ^^",
+				"      |          SHALL be a structure",
+				"test.mc:1:6: note: declared here",
+				"    1 | type Id uint32",
+				"      |      ^^",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join(c.code, "\n")
+		expected := strings.Join(c.expected, "\n")
+		checkCodeError(t, code, expected)
+	}
+}
@@ -0,0 +1,1198 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+// TypeInfo records types of expressions resolved by checker, which are consumed by coder. Expressions in unknown
+// types, like values returned by C functions, are not recorded.
+type TypeInfo struct {
+	types map[ast.Expression]ast.Type
+}
+
+func NewTypeInfo() *TypeInfo {
+	i := &TypeInfo{
+		types: make(map[ast.Expression]ast.Type),
+	}
+
+	return i
+}
+
+// TypeOf returns type of expression resolved by checker, TypeInfo may be nil and no type is known.
+func (i *TypeInfo) TypeOf(expr ast.Expression) (ast.Type, bool) {
+	if i == nil {
+		return nil, false
+	}
+
+	t, found := i.types[expr]
+	return t, found
+}
+
+func (i *TypeInfo) record(expr ast.Expression, t ast.Type) {
+	if i != nil && t != nil {
+		i.types[expr] = t
+	}
+}
+
+// TypeClass is the kind of values in a type, which decides operations allowed on them.
+type TypeClass int
+
+const (
+	ClassUnknown TypeClass = iota
+	ClassInteger
+	ClassFloat
+	ClassBoolean
+	ClassPointer
+	ClassArray
+	ClassList
+	ClassStruct
+)
+
+var integerTypes = []string{"int", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64"}
+
+func (c TypeClass) isNumeric() bool {
+	return c == ClassInteger || c == ClassFloat
+}
+
+// isScalar tells whether values of class are used as truth values in C.
+func (c TypeClass) isScalar() bool {
+	return c.isNumeric() || c == ClassBoolean || c == ClassPointer
+}
+
+// TypeName formats type as in source, like '*Point', 'int32[4]' and 'uint8[]'.
+func TypeName(t ast.Type) string {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		return strings.Repeat("*", len(typ.PointerAsterisk)) + typ.Identifier.Name
+
+	case *ast.ArrayType:
+		size := "..."
+		if n, ok := ConstantInteger(typ.Size); ok {
+			size = fmt.Sprintf("%d", n)
+		}
+
+		return fmt.Sprintf("%s[%s]", TypeName(typ.ElementType), size)
+
+	case *ast.ListType:
+		return TypeName(typ.ElementType) + "[]"
+	}
+
+	return "unknown"
+}
+
+func newSimpleType(level int, name string) *ast.SimpleType {
+	asterisks := make([]*ast.TerminalToken, level)
+	for i := range asterisks {
+		asterisks[i] = ast.ASTBuildSymbol(ast.Asterisk)
+	}
+
+	return ast.NewSimpleType(asterisks, ast.ASTBuildIdentifier(name))
+}
+
+// pointerTo returns type of pointer to t, address of an array points to its first element, and pointers to lists are
+// not supported.
+func pointerTo(t ast.Type) ast.Type {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		return newSimpleType(len(typ.PointerAsterisk)+1, typ.Identifier.Name)
+
+	case *ast.ArrayType:
+		return pointerTo(typ.ElementType)
+	}
+
+	return nil
+}
+
+// underlying follows aliases of a type declared in document to the type defined, structures are named types and
+// are not followed.
+func underlying(scope *Scope, t ast.Type) ast.Type {
+	types := scope.Root().Types
+	for i := 0; i <= len(types); i++ {
+		s, ok := t.(*ast.SimpleType)
+		if !ok {
+			return t
+		}
+
+		d, found := types[s.Identifier.Name]
+		if !found {
+			return t
+		}
+
+		if _, isStruct := d.StructType(); isStruct {
+			return t
+		}
+
+		switch def := d.Definition.(type) {
+		case *ast.SimpleType:
+			t = newSimpleType(len(s.PointerAsterisk)+len(def.PointerAsterisk), def.Identifier.Name)
+
+		default:
+			if len(s.PointerAsterisk) > 0 {
+				return t
+			}
+
+			t = def
+		}
+	}
+
+	// aliases in cycle are reported by checks of types
+	return t
+}
+
+// classOf returns class of values in type t, types not declared in document, like those of C headers, are unknown.
+func classOf(scope *Scope, t ast.Type) TypeClass {
+	switch typ := underlying(scope, t).(type) {
+	case *ast.ArrayType:
+		return ClassArray
+
+	case *ast.ListType:
+		return ClassList
+
+	case *ast.SimpleType:
+		name := typ.Identifier.Name
+		switch {
+		case len(typ.PointerAsterisk) > 0:
+			return ClassPointer
+
+		case name == "bool":
+			return ClassBoolean
+
+		case name == "float32" || name == "float64":
+			return ClassFloat
+
+		case isIntegerType(name):
+			return ClassInteger
+		}
+
+		if _, _, found := structOf(scope, name); found {
+			return ClassStruct
+		}
+	}
+
+	return ClassUnknown
+}
+
+func isIntegerType(name string) bool {
+	for _, t := range integerTypes {
+		if t == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identical tells whether two types are the same after aliases are followed, sizes of arrays are not compared.
+func identical(scope *Scope, a ast.Type, b ast.Type) bool {
+	a, b = underlying(scope, a), underlying(scope, b)
+	switch ta := a.(type) {
+	case *ast.SimpleType:
+		tb, ok := b.(*ast.SimpleType)
+		return ok && ta.Identifier.Name == tb.Identifier.Name && len(ta.PointerAsterisk) == len(tb.PointerAsterisk)
+
+	case *ast.ArrayType:
+		tb, ok := b.(*ast.ArrayType)
+		return ok && identical(scope, ta.ElementType, tb.ElementType)
+
+	case *ast.ListType:
+		tb, ok := b.(*ast.ListType)
+		return ok && identical(scope, ta.ElementType, tb.ElementType)
+	}
+
+	return false
+}
+
+// elementOf returns type of elements indexed in arrays, lists and pointers, nil is returned for other types.
+func elementOf(scope *Scope, t ast.Type) ast.Type {
+	switch typ := underlying(scope, t).(type) {
+	case *ast.ArrayType:
+		return typ.ElementType
+
+	case *ast.ListType:
+		return typ.ElementType
+
+	case *ast.SimpleType:
+		return dereference(typ)
+	}
+
+	return nil
+}
+
+// isNull tells whether expr is null literal, which is assignable to pointers and lists.
+func isNull(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.NullLiteral:
+		return true
+
+	case *ast.ParenthesizedExpression:
+		return isNull(e.Expression)
+	}
+
+	return false
+}
+
+// isUntypedConstant tells whether expr is a numeric constant, which takes type of the other operand.
+func isUntypedConstant(scope *Scope, expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.FloatLiteral:
+		return true
+
+	case *ast.ParenthesizedExpression:
+		return isUntypedConstant(scope, e.Expression)
+	}
+
+	_, ok := scope.ConstantInteger(expr)
+	return ok
+}
+
+// assignable tells whether value of type vt can be assigned to type t. Numbers are converted implicitly like in C,
+// and values of unknown types are not checked.
+func assignable(scope *Scope, value ast.Expression, vt ast.Type, t ast.Type) bool {
+	target := classOf(scope, t)
+	if isNull(value) {
+		return target == ClassUnknown || target == ClassPointer || target == ClassList
+	}
+
+	source := classOf(scope, vt)
+	if source == ClassUnknown || target == ClassUnknown {
+		return true
+	}
+
+	if source.isNumeric() && target.isNumeric() {
+		return true
+	}
+
+	if source != target {
+		return false
+	}
+
+	if source == ClassPointer && (classOf(scope, dereference(vt)) == ClassUnknown ||
+		classOf(scope, dereference(t)) == ClassUnknown) {
+		// pointers to types out of document, like those in C headers, are not checked
+		return true
+	}
+
+	return identical(scope, vt, t)
+}
+
+// mismatchedValue reports value of type vt which can not be used as type t.
+func mismatchedValue(value ast.Expression, vt ast.Type, t ast.Type, usage string) *context.Diagnostic {
+	return value.Context().Error("cannot use value of type '%s' as '%s' in %s", TypeName(vt), TypeName(t), usage).
+		With("SHALL be '%s'", TypeName(t)).
+		ByRule(RuleTypeMismatch, context.Error)
+}
+
+// checkValueType checks type of value assignable to type t, and returns type of value.
+func checkValueType(scope *Scope, t ast.Type, value ast.Expression, usage string) (ast.Type, context.DiagnosticInfo) {
+	vt, err := typeOf(scope, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if t != nil && !assignable(scope, value, vt, t) {
+		if isNull(value) {
+			return nil, value.Context().Error("cannot use null as '%s' in %s", TypeName(t), usage).
+				With("SHALL be a pointer or a list").
+				ByRule(RuleTypeMismatch, context.Error)
+		}
+
+		return nil, mismatchedValue(value, vt, t, usage)
+	}
+
+	return vt, nil
+}
+
+// typeOf resolves type of expression and checks types of operands, nil is returned if the type is unknown. Types
+// resolved are recorded for coder.
+func typeOf(scope *Scope, expr ast.Expression) (ast.Type, context.DiagnosticInfo) {
+	t, err := resolveType(scope, expr)
+	if err == nil {
+		scope.Root().Info.record(expr, t)
+	}
+
+	return t, err
+}
+
+func resolveType(scope *Scope, expr ast.Expression) (ast.Type, context.DiagnosticInfo) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return ast.ASTBuildSimpleType("int"), nil
+
+	case *ast.FloatLiteral:
+		return ast.ASTBuildSimpleType("float64"), nil
+
+	case *ast.BooleanLiteral:
+		return ast.ASTBuildSimpleType("bool"), nil
+
+	case *ast.StringLiteral:
+		// characters are in C type, which is not checked
+		return newSimpleType(1, "char"), nil
+
+	case *ast.SizeofExpression:
+		return ast.ASTBuildSimpleType("uint64"), nil
+
+	case *ast.Identifier:
+		if symbol, found := scope.Lookup(e.Name); found && symbol.Kind != SymbolModule {
+			return symbol.Type, nil
+		}
+
+	case *ast.ParenthesizedExpression:
+		return typeOf(scope, e.Expression)
+
+	case *ast.PrefixExpression:
+		return prefixType(scope, e)
+
+	case *ast.InfixExpression:
+		return infixType(scope, e)
+
+	case *ast.CallExpression:
+		return callType(scope, e)
+
+	case *ast.MemberExpression:
+		return memberType(scope, e)
+
+	case *ast.IndexExpression:
+		return indexType(scope, e)
+
+	case *ast.ArrayLiteral:
+		return arrayLiteralType(scope, e)
+
+	case *ast.StructLiteral:
+		return structLiteralType(scope, e)
+
+	case *ast.NewExpression:
+		return newType(scope, e)
+
+	case *ast.PointerArithmeticExpression:
+		if err := checkOperand(scope, e.Offset, e.Operator, ClassInteger); err != nil {
+			return nil, err
+		}
+
+		return typeOf(scope, e.Pointer)
+	}
+
+	return nil, nil
+}
+
+// invalidOperand reports operand in type t which is not allowed by operator.
+func invalidOperand(operand ast.Expression, operator *ast.TerminalToken, t ast.Type, expected string) *context.Diagnostic {
+	return operand.Context().Error("invalid operation: operator '%s' on value of type '%s'", operator.Token, TypeName(t)).
+		With("SHALL be %s", expected).
+		ByRule(RuleInvalidOperation, context.Error)
+}
+
+var classNames = map[TypeClass]string{
+	ClassInteger: "an integer",
+	ClassFloat:   "a number",
+	ClassBoolean: "a boolean",
+	ClassPointer: "a pointer",
+}
+
+// checkOperand checks operand of operator is in class, integers are also allowed for numbers.
+func checkOperand(scope *Scope, operand ast.Expression, operator *ast.TerminalToken, class TypeClass) context.DiagnosticInfo {
+	t, err := typeOf(scope, operand)
+	if err != nil {
+		return err
+	}
+
+	return checkOperandType(scope, operand, t, operator, class)
+}
+
+// checkOperandType checks operand of operator in type t like checkOperand, whose type is already resolved.
+func checkOperandType(scope *Scope, operand ast.Expression, t ast.Type, operator *ast.TerminalToken, class TypeClass) context.DiagnosticInfo {
+	c := classOf(scope, t)
+	switch {
+	case c == ClassUnknown || c == class:
+		return nil
+
+	case class == ClassFloat && c.isNumeric():
+		return nil
+	}
+
+	return invalidOperand(operand, operator, t, classNames[class])
+}
+
+func prefixType(scope *Scope, e *ast.PrefixExpression) (ast.Type, context.DiagnosticInfo) {
+	t, err := typeOf(scope, e.Operand)
+	if err != nil {
+		return nil, err
+	}
+
+	c := classOf(scope, t)
+	if c == ClassUnknown {
+		if e.Operator.Token == ast.Not {
+			return ast.ASTBuildSimpleType("bool"), nil
+		}
+
+		if e.Operator.Token == ast.Ampersand {
+			return pointerTo(t), nil
+		}
+
+		return nil, nil
+	}
+
+	switch e.Operator.Token {
+	case ast.Sub, ast.Plus:
+		if !c.isNumeric() {
+			return nil, invalidOperand(e.Operand, e.Operator, t, "a number")
+		}
+
+	case ast.Tilde:
+		if c != ClassInteger {
+			return nil, invalidOperand(e.Operand, e.Operator, t, "an integer")
+		}
+
+	case ast.Not:
+		if !c.isScalar() {
+			return nil, invalidOperand(e.Operand, e.Operator, t, "a boolean")
+		}
+
+		return ast.ASTBuildSimpleType("bool"), nil
+
+	case ast.Asterisk:
+		if c != ClassPointer {
+			return nil, e.Operand.Context().Error("invalid indirect of value of type '%s'", TypeName(t)).
+				With("SHALL be a pointer").
+				ByRule(RuleInvalidOperation, context.Error)
+		}
+
+		return dereference(underlying(scope, t)), nil
+
+	case ast.Ampersand:
+		return pointerTo(t), nil
+
+	case ast.Ref:
+		if c != ClassPointer && c != ClassList {
+			return nil, invalidOperand(e.Operand, e.Operator, t, "a pointer")
+		}
+	}
+
+	return t, nil
+}
+
+// numericRanks orders basic numeric types for arithmetic conversions, floats are wider than integers, and unsigned
+// integers are wider than signed ones in the same size like in C.
+var numericRanks = map[string]int{
+	"int8":    1,
+	"uint8":   2,
+	"int16":   3,
+	"uint16":  4,
+	"int":     5,
+	"int32":   5,
+	"uint32":  6,
+	"int64":   7,
+	"uint64":  8,
+	"float32": 9,
+	"float64": 10,
+}
+
+// numericRank returns rank of numeric type t after aliases are followed, 0 is returned for types not ranked.
+func numericRank(scope *Scope, t ast.Type) int {
+	if s, ok := underlying(scope, t).(*ast.SimpleType); ok && len(s.PointerAsterisk) == 0 {
+		return numericRanks[s.Identifier.Name]
+	}
+
+	return 0
+}
+
+// numericResult returns type of arithmetic on numbers with the usual arithmetic conversions, the wider or floating
+// type wins, and constants take type of the other operand unless a float constant meets an integer.
+func numericResult(scope *Scope, e *ast.InfixExpression, left ast.Type, right ast.Type) ast.Type {
+	lc, rc := classOf(scope, left), classOf(scope, right)
+	lconst, rconst := isUntypedConstant(scope, e.LeftOperand), isUntypedConstant(scope, e.RightOperand)
+	switch {
+	case lc == ClassUnknown || rc == ClassUnknown:
+		return nil
+
+	case lconst && !rconst && !(lc == ClassFloat && rc == ClassInteger):
+		return right
+
+	case rconst && !lconst && !(rc == ClassFloat && lc == ClassInteger):
+		return left
+
+	case numericRank(scope, right) > numericRank(scope, left):
+		return right
+	}
+
+	return left
+}
+
+// comparable tells whether values of two types can be compared for equality.
+func comparable(scope *Scope, e *ast.InfixExpression, left ast.Type, right ast.Type) bool {
+	lc, rc := classOf(scope, left), classOf(scope, right)
+	if isNull(e.LeftOperand) || isNull(e.RightOperand) {
+		return lc == ClassPointer || lc == ClassList || rc == ClassPointer || rc == ClassList ||
+			lc == ClassUnknown || rc == ClassUnknown
+	}
+
+	switch {
+	case lc == ClassUnknown || rc == ClassUnknown:
+		return true
+
+	case lc.isNumeric() && rc.isNumeric():
+		return true
+
+	case lc == ClassBoolean && rc == ClassBoolean:
+		return true
+
+	case lc == ClassPointer && rc == ClassPointer:
+		return assignable(scope, e.RightOperand, right, left)
+	}
+
+	return false
+}
+
+func mismatchedOperands(e *ast.InfixExpression, left ast.Type, right ast.Type) *context.Diagnostic {
+	return e.Operator.Context().Error("invalid operation: mismatched types '%s' and '%s'", TypeName(left), TypeName(right)).
+		With("operands of '%s' SHALL be in compatible types", e.Operator.Token).
+		ByRule(RuleTypeMismatch, context.Error)
+}
+
+func infixType(scope *Scope, e *ast.InfixExpression) (ast.Type, context.DiagnosticInfo) {
+	left, err := typeOf(scope, e.LeftOperand)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := typeOf(scope, e.RightOperand)
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []ast.Expression{e.LeftOperand, e.RightOperand}
+	types := []ast.Type{left, right}
+	boolean := ast.ASTBuildSimpleType("bool")
+	switch e.Operator.Token {
+	case ast.Plus, ast.Sub, ast.Asterisk, ast.Slash:
+		for i, t := range types {
+			if classOf(scope, t) == ClassPointer {
+				return nil, operands[i].Context().Error("invalid operation: operator '%s' on pointer", e.Operator.Token).
+					With("use '+>>' or '-<<' for pointer arithmetic").
+					ByRule(RulePointerArithmetic, context.Error)
+			}
+
+			if err := checkOperandType(scope, operands[i], t, e.Operator, ClassFloat); err != nil {
+				return nil, err
+			}
+		}
+
+		return numericResult(scope, e, left, right), nil
+
+	case ast.Percent, ast.Ampersand, ast.VerticalBar, ast.Caret, ast.ShiftLeft, ast.ShiftRight:
+		for i, t := range types {
+			if err := checkOperandType(scope, operands[i], t, e.Operator, ClassInteger); err != nil {
+				return nil, err
+			}
+		}
+
+		return numericResult(scope, e, left, right), nil
+
+	case ast.Equal, ast.NotEqual:
+		if !comparable(scope, e, left, right) {
+			return nil, mismatchedOperands(e, left, right)
+		}
+
+		return boolean, nil
+
+	case ast.LessThan, ast.LessThanOrEqual, ast.GreaterThan, ast.GreaterThanOrEqual:
+		lc, rc := classOf(scope, left), classOf(scope, right)
+		if lc == ClassPointer && rc == ClassPointer {
+			if !identical(scope, left, right) {
+				return nil, mismatchedOperands(e, left, right)
+			}
+
+			return boolean, nil
+		}
+
+		for i, t := range types {
+			if err := checkOperandType(scope, operands[i], t, e.Operator, ClassFloat); err != nil {
+				return nil, err
+			}
+		}
+
+		return boolean, nil
+
+	case ast.And, ast.Or:
+		for i, t := range types {
+			if c := classOf(scope, t); c != ClassUnknown && !c.isScalar() {
+				return nil, invalidOperand(operands[i], e.Operator, t, "a boolean")
+			}
+		}
+
+		return boolean, nil
+
+	case ast.InstanceEqual, ast.InstanceNotEqual:
+		if classOf(scope, left) != ClassUnknown && classOf(scope, right) != ClassUnknown &&
+			!identical(scope, left, right) {
+			return nil, mismatchedOperands(e, left, right)
+		}
+
+		return boolean, nil
+	}
+
+	return nil, nil
+}
+
+// calledFunction returns function declared in document or imported module called by e. Methods are resolved by
+// type of receiver, and builtin and C functions are not found.
+func calledFunction(scope *Scope, e *ast.CallExpression) (*ast.FunctionDeclaration, bool, context.DiagnosticInfo) {
+	functions := scope.Root().Functions
+	switch callee := e.Callee.(type) {
+	case *ast.Identifier:
+		if _, isVariable := scope.Lookup(callee.Name); isVariable {
+			return nil, false, nil
+		}
+
+		f, found := functions[callee.Name]
+		return f, found, nil
+
+	case *ast.MemberExpression:
+		if module, found := moduleOf(scope, callee); found {
+			decl, _, _ := moduleMember(module.Module, callee.Member.Name)
+			f, ok := decl.(*ast.FunctionDeclaration)
+			return f, ok, nil
+		}
+
+		if id, ok := callee.Object.(*ast.Identifier); ok {
+			if _, isVariable := scope.Lookup(id.Name); !isVariable {
+				f, found := functions[id.Name+"."+callee.Member.Name]
+				return f, found, nil
+			}
+		}
+
+		t, err := typeOf(scope, callee.Object)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, typ := range []ast.Type{t, underlying(scope, t)} {
+			if s, ok := typ.(*ast.SimpleType); ok && len(s.PointerAsterisk) <= 1 {
+				if f, found := functions[s.Identifier.Name+"."+callee.Member.Name]; found {
+					return f, true, nil
+				}
+			}
+		}
+
+		if _, err := memberType(scope, callee); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return nil, false, nil
+}
+
+// returnTypes returns types of values returned by function.
+func returnTypes(f *ast.FunctionDeclaration) []ast.Type {
+	if f.ReturnTypes == nil {
+		return nil
+	}
+
+	result := make([]ast.Type, 0, f.ReturnTypes.Length())
+	for _, item := range f.ReturnTypes.Types {
+		result = append(result, item.Type)
+	}
+
+	return result
+}
+
+// checkCallArguments checks number and types of arguments SHALL match parameters of function.
+func checkCallArguments(scope *Scope, e *ast.CallExpression, f *ast.FunctionDeclaration) context.DiagnosticInfo {
+	var params []*ast.ArgumentDeclaration
+	if f.Arguments != nil {
+		params = f.Arguments.Arguments
+	}
+
+	if len(params) != e.Arguments.Length() {
+		return e.Context().Error("wrong number of arguments in call to '%s', expect %d, got %d",
+			f.FullName(), len(params), e.Arguments.Length()).
+			With("wrong number of arguments").
+			ByRule(RuleArgumentCount, context.Error).
+			For(f.Name.Context().Note("declared here"))
+	}
+
+	for i, arg := range e.Arguments.Expressions {
+		param := params[i]
+		vt, err := typeOf(scope, arg.Expression)
+		if err != nil {
+			return err
+		}
+
+		if !assignable(scope, arg.Expression, vt, param.Type) {
+			usage := fmt.Sprintf("argument to '%s'", f.FullName())
+			return mismatchedValue(arg.Expression, vt, param.Type, usage).
+				For(param.Name.Context().Note("parameter '%s' declared here", param.Name.Name))
+		}
+	}
+
+	return nil
+}
+
+// conversionType checks conversion of a number to basic type name, and returns the type converted to. Booleans are
+// not numbers, and are compared instead.
+func conversionType(scope *Scope, e *ast.CallExpression, name string) (ast.Type, context.DiagnosticInfo) {
+	if count := e.Arguments.Length(); count != 1 {
+		return nil, e.Context().Error("wrong number of arguments in conversion to '%s', expect exactly 1, got %d", name, count).
+			With("SHALL be exactly 1").
+			ByRule(RuleArgumentCount, context.Error)
+	}
+
+	if name == "bool" {
+		return nil, e.Callee.Context().Error("cannot convert to 'bool'").
+			With("compare with zero or null instead").
+			ByRule(RuleInvalidConversion, context.Error)
+	}
+
+	value := e.Arguments.Expressions[0].Expression
+	t, err := typeOf(scope, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if c := classOf(scope, t); c != ClassUnknown && !c.isNumeric() && c != ClassBoolean {
+		return nil, value.Context().Error("cannot convert value of type '%s' to '%s'", TypeName(t), name).
+			With("SHALL be a number").
+			ByRule(RuleInvalidConversion, context.Error)
+	}
+
+	return ast.ASTBuildSimpleType(name), nil
+}
+
+func callType(scope *Scope, e *ast.CallExpression) (ast.Type, context.DiagnosticInfo) {
+	if name, ok := ConversionCall(e); ok {
+		return conversionType(scope, e, name)
+	}
+
+	if name, ok := BuiltinCall(e); ok {
+		for _, arg := range e.Arguments.Expressions {
+			if _, err := typeOf(scope, arg.Expression); err != nil {
+				return nil, err
+			}
+		}
+
+		if name == BuiltinLength || name == BuiltinCap {
+			return ast.ASTBuildSimpleType("int"), nil
+		}
+
+		return nil, nil
+	}
+
+	f, found, err := calledFunction(scope, e)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		for _, arg := range e.Arguments.Expressions {
+			if _, err := typeOf(scope, arg.Expression); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	if err := checkCallArguments(scope, e, f); err != nil {
+		return nil, err
+	}
+
+	types := returnTypes(f)
+	if len(types) > 1 {
+		return nil, e.Context().Error("multiple-value call of '%s' in single-value context", f.FullName()).
+			With("'%s' returns %d values", f.FullName(), len(types)).
+			ByRule(RuleMultipleValue, context.Error).
+			For(f.ReturnTypes.Context().Note("return types declared here"))
+	}
+
+	if len(types) == 1 {
+		return types[0], nil
+	}
+
+	return nil, nil
+}
+
+// resultTypes returns types of values of expression, calls may return multiple values.
+func resultTypes(scope *Scope, expr ast.Expression) ([]ast.Type, *ast.FunctionDeclaration, context.DiagnosticInfo) {
+	if call, ok := expr.(*ast.CallExpression); ok {
+		if _, builtin := BuiltinCall(call); !builtin {
+			f, found, err := calledFunction(scope, call)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if found {
+				if err := checkCallArguments(scope, call, f); err != nil {
+					return nil, nil, err
+				}
+
+				types := returnTypes(f)
+				if len(types) == 1 {
+					scope.Root().Info.record(call, types[0])
+				}
+
+				return types, f, nil
+			}
+		}
+	}
+
+	t, err := typeOf(scope, expr)
+	return []ast.Type{t}, nil, err
+}
+
+func memberType(scope *Scope, e *ast.MemberExpression) (ast.Type, context.DiagnosticInfo) {
+	if module, found := moduleOf(scope, e); found {
+		decl, _, _ := moduleMember(module.Module, e.Member.Name)
+		if g, ok := decl.(*ast.GlobalDeclaration); ok && !g.Variable.IsAuto() {
+			return g.Variable.Type, nil
+		}
+
+		return nil, nil
+	}
+
+	t, err := typeOf(scope, e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	c := classOf(scope, t)
+	if c == ClassPointer {
+		t = dereference(underlying(scope, t))
+		c = classOf(scope, t)
+	}
+
+	if c == ClassUnknown {
+		return nil, nil
+	}
+
+	s, ok := underlying(scope, t).(*ast.SimpleType)
+	if !ok || c != ClassStruct {
+		return nil, e.Member.Context().Error("invalid member '%s' of value of type '%s'", e.Member.Name, TypeName(t)).
+			With("SHALL be a structure or a pointer to structure").
+			ByRule(RuleUndefinedMember, context.Error)
+	}
+
+	d, st, _ := structOf(scope, s.Identifier.Name)
+	field, found := st.Field(e.Member.Name)
+	if !found {
+		return nil, e.Member.Context().Error("undefined field '%s' of type '%s'", e.Member.Name, TypeName(t)).
+			With("no such field").
+			ByRule(RuleUndefinedMember, context.Error).
+			For(d.Name.Context().Note("'%s' declared here", d.Name.Name))
+	}
+
+	return field.Type, nil
+}
+
+func indexType(scope *Scope, e *ast.IndexExpression) (ast.Type, context.DiagnosticInfo) {
+	t, err := typeOf(scope, e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := typeOf(scope, e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	if c := classOf(scope, index); c != ClassUnknown && c != ClassInteger {
+		return nil, e.Index.Context().Error("invalid index of type '%s'", TypeName(index)).
+			With("SHALL be an integer").
+			ByRule(RuleInvalidOperation, context.Error)
+	}
+
+	switch classOf(scope, t) {
+	case ClassUnknown:
+		return nil, nil
+
+	case ClassArray, ClassList, ClassPointer:
+		return elementOf(scope, t), nil
+	}
+
+	return nil, e.Object.Context().Error("invalid operation: cannot index value of type '%s'", TypeName(t)).
+		With("SHALL be an array, a list or a pointer").
+		ByRule(RuleInvalidOperation, context.Error)
+}
+
+func arrayLiteralType(scope *Scope, l *ast.ArrayLiteral) (ast.Type, context.DiagnosticInfo) {
+	element := l.Type.ElementType
+	for _, item := range l.Elements.Expressions {
+		if _, err := checkValueType(scope, element, item.Expression, "array literal"); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.Type.Size != nil {
+		return l.Type, nil
+	}
+
+	size := ast.ASTBuildValue(l.Elements.Length())
+	return ast.NewArrayType(element, l.Type.LBracket, size, l.Type.RBracket), nil
+}
+
+func structLiteralType(scope *Scope, l *ast.StructLiteral) (ast.Type, context.DiagnosticInfo) {
+	_, s, found := structOf(scope, l.Type.Identifier.Name)
+	for _, value := range l.Fields {
+		var t ast.Type
+		if found {
+			if field, ok := s.Field(value.Name.Name); ok {
+				t = field.Type
+			}
+		}
+
+		usage := fmt.Sprintf("field '%s'", value.Name.Name)
+		if _, err := checkValueType(scope, t, value.Value, usage); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.Type, nil
+}
+
+func newType(scope *Scope, e *ast.NewExpression) (ast.Type, context.DiagnosticInfo) {
+	if l, ok := e.ListLiteral(); ok {
+		if _, err := arrayLiteralType(scope, l); err != nil {
+			return nil, err
+		}
+
+		return ast.NewListType(l.Type.ElementType, l.Type.LBracket, l.Type.RBracket), nil
+	}
+
+	if l, ok := e.StructLiteral(); ok {
+		t, err := structLiteralType(scope, l)
+		if err != nil {
+			return nil, err
+		}
+
+		return pointerTo(t), nil
+	}
+
+	if call, ok := e.Constructor(); ok {
+		id, ok := call.Callee.(*ast.Identifier)
+		if !ok {
+			return nil, nil
+		}
+
+		t := ast.NewSimpleType(nil, id)
+		for _, arg := range call.Arguments.Expressions {
+			if _, err := checkValueType(scope, t, arg.Expression, "new"); err != nil {
+				return nil, err
+			}
+		}
+
+		return pointerTo(t), nil
+	}
+
+	return nil, nil
+}
+
+// checkDeclarationType checks value of variable declared SHALL be in its type, and returns type of the variable.
+// Type of variable declared by 'auto' is inferred from its value.
+func checkDeclarationType(scope *Scope, d *ast.VariableDeclaration) (ast.Type, context.DiagnosticInfo) {
+	if d.IsAuto() {
+		if d.Value == nil {
+			return nil, nil
+		}
+
+		return typeOf(scope, d.Value)
+	}
+
+	if d.Value != nil {
+		usage := fmt.Sprintf("declaration of '%s'", d.Name.Name)
+		if _, err := checkValueType(scope, d.Type, d.Value, usage); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.Type, nil
+}
+
+// inferredTypes returns types of variables declared by ':=', a single call may return values of multiple variables.
+func inferredTypes(scope *Scope, d *ast.InferenceDeclaration) ([]ast.Type, context.DiagnosticInfo) {
+	names, values := d.Names.Length(), d.Values.Length()
+	if values > 1 {
+		types := make([]ast.Type, 0, values)
+		for _, item := range d.Values.Expressions {
+			t, err := typeOf(scope, item.Expression)
+			if err != nil {
+				return nil, err
+			}
+
+			types = append(types, t)
+		}
+
+		return types, nil
+	}
+
+	types, f, err := resultTypes(scope, d.Values.Expressions[0].Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if f != nil && len(types) != names {
+		return nil, mismatchedResults(d.Assign, names, f)
+	}
+
+	if len(types) != names {
+		return make([]ast.Type, names), nil
+	}
+
+	return types, nil
+}
+
+// mismatchedResults reports values returned by function which are not in the same number as variables.
+func mismatchedResults(operator *ast.TerminalToken, count int, f *ast.FunctionDeclaration) context.DiagnosticInfo {
+	var note *context.Diagnostic
+	if f.ReturnTypes != nil {
+		note = f.ReturnTypes.Context().Note("return types declared here")
+
+	} else {
+		note = f.Name.Context().Note("declared here")
+	}
+
+	return operator.Context().Error("assignment mismatch: %d variables but '%s' returns %d values",
+		count, f.FullName(), len(returnTypes(f))).
+		With("SHALL be %d values", count).
+		ByRule(RuleAssignmentMismatch, context.Error).
+		For(note)
+}
+
+// compoundOperands returns class of operands allowed by compound assignment operator, like '+=' and '<<='.
+func compoundOperands(operator *ast.TerminalToken) TypeClass {
+	switch operator.Token {
+	case ast.AddAssign, ast.SubAssign, ast.MulAssign, ast.DivAssign:
+		return ClassFloat
+	}
+
+	return ClassInteger
+}
+
+// checkAssignmentTypes checks values assigned SHALL be in types of targets, and operands of compound assignments
+// SHALL be numbers.
+func checkAssignmentTypes(scope *Scope, s *ast.AssignmentStatement) context.DiagnosticInfo {
+	targets, values := s.Targets.Length(), s.Values.Length()
+	if s.Operator.Token != ast.Assign {
+		class := compoundOperands(s.Operator)
+		for i, target := range s.Targets.Expressions {
+			if err := checkOperand(scope, target.Expression, s.Operator, class); err != nil {
+				return err
+			}
+
+			if i < values {
+				if err := checkOperand(scope, s.Values.Expressions[i].Expression, s.Operator, class); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	var types []ast.Type
+	if values == 1 {
+		results, f, err := resultTypes(scope, s.Values.Expressions[0].Expression)
+		if err != nil {
+			return err
+		}
+
+		if f != nil && len(results) != targets {
+			return mismatchedResults(s.Operator, targets, f)
+		}
+
+		if f != nil {
+			types = results
+		}
+	}
+
+	for i, item := range s.Targets.Expressions {
+		target := item.Expression
+		if id, ok := target.(*ast.Identifier); ok && id.IsDummy() {
+			continue
+		}
+
+		t, err := typeOf(scope, target)
+		if err != nil {
+			return err
+		}
+
+		if targets == values {
+			if _, err := checkValueType(scope, t, s.Values.Expressions[i].Expression, "assignment"); err != nil {
+				return err
+			}
+
+		} else if types != nil && !assignable(scope, nil, types[i], t) {
+			return s.Values.Expressions[0].Context().
+				Error("cannot assign value %d of type '%s' to '%s'", i+1, TypeName(types[i]), TypeName(t)).
+				With("SHALL be '%s'", TypeName(t)).
+				ByRule(RuleTypeMismatch, context.Error)
+		}
+	}
+
+	return nil
+}
+
+// checkReturnTypes checks values returned SHALL be in return types of function, count of values is checked by
+// checkFunctionReturnValue.
+func checkReturnTypes(scope *Scope, s *ast.ReturnStatement) context.DiagnosticInfo {
+	f, found := scope.EnclosingFunction()
+	if !found || s.Value == nil {
+		return nil
+	}
+
+	types := returnTypes(f)
+	if len(types) != s.Value.Length() {
+		return nil
+	}
+
+	for i, item := range s.Value.Expressions {
+		vt, err := typeOf(scope, item.Expression)
+		if err != nil {
+			return err
+		}
+
+		if !assignable(scope, item.Expression, vt, types[i]) {
+			return mismatchedValue(item.Expression, vt, types[i], "return statement").
+				For(types[i].Context().Note("return type declared here"))
+		}
+	}
+
+	return nil
+}
+
+// checkConditionType checks condition SHALL be a boolean, pointers and numbers are not truth values.
+func checkConditionType(scope *Scope, keyword *ast.TerminalToken, cond ast.Expression) context.DiagnosticInfo {
+	t, err := typeOf(scope, cond)
+	if err != nil {
+		return err
+	}
+
+	if c := classOf(scope, t); c != ClassUnknown && c != ClassBoolean {
+		return cond.Context().Error("non-boolean condition in '%s' statement", keyword.Token).
+			With("condition must be a boolean type, got '%s'", TypeName(t)).
+			ByRule(RuleNonBooleanCond, context.Error)
+	}
+
+	return nil
+}
+
+// iterableElement returns type of elements iterated by foreach, which SHALL be an array or a list.
+func iterableElement(scope *Scope, iterable ast.Expression) (ast.Type, context.DiagnosticInfo) {
+	t, err := typeOf(scope, iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	switch classOf(scope, t) {
+	case ClassUnknown:
+		return nil, nil
+
+	case ClassArray, ClassList:
+		return elementOf(scope, t), nil
+	}
+
+	return nil, iterable.Context().Error("cannot iterate over value of type '%s'", TypeName(t)).
+		With("SHALL be an array or a list").
+		ByRule(RuleInvalidOperation, context.Error)
+}
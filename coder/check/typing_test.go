@@ -0,0 +1,421 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
+)
+
+func TestCheckTypesCorrect(t *testing.T) {
+	code := strings.Join([]string{
+		"type Point struct {",
+		"    x float64",
+		"    y float64",
+		"}",
+		"type Celsius float64",
+		"fun (p *Point) Scale(k float64) (*Point) {",
+		"    p.x *= k",
+		"    p.y *= k",
+		"    return p",
+		"}",
+		"fun divmod(a int, b int) (int, int) {",
+		"    return a / b, a % b",
+		"}",
+		"fun warm(c Celsius) (bool) {",
+		"    return c > 20 and c < 30.5",
+		"}",
+		"fun main() (int) {",
+		"    var p *Point = new Point{x: 1, y: 2}",
+		"    ref q := p.Scale(2)",
+		"    var d float64 = q.x * 2 + 1",
+		"    n, m := divmod(7, 2)",
+		"    divmod(n, m)",
+		"    ref name := \"point\"",
+		"    var buf uint8[4]",
+		"    var ref b *uint8 = &buf",
+		"    var small int16 = int16(n) + int16(q.x)",
+		"    var ids uint8[] = new uint8[]{1, 2, 3}",
+		"    foreach (id in ids) {",
+		"        n += id",
+		"    }",
+		"    if p != null and warm(d) {",
+		"        m++",
+		"    }",
+		"    delete p",
+		"    return n + m",
+		"}",
+	}, "\n")
+
+	checkCodeCorrect(t, code)
+}
+
+func TestCheckTypesErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun area(w int, h int) (int) {",
+				"    return w * h",
+				"}",
+				"fun main() {",
+				"    a := area(1, true)",
+				"}",
+			},
+			[]string{
+				"test.mc:5:18: error: cannot use value of type 'bool' as 'int' in argument to 'area' [type-mismatch]",
+				"    5 |     a := area(1, true)",
+				"      |                  ^^^^",
+				"      |                  SHALL be 'int'",
+				"test.mc:1:17: note: parameter 'h' declared here",
+				"    1 | fun area(w int, h int) (int) {",
+				"      |                 ^",
+			},
+		},
+		{
+			[]string{
+				"fun area(w int, h int) (int) {",
+				"    return w * h",
+				"}",
+				"fun main() {",
+				"    a := area(1)",
+				"}",
+			},
+			[]string{
+				"test.mc:5:10: error: wrong number of arguments in call to 'area', expect 2, got 1 [argument-count]",
+				"    5 |     a := area(1)",
+				"      |          ^^^^^^^",
+				"      |          wrong number of arguments",
+				"test.mc:1:5: note: declared here",
+				"    1 | fun area(w int, h int) (int) {",
+				"      |     ^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun ratio() (*int32) {",
+				"    return 1.5",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: cannot use value of type 'float64' as '*int32' in return statement [type-mismatch]",
+				"    2 |     return 1.5",
+				"      |            ^^^",
+				"      |            SHALL be '*int32'",
+				"test.mc:1:14: note: return type declared here",
+				"    1 | fun ratio() (*int32) {",
+				"      |              ^^^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    x := 1 + true",
+				"}",
+			},
+			[]string{
+				"test.mc:2:14: error: invalid operation: operator '+' on value of type 'bool' [invalid-operation]",
+				"    2 |     x := 1 + true",
+				"      |              ^^^^",
+				"      |              SHALL be a number",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var p *int32 = null",
+				"    q := p + 1",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: invalid operation: operator '+' on pointer [pointer-arithmetic]",
+				"    3 |     q := p + 1",
+				"      |          ^",
+				"      |          use '+>>' or '-<<' for pointer arithmetic",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    a := 1.5",
+				"    var b bool = a",
+				"}",
+			},
+			[]string{
+				"test.mc:3:18: error: cannot use value of type 'float64' as 'bool' in declaration of 'b' [type-mismatch]",
+				"    3 |     var b bool = a",
+				"      |                  ^",
+				"      |                  SHALL be 'bool'",
+			},
+		},
+		{
+			[]string{
+				"struct Point { x int; y int }",
+				"fun main() {",
+				"    var p Point",
+				"    p.z = 1",
+				"}",
+			},
+			[]string{
+				"test.mc:4:7: error: undefined field 'z' of type 'Point' [undefined-member]",
+				"    4 |     p.z = 1",
+				"      |       ^",
+				"      |       no such field",
+				"test.mc:1:8: note: 'Point' declared here",
+				"    1 | struct Point { x int; y int }",
+				"      |        ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun divmod(a int, b int) (int, int) {",
+				"    return a / b, a % b",
+				"}",
+				"fun main() {",
+				"    q := divmod(7, 2)",
+				"}",
+			},
+			[]string{
+				"test.mc:5:7: error: assignment mismatch: 1 variables but 'divmod' returns 2 values [assignment-mismatch]",
+				"    5 |     q := divmod(7, 2)",
+				"      |       ^^",
+				"      |       SHALL be 1 values",
+				"test.mc:1:27: note: return types declared here",
+				"    1 | fun divmod(a int, b int) (int, int) {",
+				"      |                           ^^^^ ^^^",
+			},
+		},
+		{
+			[]string{
+				"struct Point { x int; y int }",
+				"fun main() {",
+				"    var p Point",
+				"    if p.x {",
+				"    }",
+				"}",
+			},
+			[]string{
+				"test.mc:4:8: error: non-boolean condition in 'if' statement [non-boolean-condition]",
+				"    4 |     if p.x {",
+				"      |        ^^^",
+				"      |        condition must be a boolean type, got 'int'",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    n := 3",
+				"    foreach (i in n) {",
+				"    }",
+				"}",
+			},
+			[]string{
+				"test.mc:3:19: error: cannot iterate over value of type 'int' [invalid-operation]",
+				"    3 |     foreach (i in n) {",
+				"      |                   ^",
+				"      |                   SHALL be an array or a list",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var s int32 = \"x\"",
+				"}",
+			},
+			[]string{
+				"test.mc:2:19: error: cannot use value of type '*char' as 'int32' in declaration of 's' [type-mismatch]",
+				"    2 |     var s int32 = \"x\"",
+				"      |                   ^^^",
+				"      |                   SHALL be 'int32'",
+			},
+		},
+		{
+			[]string{
+				"fun name() (int32) {",
+				"    return \"abc\"",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: cannot use value of type '*char' as 'int32' in return statement [type-mismatch]",
+				"    2 |     return \"abc\"",
+				"      |            ^^^^^",
+				"      |            SHALL be 'int32'",
+				"test.mc:1:13: note: return type declared here",
+				"    1 | fun name() (int32) {",
+				"      |             ^^^^^",
+			},
+		},
+		{
+			[]string{
+				"fun divmod(a int, b int) (int, int) {",
+				"    return a / b, a % b",
+				"}",
+				"fun main() {",
+				"    x := divmod(1, 2) + 1",
+				"}",
+			},
+			[]string{
+				"test.mc:5:10: error: multiple-value call of 'divmod' in single-value context [multiple-value]",
+				"    5 |     x := divmod(1, 2) + 1",
+				"      |          ^^^^^^^^^ ^^",
+				"      |          'divmod' returns 2 values",
+				"test.mc:1:27: note: return types declared here",
+				"    1 | fun divmod(a int, b int) (int, int) {",
+				"      |                           ^^^^ ^^^",
+			},
+		},
+		{
+			[]string{
+				"type Header struct {",
+				"    kind uint8",
+				"}",
+				"fun main() {",
+				"    var buf uint8[32]",
+				"    var ref p *Header = &buf",
+				"}",
+			},
+			[]string{
+				"test.mc:6:25: error: cannot use value of type '*uint8' as '*Header' in declaration of 'p' [type-mismatch]",
+				"    6 |     var ref p *Header = &buf",
+				"      |                         ^^^^",
+				"      |                         SHALL be '*Header'",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var a int32 = 1",
+				"    b := int16(a, 2)",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: wrong number of arguments in conversion to 'int16', expect exactly 1, got 2 [argument-count]",
+				"    3 |     b := int16(a, 2)",
+				"      |          ^^^^^^^^ ^^",
+				"      |          SHALL be exactly 1",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    var a int32 = 1",
+				"    b := bool(a)",
+				"}",
+			},
+			[]string{
+				"test.mc:3:10: error: cannot convert to 'bool' [invalid-conversion]",
+				"    3 |     b := bool(a)",
+				"      |          ^^^^",
+				"      |          compare with zero or null instead",
+			},
+		},
+		{
+			[]string{
+				"type Point struct {",
+				"    x int32",
+				"}",
+				"fun main() {",
+				"    var p Point",
+				"    b := int32(p)",
+				"}",
+			},
+			[]string{
+				"test.mc:6:16: error: cannot convert value of type 'Point' to 'int32' [invalid-conversion]",
+				"    6 |     b := int32(p)",
+				"      |                ^",
+				"      |                SHALL be a number",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join(c.code, "\n")
+		expected := strings.Join(c.expected, "\n")
+		checkCodeError(t, code, expected)
+	}
+}
+
+func TestCheckTypeInfo(t *testing.T) {
+	code := strings.Join([]string{
+		"struct Point { x float64; y float64 }",
+		"fun main() {",
+		"    var p *Point = new Point{x: 1, y: 2}",
+		"    d := p.x * 2",
+		"    delete p",
+		"}",
+	}, "\n")
+
+	doc := parseCode(t, code)
+	checker := NewCodeChecker(NewDefaultCheckConfigure(), doc)
+	if container := checker.Check(); container.Count(context.Error) > 0 {
+		t.Fatalf("code check expected to succeed, but got errors:\n%s", container.Error())
+	}
+
+	f := doc.Declarations[1].(*ast.FunctionDeclaration)
+	d := f.Statements[1].(*ast.InferenceDeclaration)
+	value := d.Values.Expressions[0].Expression.(*ast.InfixExpression)
+	cases := []struct {
+		expr     ast.Expression
+		expected string
+	}{
+		{value, "float64"},
+		{value.LeftOperand, "float64"},
+		{value.RightOperand, "int"},
+		{value.LeftOperand.(*ast.MemberExpression).Object, "*Point"},
+	}
+
+	for i, c := range cases {
+		typ, found := checker.Types().TypeOf(c.expr)
+		if !found || TypeName(typ) != c.expected {
+			t.Errorf("case %d: expected type '%s', got '%s' (found %v)", i, c.expected, TypeName(typ), found)
+		}
+	}
+}
+
+func TestCheckTypeInfoArithmetic(t *testing.T) {
+	cases := []struct {
+		declarations string
+		expression   string
+		expected     string
+	}{
+		{"var a uint8 = 1; var b int64 = 2", "a + b", "int64"},
+		{"var a int64 = 1; var b uint8 = 2", "a + b", "int64"},
+		{"var a float32 = 1; var b float64 = 2", "a * b", "float64"},
+		{"var a float64 = 1; var b float32 = 2", "a * b", "float64"},
+		{"var a int32 = 1; var b float32 = 2", "a - b", "float32"},
+		{"var a int32 = 1; var b uint32 = 2", "a / b", "uint32"},
+		{"var a uint16 = 1; var b int16 = 2", "a & b", "uint16"},
+		{"var a uint8 = 1; var b uint8 = 2", "1 + a", "uint8"},
+		{"var a uint8 = 1; var b uint8 = 2", "a << 2", "uint8"},
+		{"var a int32 = 1; var b int32 = 2", "a * 1.5", "float64"},
+		{"var a float32 = 1; var b float32 = 2", "2.5 * a", "float32"},
+		{"var a Size = 1; var b uint8 = 2", "b + a", "Size"},
+	}
+
+	for i, c := range cases {
+		code := strings.Join([]string{
+			"type Size uint64",
+			"fun main() {",
+			"    " + strings.ReplaceAll(c.declarations, "; ", "\n    "),
+			"    r := " + c.expression,
+			"}",
+		}, "\n")
+
+		doc := parseCode(t, code)
+		checker := NewCodeChecker(NewDefaultCheckConfigure(), doc)
+		if container := checker.Check(); container.Count(context.Error) > 0 {
+			t.Fatalf("case %d: code check expected to succeed, but got errors:\n%s", i, container.Error())
+		}
+
+		f := doc.Declarations[1].(*ast.FunctionDeclaration)
+		d := f.Statements[2].(*ast.InferenceDeclaration)
+		typ, found := checker.Types().TypeOf(d.Values.Expressions[0].Expression)
+		if !found || TypeName(typ) != c.expected {
+			t.Errorf("case %d: expected type '%s' of '%s', got '%s' (found %v)",
+				i, c.expected, c.expression, TypeName(typ), found)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package check
+
+import (
+	"github.com/flily/magi-c/ast"
+)
+
+func typeValueKind(t ast.Type) ValueKind {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		if len(typ.PointerAsterisk) == 0 && typ.Identifier.Name == "bool" {
+			return ValueBoolean
+		}
+
+		return ValueNonBoolean
+	}
+
+	return ValueUnknown
+}
+
+// expressionValueKind tells whether an expression is boolean. Values of calls, members and elements are unknown
+// until types are checked.
+func expressionValueKind(scope *Scope, expr ast.Expression) ValueKind {
+	switch e := expr.(type) {
+	case *ast.BooleanLiteral:
+		return ValueBoolean
+
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.NullLiteral, *ast.SizeofExpression,
+		*ast.StructLiteral:
+		return ValueNonBoolean
+
+	case *ast.Identifier:
+		if symbol, found := scope.Lookup(e.Name); found {
+			return symbol.Value
+		}
+
+	case *ast.ParenthesizedExpression:
+		return expressionValueKind(scope, e.Expression)
+
+	case *ast.PrefixExpression:
+		switch e.Operator.Token {
+		case ast.Not:
+			return ValueBoolean
+
+		case ast.Asterisk:
+			return ValueUnknown
+		}
+
+		return ValueNonBoolean
+
+	case *ast.InfixExpression:
+		switch e.Operator.Token {
+		case ast.Equal, ast.NotEqual, ast.LessThan, ast.LessThanOrEqual, ast.GreaterThan, ast.GreaterThanOrEqual,
+			ast.And, ast.Or:
+			return ValueBoolean
+		}
+
+		return ValueNonBoolean
+	}
+
+	return ValueUnknown
+}
+
+// ConstantInteger evaluates an integer constant expression, false is returned if the expression is not a constant
+// or can not be evaluated.
+func ConstantInteger(expr ast.Expression) (int64, bool) {
+	return evaluateInteger(expr, nil)
+}
+
+// evaluateInteger evaluates an integer constant expression, sizeof is evaluated by sizeof if it is not nil.
+func evaluateInteger(expr ast.Expression, sizeof func(*ast.SizeofExpression) (int64, bool)) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return int64(e.Value), true
+
+	case *ast.ParenthesizedExpression:
+		return evaluateInteger(e.Expression, sizeof)
+
+	case *ast.SizeofExpression:
+		if sizeof != nil {
+			return sizeof(e)
+		}
+
+	case *ast.PrefixExpression:
+		v, ok := evaluateInteger(e.Operand, sizeof)
+		if !ok {
+			return 0, false
+		}
+
+		switch e.Operator.Token {
+		case ast.Sub:
+			return -v, true
+
+		case ast.Tilde:
+			return ^v, true
+		}
+
+	case *ast.InfixExpression:
+		left, ok1 := evaluateInteger(e.LeftOperand, sizeof)
+		right, ok2 := evaluateInteger(e.RightOperand, sizeof)
+		if !ok1 || !ok2 {
+			return 0, false
+		}
+
+		switch e.Operator.Token {
+		case ast.Plus:
+			return left + right, true
+
+		case ast.Sub:
+			return left - right, true
+
+		case ast.Asterisk:
+			return left * right, true
+
+		case ast.Slash:
+			if right != 0 {
+				return left / right, true
+			}
+
+		case ast.Percent:
+			if right != 0 {
+				return left % right, true
+			}
+
+		case ast.ShiftLeft:
+			if right >= 0 {
+				return left << right, true
+			}
+
+		case ast.ShiftRight:
+			if right >= 0 {
+				return left >> right, true
+			}
+
+		case ast.Ampersand:
+			return left & right, true
+
+		case ast.VerticalBar:
+			return left | right, true
+
+		case ast.Caret:
+			return left ^ right, true
+		}
+	}
+
+	return 0, false
+}
+
+// ConstantBoolean evaluates a boolean constant expression, including comparisons of integer constants, false is
+// returned if the expression is not a constant. Operands of 'and' and 'or' deciding the result are enough.
+func ConstantBoolean(expr ast.Expression) (bool, bool) {
+	switch e := expr.(type) {
+	case *ast.BooleanLiteral:
+		return e.Value, true
+
+	case *ast.ParenthesizedExpression:
+		return ConstantBoolean(e.Expression)
+
+	case *ast.PrefixExpression:
+		if e.Operator.Token == ast.Not {
+			value, ok := ConstantBoolean(e.Operand)
+			return !value, ok
+		}
+
+	case *ast.InfixExpression:
+		switch e.Operator.Token {
+		case ast.And, ast.Or:
+			decisive := e.Operator.Token == ast.Or
+			left, ok1 := ConstantBoolean(e.LeftOperand)
+			right, ok2 := ConstantBoolean(e.RightOperand)
+			if (ok1 && left == decisive) || (ok2 && right == decisive) {
+				return decisive, true
+			}
+
+			return !decisive, ok1 && ok2
+
+		case ast.Equal, ast.NotEqual:
+			left, ok1 := ConstantBoolean(e.LeftOperand)
+			right, ok2 := ConstantBoolean(e.RightOperand)
+			if ok1 && ok2 {
+				return (left == right) == (e.Operator.Token == ast.Equal), true
+			}
+		}
+
+		return compareIntegers(e)
+	}
+
+	return false, false
+}
+
+// compareIntegers evaluates comparison of integer constants.
+func compareIntegers(e *ast.InfixExpression) (bool, bool) {
+	left, ok1 := ConstantInteger(e.LeftOperand)
+	right, ok2 := ConstantInteger(e.RightOperand)
+	if !ok1 || !ok2 {
+		return false, false
+	}
+
+	switch e.Operator.Token {
+	case ast.Equal:
+		return left == right, true
+
+	case ast.NotEqual:
+		return left != right, true
+
+	case ast.LessThan:
+		return left < right, true
+
+	case ast.LessThanOrEqual:
+		return left <= right, true
+
+	case ast.GreaterThan:
+		return left > right, true
+
+	case ast.GreaterThanOrEqual:
+		return left >= right, true
+	}
+
+	return false, false
+}
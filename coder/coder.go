@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/flily/magi-c/ast"
 	"github.com/flily/magi-c/coder/check"
@@ -22,6 +23,8 @@ const (
 	DefaultOutputSuffix      = ".c"
 	DefaultSourceSuffix      = ".mc"
 	DefaultOutputParamPrefix = "__out__"
+	DefaultTempVarPrefix     = "__tmp__"
+	DefaultDestructorPrefix  = "__magic_free_"
 )
 
 func ParseDocument(data []byte, filename string) (*ast.Document, error) {
@@ -45,6 +48,24 @@ type Coder struct {
 	OutputBase string
 	Refs       *Cache
 	Style      *csyntax.CodeStyle
+
+	// Debug enables runtime checks in output, like bounds checks of array indexes.
+	Debug bool
+
+	// Target is the target profile, by which sizes of types are evaluated at compile time.
+	Target *check.TargetProfile
+
+	// Standard is the C standard of output, struct literals are assigned field by field in C89.
+	Standard csyntax.CStandard
+
+	// Checks configures levels of check rules, the target profile is overridden by Target.
+	Checks *check.CheckConfigure
+
+	// typed records types of expressions resolved by checker for each document checked.
+	typed map[*ast.Document]*check.TypeInfo
+
+	// diagnostics records results of checks by source, including warnings.
+	diagnostics map[string]*context.DiagnosticContainer
 }
 
 func NewCoder(sourceBase string, outputBase string) *Coder {
@@ -53,6 +74,12 @@ func NewCoder(sourceBase string, outputBase string) *Coder {
 		OutputBase: outputBase,
 		Refs:       NewCache(),
 		Style:      csyntax.KRStyle,
+		Target:     check.DefaultTargetProfile,
+		Standard:   csyntax.C99,
+		Checks:     check.NewDefaultCheckConfigure(),
+		typed:      make(map[*ast.Document]*check.TypeInfo),
+
+		diagnostics: make(map[string]*context.DiagnosticContainer),
 	}
 
 	return c
@@ -74,6 +101,10 @@ func (c *Coder) ParseFileContent(filename string, content []byte) (string, error
 	}
 
 	c.Refs.Add(relName, doc)
+	if name, ok := ModuleName(doc); ok {
+		c.Refs.AddModule(name, relName)
+	}
+
 	return relName, nil
 }
 
@@ -90,7 +121,7 @@ func (c *Coder) FindMain() string {
 	for filename, doc := range c.Refs.Documents {
 		for _, decl := range doc.Declarations {
 			if fnDecl, ok := decl.(*ast.FunctionDeclaration); ok {
-				if fnDecl.Name.Name == DefaultMainEntryName {
+				if isMainFunction(fnDecl) {
 					return filename
 				}
 			}
@@ -106,16 +137,33 @@ func (c *Coder) Check(source string) error {
 		return fmt.Errorf("source file '%s' not exists", source)
 	}
 
-	conf := check.NewDefaultCheckConfigure()
-	checker := check.NewCodeChecker(conf, doc)
+	imports, err := c.ResolveImports(source)
+	if err != nil {
+		return err
+	}
+
+	conf := *c.Checks
+	conf.Target = c.Target
+	checker := check.NewCodeChecker(&conf, doc)
+	for name, imported := range imports {
+		checker.AddImport(name, imported)
+	}
+
 	result := checker.Check()
-	if result == nil || result.Count(context.Warning) <= 0 {
+	c.typed[doc] = checker.Types()
+	c.diagnostics[source] = result
+	if result == nil || result.Count(context.Error) <= 0 {
 		return nil
 	}
 
 	return result
 }
 
+// Diagnostics returns result of the last check of source, warnings are not returned as error by Check.
+func (c *Coder) Diagnostics(source string) *context.DiagnosticContainer {
+	return c.diagnostics[source]
+}
+
 func (c *Coder) Output(sourceRel string) error {
 	outputTarget := c.OutputFilename(sourceRel)
 	return c.OutputToFile(sourceRel, outputTarget)
@@ -150,17 +198,109 @@ func (c *Coder) OutputTo(sourceRel string, out io.StringWriter) error {
 
 func (c *Coder) OutputDocument(document *ast.Document, out *csyntax.StyleWriter) error {
 	ctx := NewContext()
+	ctx.Module, _ = ModuleName(document)
+	ctx.Typed = c.typed[document]
+	for _, decl := range document.Declarations {
+		switch d := decl.(type) {
+		case *ast.FunctionDeclaration:
+			ctx.RegisterFunction(d)
+
+		case *ast.TypeDeclaration:
+			ctx.RegisterType(d)
+		}
+	}
+
+	// global variables are registered before functions output, in which they are referenced by name in C code.
+	for _, decl := range document.Declarations {
+		switch d := decl.(type) {
+		case *ast.ImportDeclaration:
+			if imported, found := c.ImportedDocument(d.Name.Name); found {
+				c.registerImport(ctx, d.Name.Name, imported)
+			}
+
+		case *ast.GlobalDeclaration:
+			c.registerGlobal(ctx, d)
+		}
+	}
+
 	elements := c.OutputDeclarations(ctx, document.Declarations)
+	if comments := document.TrailingComments(); len(comments) > 0 {
+		if len(elements) > 0 {
+			elements = append(elements, csyntax.NewEmptyLine())
+		}
+
+		for _, comment := range OutputComments(comments) {
+			elements = append(elements, comment)
+		}
+	}
+
 	return out.Write(csyntax.NewDefaultLevel(), elements...)
 }
 
+func isPreprocessorDeclaration(decl ast.Declaration) bool {
+	switch decl.(type) {
+	case *ast.PreprocessorInclude, *ast.PreprocessorInline:
+		return true
+	}
+
+	return false
+}
+
 func (c *Coder) OutputDeclarations(ctx *Context, decls []ast.Declaration) []csyntax.CodeElement {
+	chunks := make([][]csyntax.CodeElement, 0, len(decls)+2)
+	types := make([]*ast.TypeDeclaration, 0, len(ctx.Types))
+	globals := make([]csyntax.CodeElement, 0, len(decls))
+	leading := 0
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *ast.TypeDeclaration:
+			types = append(types, d)
+			continue
+
+		case *ast.GlobalDeclaration:
+			globals = append(globals, c.OutputDeclaration(ctx, d)...)
+			continue
+
+		case *ast.ModuleDeclaration, *ast.ImportDeclaration:
+			continue
+		}
+
+		if leading == len(chunks) && isPreprocessorDeclaration(decl) {
+			leading++
+		}
+
+		chunks = append(chunks, c.OutputDeclaration(ctx, decl))
+	}
+
+	// types, global variables, symbols of imported modules and functions called before their definitions require
+	// prototypes, which are placed after leading preprocessor directives.
+	headers := c.OutputTypeDeclarations(ctx, types)
+	if destructors := c.OutputDestructors(ctx); len(destructors) > 0 {
+		headers = append(headers, destructors...)
+	}
+
+	if len(globals) > 0 {
+		headers = append(headers, globals)
+	}
+
+	headers = append(headers, c.OutputImports(ctx)...)
+
+	if prototypes := c.OutputFunctionPrototypes(ctx); len(prototypes) > 0 {
+		headers = append(headers, prototypes)
+	}
+
+	// headers required are known after all other declarations are output
+	if runtime := c.OutputRuntime(ctx); len(runtime) > 0 {
+		headers = slices.Insert(headers, 0, runtime)
+	}
+
+	chunks = slices.Insert(chunks, leading, headers...)
+
 	result := make([]csyntax.CodeElement, 0, 2*len(decls))
-	for i, decl := range decls {
-		out := c.OutputDeclaration(ctx, decl)
-		result = append(result, out...)
+	for i, chunk := range chunks {
+		result = append(result, chunk...)
 
-		if i < len(decls)-1 {
+		if i < len(chunks)-1 {
 			result = append(result, csyntax.NewEmptyLine())
 		}
 	}
@@ -168,8 +308,43 @@ func (c *Coder) OutputDeclarations(ctx *Context, decls []ast.Declaration) []csyn
 	return result
 }
 
+// OutputTypeDeclarations outputs forward declarations of all structures, and then definitions of types in order
+// of dependencies.
+func (c *Coder) OutputTypeDeclarations(ctx *Context, types []*ast.TypeDeclaration) [][]csyntax.CodeElement {
+	result := make([][]csyntax.CodeElement, 0, len(types)+1)
+	forwards := make([]csyntax.CodeElement, 0, len(types))
+	for _, t := range types {
+		if _, ok := t.StructType(); ok {
+			forwards = append(forwards, csyntax.NewStructTypedef(t.Name.Name))
+		}
+	}
+
+	if len(forwards) > 0 {
+		result = append(result, forwards)
+	}
+
+	for _, t := range SortTypeDeclarations(ctx, types) {
+		result = append(result, c.OutputDeclaration(ctx, t))
+	}
+
+	return result
+}
+
+func (c *Coder) OutputFunctionPrototypes(ctx *Context) []csyntax.CodeElement {
+	result := make([]csyntax.CodeElement, 0, len(ctx.ForwardFunctions))
+	for _, info := range ctx.ForwardFunctions {
+		result = append(result, c.outputFunctionSignature(ctx, info).Prototype())
+	}
+
+	return result
+}
+
 func (c *Coder) OutputDeclaration(ctx *Context, decl ast.Declaration) []csyntax.CodeElement {
 	result := make([]csyntax.CodeElement, 0, 10)
+	for _, comment := range OutputComments(decl.LeadingComments()) {
+		result = append(result, comment)
+	}
+
 	result = append(result, csyntax.NewContext(decl.Context()))
 
 	switch d := decl.(type) {
@@ -181,13 +356,67 @@ func (c *Coder) OutputDeclaration(ctx *Context, decl ast.Declaration) []csyntax.
 
 	case *ast.PreprocessorInline:
 		result = append(result, c.OutputPreprocessorInline(ctx, d))
+
+	case *ast.TypeDeclaration:
+		result = append(result, c.OutputTypeDeclaration(ctx, d))
+
+	case *ast.GlobalDeclaration:
+		result = append(result, c.OutputGlobalDeclaration(ctx, d))
+	}
+
+	for _, comment := range OutputComments(decl.TrailingComments()) {
+		result = append(result, comment)
 	}
 
 	return result
 }
 
+func (c *Coder) OutputTypeDeclaration(ctx *Context, decl *ast.TypeDeclaration) csyntax.CodeElement {
+	s, ok := decl.StructType()
+	if !ok {
+		return csyntax.NewTypedefDeclaration(c.OutputType(ctx, decl.Definition), decl.Name.Name)
+	}
+
+	fields := make([]csyntax.Statement, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		for _, comment := range OutputComments(field.LeadingComments()) {
+			fields = append(fields, comment)
+		}
+
+		fields = append(fields, csyntax.NewStructField(c.OutputType(ctx, field.Type), field.Name.Name))
+
+		for _, comment := range OutputComments(field.TrailingComments()) {
+			fields = append(fields, comment)
+		}
+	}
+
+	if s.RBrace != nil {
+		for _, comment := range OutputComments(s.RBrace.LeadingComments()) {
+			fields = append(fields, comment)
+		}
+	}
+
+	return csyntax.NewStructDefinition(decl.Name.Name, fields)
+}
+
 func (c *Coder) OutputFunctionDeclaration(ctx *Context, decl *ast.FunctionDeclaration) *csyntax.FunctionDeclaration {
-	if decl.Name.Name == "main" {
+	ctx.EnterFunction(decl)
+	defer ctx.LeaveFunction()
+
+	if decl.Receiver != nil {
+		name := decl.Receiver.Name.Name
+		ctx.DeclareVariable(name, decl.Receiver.Type, name, c.outputReceiverType(ctx, decl.Receiver))
+	}
+
+	if decl.Arguments != nil {
+		for _, arg := range decl.Arguments.Arguments {
+			if info, ok := ctx.DeclareVariable(arg.Name.Name, arg.Type, arg.Name.Name, c.OutputType(ctx, arg.Type)); ok {
+				info.Parameter = true
+			}
+		}
+	}
+
+	if isMainFunction(decl) {
 		return c.OutputMainFunction(ctx, decl)
 	}
 
@@ -207,51 +436,158 @@ func (c *Coder) OutputFunctionDeclaration(ctx *Context, decl *ast.FunctionDeclar
 	return f
 }
 
+// outputFunctionBody outputs statements of function, temporary variables used are declared before them.
 func (c *Coder) outputFunctionBody(ctx *Context, decl *ast.FunctionDeclaration, f *csyntax.FunctionDeclaration) *csyntax.FunctionDeclaration {
-	length := len(decl.Statements)
-	for i, stmt := range decl.Statements {
-		rs := c.OutputStatement(ctx, stmt)
-		for _, r := range rs {
-			f.AddStatement(r)
+	body := c.outputStatementList(ctx, decl.Statements, decl.RBrace)
+	if len(ctx.Temporaries) > 0 {
+		body = slices.Concat(ctx.Temporaries, []csyntax.Statement{csyntax.NewEmptyLine()}, body)
+	}
 
-		}
+	for _, r := range body {
+		f.AddStatement(r)
+	}
+
+	return f
+}
+
+// outputStatementList outputs statements separated by empty lines, comments before the closing brace are kept at
+// the end. Declarations of global variables used are only for checker, and are not output. Owners declared in the
+// frame are freed at the end, if it is reachable. Declarations after statements are nested in blocks in C89.
+func (c *Coder) outputStatementList(ctx *Context, stmts []ast.Statement, rBrace *ast.TerminalToken) []csyntax.Statement {
+	stmts = slices.DeleteFunc(slices.Clone(stmts), func(stmt ast.Statement) bool {
+		_, ok := stmt.(*ast.GlobalDeclaration)
+		return ok
+	})
+
+	result := make([]csyntax.Statement, 0, 2*len(stmts))
+	length := len(stmts)
+	for i, stmt := range stmts {
+		result = append(result, c.OutputStatement(ctx, stmt)...)
 
 		if i < length-1 {
-			f.AddStatement(csyntax.NewEmptyLine())
+			result = append(result, csyntax.NewEmptyLine())
 		}
 	}
 
-	return f
+	if !check.IsTerminated(stmts) {
+		if frees := c.outputScopeExit(ctx, ctx.FunctionFrame, nil); len(frees) > 0 {
+			result = append(append(result, csyntax.NewEmptyLine()), frees...)
+		}
+	}
+
+	if rBrace != nil {
+		for _, comment := range OutputComments(rBrace.LeadingComments()) {
+			result = append(result, comment)
+		}
+	}
+
+	return c.declarationsFirst(result)
 }
 
-func (c *Coder) OutputMainFunction(ctx *Context, decl *ast.FunctionDeclaration) *csyntax.FunctionDeclaration {
-	var f *csyntax.FunctionDeclaration
-	if decl.ReturnTypes == nil || decl.ReturnTypes.Length() == 0 {
-		f = csyntax.NewFunctionDeclaration("main", csyntax.NewConcreteType("void"), csyntax.NewParameterList(), nil)
-	} else {
-		f = csyntax.NewFunctionDeclaration("main", csyntax.NewConcreteType("int"), csyntax.NewParameterList(), nil)
+// OutputBlockStatement outputs statements of a block in a new frame, variables declared in the block are not
+// visible outside.
+func (c *Coder) OutputBlockStatement(ctx *Context, block *ast.BlockStatement) *csyntax.CodeBlock {
+	ctx.PushFrame()
+	defer ctx.PopFrame()
+
+	return csyntax.NewCodeBlock(c.outputStatementList(ctx, block.Statements, block.RBrace))
+}
+
+// OutputLoopBody outputs body of loop in a loop frame, owners declared in it are freed before 'break' and
+// 'continue'.
+func (c *Coder) OutputLoopBody(ctx *Context, block *ast.BlockStatement) *csyntax.CodeBlock {
+	ctx.PushLoopFrame()
+	defer ctx.PopFrame()
+
+	return csyntax.NewCodeBlock(c.outputStatementList(ctx, block.Statements, block.RBrace))
+}
+
+func (c *Coder) OutputIfStatement(ctx *Context, stmt *ast.IfStatement) *csyntax.IfStatement {
+	branches := make([]*csyntax.IfStatement, 0, len(stmt.Branches))
+	for _, branch := range stmt.Branches {
+		cond := c.OutputExpression(ctx, branch.Condition)
+		body := c.OutputBlockStatement(ctx, branch.Body)
+		branches = append(branches, csyntax.NewIfStatement(cond, body))
 	}
 
-	return c.outputFunctionBody(ctx, decl, f)
+	var elseBody *csyntax.CodeBlock
+	if stmt.ElseBody != nil {
+		elseBody = c.OutputBlockStatement(ctx, stmt.ElseBody)
+	}
+
+	return csyntax.NewIfElseChainStatement(branches, elseBody)
 }
 
-func (c *Coder) OutputFunctionSingleReturnValue(ctx *Context, decl *ast.FunctionDeclaration) *csyntax.FunctionDeclaration {
+// outputFunctionSignature returns the function with an empty body. Functions with multiple return values return
+// status, and values are returned by pointers in parameters.
+func (c *Coder) outputFunctionSignature(ctx *Context, info *FunctionInfo) *csyntax.FunctionDeclaration {
+	decl := info.Declaration
+	rcc := 0
+	if decl.ReturnTypes != nil {
+		rcc = decl.ReturnTypes.Length()
+	}
+
+	if isMainFunction(decl) {
+		if rcc == 0 {
+			return csyntax.NewFunctionDeclaration("main", csyntax.NewConcreteType("void"), csyntax.NewParameterList(), nil)
+		}
+
+		return csyntax.NewFunctionDeclaration("main", csyntax.NewConcreteType("int"), csyntax.NewParameterList(), nil)
+	}
+
+	returns := c.returnTypes(ctx, decl)
 	retType := csyntax.NewConcreteType("void")
-	if decl.ReturnTypes != nil && decl.ReturnTypes.Length() > 0 {
-		// FIXME: return type is always int for now
+	if rcc == 1 {
+		retType = returns[0]
+
+	} else if rcc > 1 {
 		retType = csyntax.NewConcreteType("int")
 	}
 
 	params := make([]*csyntax.ParameterListItem, 0, 10)
+	if decl.Receiver != nil {
+		item := csyntax.NewParameterListItem(c.outputReceiverType(ctx, decl.Receiver), decl.Receiver.Name.Name)
+		params = append(params, item)
+	}
+
+	if rcc > 1 {
+		for i, t := range returns {
+			outType := csyntax.NewType(string(t.Base), t.PointerLevel+1)
+			item := csyntax.NewParameterListItem(outType, OutputArgumentName(i))
+			params = append(params, item)
+		}
+	}
+
 	if decl.Arguments != nil {
 		for _, param := range decl.Arguments.Arguments {
-			// FIXME: parameter type is always int for now
-			item := csyntax.NewParameterListItem(csyntax.NewConcreteType("int"), param.Name.Name)
+			item := csyntax.NewParameterListItem(c.parameterType(ctx, param.Type), param.Name.Name)
 			params = append(params, item)
 		}
 	}
 
-	f := csyntax.NewFunctionDeclaration(decl.Name.Name, retType, csyntax.NewParameterList(params...), nil)
+	f := csyntax.NewFunctionDeclaration(info.CodeName, retType, csyntax.NewParameterList(params...), nil)
+	f.Static = info.Static
+	return f
+}
+
+// outputReceiverType returns type of receiver, which is always passed by pointer, no matter the receiver is
+// declared as a value or a pointer.
+func (c *Coder) outputReceiverType(ctx *Context, receiver *ast.ArgumentDeclaration) *csyntax.Type {
+	t := c.OutputType(ctx, receiver.Type)
+	if t.PointerLevel == 0 {
+		return csyntax.NewType(string(t.Base), 1)
+	}
+
+	return t
+}
+
+func (c *Coder) OutputMainFunction(ctx *Context, decl *ast.FunctionDeclaration) *csyntax.FunctionDeclaration {
+	f := c.outputFunctionSignature(ctx, ctx.FunctionOf(decl))
+	return c.outputFunctionBody(ctx, decl, f)
+}
+
+func (c *Coder) OutputFunctionSingleReturnValue(ctx *Context, decl *ast.FunctionDeclaration) *csyntax.FunctionDeclaration {
+	f := c.outputFunctionSignature(ctx, ctx.FunctionOf(decl))
 	return c.outputFunctionBody(ctx, decl, f)
 }
 
@@ -261,28 +597,16 @@ func (c *Coder) OutputFunctionMultipleReturnValues(ctx *Context, decl *ast.Funct
 		ctx.FunctionOut.Add(outputParamName, outputParamName)
 	}
 
-	retType := csyntax.NewConcreteType("int")
-	params := make([]*csyntax.ParameterListItem, 0, 10)
-	for _, name := range ctx.FunctionOut.Variables {
-		outType := csyntax.NewPointerType("int") // FIXME: output parameter type is always pointer to int for now
-		item := csyntax.NewParameterListItem(outType, name.CodeName)
-		params = append(params, item)
-	}
-
-	if decl.Arguments != nil {
-		for _, param := range decl.Arguments.Arguments {
-			// FIXME: parameter type is always int for now
-			item := csyntax.NewParameterListItem(csyntax.NewConcreteType("int"), param.Name.Name)
-			params = append(params, item)
-		}
-	}
-
-	f := csyntax.NewFunctionDeclaration(decl.Name.Name, retType, csyntax.NewParameterList(params...), nil)
+	f := c.outputFunctionSignature(ctx, ctx.FunctionOf(decl))
 	return c.outputFunctionBody(ctx, decl, f)
 }
 
 func (c *Coder) OutputStatement(ctx *Context, stmt ast.Statement) []csyntax.Statement {
 	result := make([]csyntax.Statement, 0, 10)
+	for _, comment := range OutputComments(stmt.LeadingComments()) {
+		result = append(result, comment)
+	}
+
 	result = append(result, csyntax.NewContext(stmt.Context()))
 
 	switch s := stmt.(type) {
@@ -295,6 +619,54 @@ func (c *Coder) OutputStatement(ctx *Context, stmt ast.Statement) []csyntax.Stat
 	case *ast.ReturnStatement:
 		result = append(result, c.OutputReturnStatement(ctx, s)...)
 
+	case *ast.VariableDeclaration:
+		result = append(result, c.outputOwnedDeclaration(ctx, s)...)
+
+	case *ast.InferenceDeclaration:
+		result = append(result, c.OutputInferenceDeclaration(ctx, s)...)
+
+	case *ast.ExpressionStatement:
+		result = append(result, csyntax.NewExpressionStatement(c.OutputExpression(ctx, s.Expression)))
+
+	case *ast.AssignmentStatement:
+		result = append(result, c.OutputAssignmentStatement(ctx, s)...)
+
+	case *ast.IncrementStatement:
+		result = append(result, csyntax.NewExpressionStatement(c.outputSimpleStatement(ctx, s)))
+
+	case *ast.IfStatement:
+		result = append(result, c.OutputIfStatement(ctx, s))
+
+	case *ast.WhileStatement:
+		cond := c.OutputExpression(ctx, s.Condition)
+		result = append(result, csyntax.NewWhileStatement(cond, c.OutputLoopBody(ctx, s.Body)))
+
+	case *ast.DoWhileStatement:
+		body := c.OutputLoopBody(ctx, s.Body)
+		result = append(result, csyntax.NewDoWhileStatement(body, c.OutputExpression(ctx, s.Condition)))
+
+	case *ast.ForStatement:
+		result = append(result, c.scopedForStatement(c.OutputForStatement(ctx, s)))
+
+	case *ast.ForeachStatement:
+		result = append(result, c.scopedForStatement(c.OutputForeachStatement(ctx, s)))
+
+	case *ast.DeleteStatement:
+		result = append(result, c.OutputDeleteStatement(ctx, s)...)
+
+	case *ast.LoopControlStatement:
+		result = append(result, c.outputScopeExit(ctx, ctx.LoopFrame(), nil)...)
+		if s.IsBreak() {
+			result = append(result, csyntax.NewBreakStatement())
+
+		} else {
+			result = append(result, csyntax.NewContinueStatement())
+		}
+
+	}
+
+	for _, comment := range OutputComments(stmt.TrailingComments()) {
+		result = append(result, comment)
 	}
 
 	return result
@@ -318,17 +690,430 @@ func (c *Coder) OutputPreprocessorInline(ctx *Context, inline *ast.PreprocessorI
 	return block
 }
 
+func (c *Coder) outputDeclarationStatement(ctx *Context, name *ast.Identifier, sourceType ast.Type, typ *csyntax.Type, isConst bool, value csyntax.Expression) *csyntax.DeclarationStatement {
+	ctx.DeclareVariable(name.Name, sourceType, name.Name, typ)
+
+	base := string(typ.Base)
+	if isConst {
+		base = "const " + base
+	}
+
+	declarator := csyntax.NewTypedDeclarator(typ, name.Name, declarationInitializer(ctx, typ, value))
+	decl := csyntax.NewVariableDeclaration(base, []csyntax.VariableDeclarationItem{declarator})
+	return csyntax.NewDeclarationStatement(decl)
+}
+
+func (c *Coder) OutputVariableDeclaration(ctx *Context, decl *ast.VariableDeclaration) *csyntax.DeclarationStatement {
+	var typ *csyntax.Type
+	if decl.IsAuto() && decl.Value != nil {
+		typ = c.InferExpressionType(ctx, decl.Value)
+
+	} else {
+		typ = c.OutputType(ctx, decl.Type)
+	}
+
+	if typ == nil {
+		typ = csyntax.NewConcreteType("int")
+	}
+
+	var value csyntax.Expression
+	var allocation *VariableInfo
+	if decl.Value != nil {
+		value = c.OutputExpression(ctx, decl.Value)
+		allocation = allocationOf(ctx, decl.Value)
+
+	} else if !ctx.IsGlobalContext() && !decl.IsRef() && isOwnerType(typ) {
+		// owners are initialized as null, to be freed safely
+		value = csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+	}
+
+	stmt := c.outputDeclarationStatement(ctx, decl.Name, decl.Type, typ, decl.IsConst(), value)
+	trackAllocation(ctx, decl.Name, allocation)
+	return stmt
+}
+
+func (c *Coder) OutputInferenceDeclaration(ctx *Context, decl *ast.InferenceDeclaration) []csyntax.Statement {
+	names := decl.Identifiers()
+	stmts := make([]csyntax.Statement, 0, len(names)+1)
+
+	if len(names) > 1 && decl.Values.Length() == 1 {
+		if call, ok := decl.Values.Expressions[0].Expression.(*ast.CallExpression); ok {
+			outputs := make([]csyntax.Expression, len(names))
+			types := c.outputTypes(ctx, call, len(names))
+			for i, name := range names {
+				if name.IsDummy() {
+					continue
+				}
+
+				stmts = append(stmts, c.outputDeclarationStatement(ctx, name, nil, types[i], false, nil))
+				outputs[i] = csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, csyntax.NewIdentifier(name.Name))
+				if !decl.IsRef() {
+					// pointers returned by call are owned, which is checked by checker
+					declareOwner(ctx, name)
+				}
+			}
+
+			stmts = append(stmts, csyntax.NewExpressionStatement(c.OutputCallExpression(ctx, call, outputs)))
+			return stmts
+		}
+	}
+
+	var moved []csyntax.Expression
+	for i, name := range names {
+		if i >= decl.Values.Length() {
+			break
+		}
+
+		expr := decl.Values.Expressions[i].Expression
+		if !name.IsDummy() {
+			if sequenced, ok := c.outputSequencedDeclaration(ctx, name, nil, false, expr); ok {
+				moved = append(moved, c.movedSources(ctx, expr)...)
+				stmts = append(stmts, sequenced...)
+				declareOwner(ctx, name)
+				continue
+			}
+		}
+
+		value := c.OutputExpression(ctx, expr)
+		if name.IsDummy() {
+			stmts = append(stmts, csyntax.NewExpressionStatement(value))
+			continue
+		}
+
+		ref := isReference(ctx, decl.IsRef(), expr)
+		if !ref {
+			moved = append(moved, c.movedSources(ctx, expr)...)
+		}
+
+		typ, allocation := c.InferExpressionType(ctx, expr), allocationOf(ctx, expr)
+		stmts = append(stmts, c.outputDeclarationStatement(ctx, name, nil, typ, false, value))
+		trackAllocation(ctx, name, allocation)
+		if !ref {
+			declareOwner(ctx, name)
+		}
+	}
+
+	return append(stmts, outputMoves(ctx, moved)...)
+}
+
+// OutputAssignmentStatement outputs an assignment. Multiple values are evaluated into temporary variables before
+// assigned, to keep the semantics of parallel assignment like `a, b = b, a`.
+func (c *Coder) OutputAssignmentStatement(ctx *Context, assign *ast.AssignmentStatement) []csyntax.Statement {
+	op := AssignOperatorMap(assign.Operator.Token)
+	targets := assign.Targets.Expressions
+	stmts := make([]csyntax.Statement, 0, 2*len(targets))
+
+	if len(targets) > 1 && assign.Values.Length() == 1 {
+		if call, ok := assign.Values.Expressions[0].Expression.(*ast.CallExpression); ok {
+			return c.outputCallAssignment(ctx, assign, call)
+		}
+	}
+
+	if len(targets) == 1 {
+		return c.outputSingleAssignment(ctx, assign, targets[0].Expression, assign.Values.Expressions[0].Expression)
+	}
+
+	var moved []csyntax.Expression
+	temps := make([]string, len(targets))
+	for i, target := range targets {
+		if i >= assign.Values.Length() {
+			break
+		}
+
+		expr := assign.Values.Expressions[i].Expression
+		value := c.OutputExpression(ctx, expr)
+		if id, ok := target.Expression.(*ast.Identifier); ok && id.IsDummy() {
+			stmts = append(stmts, csyntax.NewExpressionStatement(value))
+			continue
+		}
+
+		if assign.Operator.Token == ast.Assign {
+			moved = append(moved, c.assignedSources(ctx, target.Expression, expr)...)
+		}
+
+		temps[i] = ctx.TempName()
+		typ := c.InferExpressionType(ctx, expr)
+		declarator := csyntax.NewVariableDeclarator(temps[i], typ.PointerLevel, value)
+		decl := csyntax.NewVariableDeclaration(string(typ.Base), []csyntax.VariableDeclarationItem{declarator})
+		stmts = append(stmts, csyntax.NewDeclarationStatement(decl))
+	}
+
+	// sources are moved before owners assigned are freed, since values may be swapped between owners
+	stmts = append(stmts, outputMoves(ctx, moved)...)
+	for i, target := range targets {
+		if len(temps[i]) == 0 {
+			continue
+		}
+
+		stmts = append(stmts, c.outputAssignedFree(ctx, assign, target.Expression)...)
+	}
+
+	for i, target := range targets {
+		if len(temps[i]) == 0 {
+			continue
+		}
+
+		lvalue := c.OutputExpression(ctx, target.Expression)
+		stmts = append(stmts, csyntax.NewAssignmentStatementTo(lvalue, op, csyntax.NewIdentifier(temps[i])))
+	}
+
+	return stmts
+}
+
+// outputCallAssignment outputs assignment of values returned by a call to multiple targets. Values of owners are
+// returned into temporary variables, and old values are freed after the call, which may still use them.
+func (c *Coder) outputCallAssignment(ctx *Context, assign *ast.AssignmentStatement, call *ast.CallExpression) []csyntax.Statement {
+	targets := assign.Targets.Expressions
+	types := c.outputTypes(ctx, call, len(targets))
+	outputs := make([]csyntax.Expression, len(targets))
+	var stmts, frees, assigns []csyntax.Statement
+	for i, target := range targets {
+		if id, ok := target.Expression.(*ast.Identifier); ok && id.IsDummy() {
+			continue
+		}
+
+		lvalue := c.OutputExpression(ctx, target.Expression)
+		free := c.outputAssignedFree(ctx, assign, target.Expression)
+		if len(free) == 0 {
+			outputs[i] = csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, lvalue)
+			continue
+		}
+
+		temp := ctx.TempName()
+		declarator := csyntax.NewTypedDeclarator(types[i], temp, nil)
+		stmts = append(stmts, csyntax.NewDeclarationStatement(
+			csyntax.NewVariableDeclaration(string(types[i].Base), []csyntax.VariableDeclarationItem{declarator})))
+		outputs[i] = csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, csyntax.NewIdentifier(temp))
+		frees = append(frees, free...)
+		assigns = append(assigns, csyntax.NewAssignmentStatementTo(lvalue, csyntax.OperatorAssign, csyntax.NewIdentifier(temp)))
+	}
+
+	stmts = append(stmts, csyntax.NewExpressionStatement(c.OutputCallExpression(ctx, call, outputs)))
+	stmts = append(stmts, frees...)
+	return append(stmts, assigns...)
+}
+
+// outputAssignedFree frees value owned by target before it is overwritten by assignment, which is an owner, a
+// structure owning fields, or a field of pointer or list.
+func (c *Coder) outputAssignedFree(ctx *Context, assign *ast.AssignmentStatement, target ast.Expression) []csyntax.Statement {
+	if info, ok := c.assignedOwner(ctx, assign, target); ok {
+		return c.outputVariableFree(ctx, info)
+	}
+
+	member, ok := target.(*ast.MemberExpression)
+	if !ok || assign.Operator.Token != ast.Assign {
+		return nil
+	}
+
+	if id, ok := member.Object.(*ast.Identifier); ok && ctx.IsImport(id.Name) {
+		return nil
+	}
+
+	t := c.InferExpressionType(ctx, target)
+	if !isOwnerType(t) {
+		return nil
+	}
+
+	return []csyntax.Statement{outputFree(ctx, t, c.OutputExpression(ctx, target))}
+}
+
+// outputSingleAssignment outputs assignment to one target. Value of owner assigned is freed, and sources moved out of
+// members or elements are set to null, after value is evaluated into a temporary variable.
+func (c *Coder) outputSingleAssignment(ctx *Context, assign *ast.AssignmentStatement, target ast.Expression, value ast.Expression) []csyntax.Statement {
+	op := AssignOperatorMap(assign.Operator.Token)
+	var moved []csyntax.Expression
+	if assign.Operator.Token == ast.Assign {
+		moved = c.assignedSources(ctx, target, value)
+	}
+
+	lvalue, rvalue := c.OutputExpression(ctx, target), c.OutputExpression(ctx, value)
+	frees := c.outputAssignedFree(ctx, assign, target)
+	owner, isOwner := c.assignedOwner(ctx, assign, target)
+	temporary := len(frees) > 0 || slices.ContainsFunc(moved, func(source csyntax.Expression) bool {
+		_, ok := source.(*csyntax.Identifier)
+		return !ok
+	})
+
+	if !temporary {
+		stmts := []csyntax.Statement{csyntax.NewAssignmentStatementTo(lvalue, op, rvalue)}
+		return append(stmts, outputMoves(ctx, moved)...)
+	}
+
+	temp := ctx.TempName()
+	typ := c.InferExpressionType(ctx, value)
+	declarator := csyntax.NewTypedDeclarator(typ, temp, rvalue)
+	stmts := []csyntax.Statement{
+		csyntax.NewDeclarationStatement(csyntax.NewVariableDeclaration(string(typ.Base), []csyntax.VariableDeclarationItem{declarator})),
+	}
+
+	// target moved into value is null, and is not freed
+	stmts = append(stmts, outputMoves(ctx, moved)...)
+	if !isOwner || !slices.ContainsFunc(moved, func(source csyntax.Expression) bool {
+		id, ok := source.(*csyntax.Identifier)
+		return ok && id.Name == owner.CodeName
+	}) {
+		stmts = append(stmts, frees...)
+	}
+
+	return append(stmts, csyntax.NewAssignmentStatementTo(lvalue, op, csyntax.NewIdentifier(temp)))
+}
+
+// assignedOwner returns the owner variable or structure owning fields assigned, whose old value is freed.
+func (c *Coder) assignedOwner(ctx *Context, assign *ast.AssignmentStatement, target ast.Expression) (*VariableInfo, bool) {
+	id, ok := target.(*ast.Identifier)
+	if !ok || assign.Operator.Token != ast.Assign {
+		return nil, false
+	}
+
+	info, found := ctx.Find(id.Name)
+	if !found || (!info.Owner && !info.Fields) {
+		return nil, false
+	}
+
+	return info, true
+}
+
+// outputSimpleStatement outputs statement in header of for loop as an expression.
+func (c *Coder) outputSimpleStatement(ctx *Context, stmt ast.Statement) csyntax.Expression {
+	switch s := stmt.(type) {
+	case *ast.IncrementStatement:
+		op := csyntax.OperatorIncrement
+		if s.IsDecrement() {
+			op = csyntax.OperatorDecrement
+		}
+
+		return csyntax.NewPostfixExpression(c.OutputExpression(ctx, s.Target), op)
+
+	case *ast.AssignmentStatement:
+		target := c.OutputExpression(ctx, s.Targets.Expressions[0].Expression)
+		value := c.OutputExpression(ctx, s.Values.Expressions[0].Expression)
+		return csyntax.NewAssignmentExpressionTo(target, AssignOperatorMap(s.Operator.Token), value)
+
+	case *ast.ExpressionStatement:
+		return c.OutputExpression(ctx, s.Expression)
+
+	default:
+		err := fmt.Errorf("unsupported statement in for loop: %T", s)
+		panic(err)
+	}
+}
+
+func (c *Coder) outputForInitializer(ctx *Context, stmt ast.Statement) csyntax.ForInitializer {
+	switch s := stmt.(type) {
+	case *ast.VariableDeclaration:
+		return c.OutputVariableDeclaration(ctx, s).VariableDeclaration
+
+	case *ast.InferenceDeclaration:
+		name, expr := s.Identifiers()[0], s.Values.Expressions[0].Expression
+		value, allocation := c.OutputExpression(ctx, expr), allocationOf(ctx, expr)
+		stmt := c.outputDeclarationStatement(ctx, name, nil, c.InferExpressionType(ctx, expr), false, value)
+		trackAllocation(ctx, name, allocation)
+		return stmt.VariableDeclaration
+	}
+
+	return c.outputSimpleStatement(ctx, stmt)
+}
+
+func (c *Coder) OutputForStatement(ctx *Context, stmt *ast.ForStatement) *csyntax.ForStatement {
+	ctx.PushFrame()
+	defer ctx.PopFrame()
+
+	var init csyntax.ForInitializer
+	if stmt.Initializer != nil {
+		init = c.outputForInitializer(ctx, stmt.Initializer)
+	}
+
+	var cond csyntax.Expression
+	if stmt.Condition != nil {
+		cond = c.OutputExpression(ctx, stmt.Condition)
+	}
+
+	var post csyntax.Expression
+	if stmt.Post != nil {
+		post = c.outputSimpleStatement(ctx, stmt.Post)
+	}
+
+	return csyntax.NewForStatement(init, cond, post, c.OutputLoopBody(ctx, stmt.Body))
+}
+
+// OutputForeachStatement outputs foreach as a for loop on index. The length of fixed array is its declared length,
+// which is computed by sizeof if the array is unknown, like those declared in inline C, and the length of list is
+// read in every iteration, since the list may be resized in loop.
+func (c *Coder) OutputForeachStatement(ctx *Context, stmt *ast.ForeachStatement) *csyntax.ForStatement {
+	index := csyntax.NewIdentifier(ctx.TempName())
+	iterable := c.OutputExpression(ctx, stmt.Iterable)
+	iterableType := c.InferExpressionType(ctx, stmt.Iterable)
+
+	indexType := "int"
+	var length csyntax.Expression
+	var element csyntax.Expression
+	if iterableType.IsList() {
+		indexType = "long"
+		length = csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListLength)), iterable)
+		element = listElement(ctx, iterableType, iterable, index, stmt.Iterable)
+
+	} else if iterableType.IsArray() {
+		length = csyntax.NewIntegerLiteral(int64(iterableType.ArraySize))
+		element = csyntax.NewIndexExpression(iterable, index)
+
+	} else {
+		first := csyntax.NewIndexExpression(iterable, csyntax.NewIntegerLiteral(0))
+		length = csyntax.NewInfixExpression(
+			csyntax.NewSizeofExpression(iterable), csyntax.OperatorDivide, csyntax.NewSizeofExpression(first))
+		element = csyntax.NewIndexExpression(iterable, index)
+	}
+
+	init := csyntax.NewVariableDeclaration(indexType, []csyntax.VariableDeclarationItem{
+		csyntax.NewVariableDeclarator(string(index.Name), 0, csyntax.NewIntegerLiteral(0)),
+	})
+	cond := csyntax.NewInfixExpression(index, csyntax.OperatorLessThan, length)
+
+	ctx.PushLoopFrame()
+	defer ctx.PopFrame()
+
+	typ := elementType(iterableType)
+	body := csyntax.NewCodeBlock([]csyntax.Statement{
+		c.outputDeclarationStatement(ctx, stmt.Variable, nil, typ, false, element),
+	})
+
+	for _, s := range c.outputStatementList(ctx, stmt.Body.Statements, stmt.Body.RBrace) {
+		body.Add(s)
+	}
+
+	return csyntax.NewForStatement(init, cond, index.IncrPostfix(), body)
+}
+
+// OutputReturnStatement outputs return, owners in function are freed before returning, except those returned.
+// Return value using owners is evaluated into a temporary variable before freed.
 func (c *Coder) OutputReturnStatement(ctx *Context, ret *ast.ReturnStatement) []csyntax.Statement {
 	stmts := make([]csyntax.Statement, 0, 10)
 	if ret.Value == nil || ret.Value.Length() <= 0 {
+		stmts = append(stmts, c.outputScopeExit(ctx, nil, nil)...)
 		stmts = append(stmts, csyntax.NewReturnStatement(nil))
 		return stmts
 	}
 
+	var returned []*VariableInfo
+	for _, item := range ret.Value.Expressions {
+		returned = append(returned, returnedOwners(ctx, item.Expression)...)
+	}
+
+	frees := c.outputScopeExit(ctx, nil, returned)
 	if ret.Value.Length() == 1 {
-		expr := ret.Value.Expressions[0]
-		stmt := c.OutputReturnStatementSingleValue(expr.Expression)
-		stmts = append(stmts, stmt)
+		expr := ret.Value.Expressions[0].Expression
+		if len(frees) == 0 || isSimpleValue(expr) {
+			stmts = append(stmts, frees...)
+			stmts = append(stmts, c.OutputReturnStatementSingleValue(ctx, expr))
+			return stmts
+		}
+
+		temp := ctx.TempName()
+		typ := c.InferExpressionType(ctx, expr)
+		declarator := csyntax.NewTypedDeclarator(typ, temp, c.OutputExpression(ctx, expr))
+		decl := csyntax.NewVariableDeclaration(string(typ.Base), []csyntax.VariableDeclarationItem{declarator})
+		stmts = append(stmts, csyntax.NewDeclarationStatement(decl))
+		stmts = append(stmts, frees...)
+		stmts = append(stmts, csyntax.NewReturnStatement(csyntax.NewIdentifier(temp)))
 		return stmts
 	}
 
@@ -336,12 +1121,12 @@ func (c *Coder) OutputReturnStatement(ctx *Context, ret *ast.ReturnStatement) []
 		outputParamName := OutputArgumentName(i)
 
 		cond := csyntax.NewInfixExpression(
-			csyntax.NewIdentifier("NULL"),
-			csyntax.OperatorEqual,
+			csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL")),
+			csyntax.OperatorNotEqual,
 			csyntax.NewIdentifier(outputParamName))
 
-		cexpr := c.OutputExpression(expr.Expression)
-		assign := csyntax.NewAssignmentStatement(outputParamName, 1, cexpr) // FIXME: output parameter type is always pointer to concrete type for now
+		cexpr := c.OutputExpression(ctx, expr.Expression)
+		assign := csyntax.NewAssignmentStatement(outputParamName, 1, cexpr)
 		body := csyntax.NewCodeBlock([]csyntax.Statement{
 			assign,
 		})
@@ -350,27 +1135,125 @@ func (c *Coder) OutputReturnStatement(ctx *Context, ret *ast.ReturnStatement) []
 		stmts = append(stmts, ifStmt)
 	}
 
+	stmts = append(stmts, frees...)
 	stmts = append(stmts, csyntax.NewReturnStatement(csyntax.NewIntegerLiteral(0)))
 	return stmts
 }
 
-func (c *Coder) OutputReturnStatementSingleValue(expr ast.Expression) *csyntax.ReturnStatement {
-	value := c.OutputExpression(expr)
+func (c *Coder) OutputReturnStatementSingleValue(ctx *Context, expr ast.Expression) *csyntax.ReturnStatement {
+	value := c.OutputExpression(ctx, expr)
 	return csyntax.NewReturnStatement(value)
 }
 
-func (c *Coder) OutputExpression(expr ast.Expression) csyntax.Expression {
+func (c *Coder) OutputExpression(ctx *Context, expr ast.Expression) csyntax.Expression {
 	switch e := expr.(type) {
 	case *ast.Identifier:
+		if info, found := ctx.Find(e.Name); found {
+			return csyntax.NewIdentifier(info.CodeName)
+		}
+
 		return csyntax.NewIdentifier(e.Name)
 
 	case *ast.IntegerLiteral:
 		return csyntax.NewIntegerLiteral(int64(e.Value))
 
+	case *ast.FloatLiteral:
+		return csyntax.NewFloatLiteral(e.Value)
+
+	case *ast.StringLiteral:
+		return csyntax.NewStringLiteral(e.Value)
+
+	case *ast.BooleanLiteral:
+		if e.Value {
+			return csyntax.NewIntegerLiteral(1)
+		}
+
+		return csyntax.NewIntegerLiteral(0)
+
+	case *ast.NullLiteral:
+		return csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+
+	case *ast.PrefixExpression:
+		if e.Operator.Token == ast.Ref {
+			// a reference is a pointer in C, ownership is only checked
+			return c.OutputExpression(ctx, e.Operand)
+		}
+
+		op := PrefixOperatorMap(e.Operator.Token)
+		operand := c.OutputExpression(ctx, e.Operand)
+		if e.Operator.Token == ast.Ampersand && c.InferExpressionType(ctx, e.Operand).IsArray() {
+			// address of an array is pointer to its first element, which array decays to
+			return operand
+		}
+
+		return csyntax.NewUnaryExpression(op, operand)
+
+	case *ast.CallExpression:
+		if name, ok := check.BuiltinCall(e); ok {
+			return c.OutputBuiltinCall(ctx, name, e)
+		}
+
+		if name, ok := check.ConversionCall(e); ok {
+			value := c.OutputExpression(ctx, e.Arguments.Expressions[0].Expression)
+			return csyntax.NewCastExpression(c.OutputType(ctx, ast.ASTBuildSimpleType(name)), value)
+		}
+
+		return c.OutputCallExpression(ctx, e, nil)
+
+	case *ast.ParenthesizedExpression:
+		// parentheses are generated by nesting of expressions
+		return c.OutputExpression(ctx, e.Expression)
+
+	case *ast.MemberExpression:
+		if id, ok := e.Object.(*ast.Identifier); ok && ctx.IsImport(id.Name) {
+			// global variable of imported module, like 'foo.count'
+			name := QualifiedName(id.Name, e.Member.Name)
+			if info, found := ctx.Find(name); found {
+				name = info.CodeName
+			}
+
+			return csyntax.NewIdentifier(name)
+		}
+
+		object := c.OutputExpression(ctx, e.Object)
+		if typ := c.InferExpressionType(ctx, e.Object); typ.PointerLevel > 0 {
+			return csyntax.NewPointerMemberExpression(object, e.Member.Name)
+		}
+
+		return csyntax.NewMemberExpression(object, e.Member.Name)
+
+	case *ast.IndexExpression:
+		return c.OutputIndexExpression(ctx, e)
+
+	case *ast.ArrayLiteral:
+		values := make([]csyntax.Expression, 0, e.Elements.Length())
+		for _, item := range e.Elements.Expressions {
+			values = append(values, c.OutputExpression(ctx, item.Expression))
+		}
+
+		if len(values) == 0 {
+			// empty initializer is not allowed before C23
+			values = append(values, csyntax.NewIntegerLiteral(0))
+		}
+
+		return c.outputArrayValue(ctx, c.arrayLiteralType(ctx, e), values)
+
+	case *ast.StructLiteral:
+		return c.OutputStructLiteral(ctx, e)
+
+	case *ast.NewExpression:
+		return c.OutputNewExpression(ctx, e)
+
+	case *ast.PointerArithmeticExpression:
+		return c.OutputPointerArithmetic(ctx, e)
+
+	case *ast.SizeofExpression:
+		return c.OutputSizeofExpression(ctx, e)
+
 	case *ast.InfixExpression:
-		left := c.OutputExpression(e.LeftOperand)
+		left := c.OutputExpression(ctx, e.LeftOperand)
 		op := OperatorMap(e.Operator.Token)
-		right := c.OutputExpression(e.RightOperand)
+		right := c.OutputExpression(ctx, e.RightOperand)
 		return csyntax.NewInfixExpression(left, op, right)
 
 	default:
@@ -378,3 +1261,283 @@ func (c *Coder) OutputExpression(expr ast.Expression) csyntax.Expression {
 		panic(err)
 	}
 }
+
+// OutputCallExpression outputs a function call. Functions with multiple return values are lowered with output
+// parameters, which are NULL if not given in outputs.
+func (c *Coder) OutputCallExpression(ctx *Context, call *ast.CallExpression, outputs []csyntax.Expression) *csyntax.CallExpression {
+	args := make([]csyntax.Expression, 0, call.Arguments.Length()+len(outputs)+1)
+	name, ok := call.FunctionName()
+	resolved, receiver, isResolved := c.resolveCallee(ctx, call)
+	if isResolved {
+		name, ok = resolved, true
+		if receiver != nil {
+			args = append(args, receiver)
+		}
+	}
+
+	var callee csyntax.Expression
+	if ok {
+		if info, found := ctx.UseFunction(name); found {
+			callee = csyntax.NewIdentifier(info.CodeName)
+			for i := 0; info.ReturnCount > 1 && i < info.ReturnCount; i++ {
+				if i < len(outputs) && outputs[i] != nil {
+					args = append(args, outputs[i])
+
+				} else {
+					args = append(args, csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL")))
+				}
+			}
+		}
+	}
+
+	for _, arg := range call.Arguments.Expressions {
+		args = append(args, c.OutputExpression(ctx, arg.Expression))
+	}
+
+	if callee == nil {
+		callee = c.OutputExpression(ctx, call.Callee)
+	}
+
+	return csyntax.NewCallExpression(callee, args...)
+}
+
+// resolveCallee resolves a call like 'foo.bar()', 'Point.New()' or 'p.Distance()' to name of function in context.
+// Receiver of an instance method is passed as pointer, address of a value is taken, and pointers to pointer are
+// dereferenced.
+func (c *Coder) resolveCallee(ctx *Context, call *ast.CallExpression) (string, csyntax.Expression, bool) {
+	name, t, ok := c.methodName(ctx, call)
+	if !ok || t == nil {
+		return name, nil, ok
+	}
+
+	member := call.Callee.(*ast.MemberExpression)
+	receiver := c.OutputExpression(ctx, member.Object)
+	if t.PointerLevel == 0 {
+		if !c.isAddressable(ctx, member.Object) {
+			// value like result of call has no address, it is stored in a temporary variable
+			temp := ctx.DeclareTemporary(t, nil)
+			receiver = csyntax.NewCommaExpression(csyntax.NewAssignmentExpressionTo(temp, csyntax.OperatorAssign, receiver), temp)
+		}
+
+		receiver = addressOfValue(receiver)
+	}
+
+	for i := 1; i < t.PointerLevel; i++ {
+		receiver = csyntax.NewUnaryExpression(csyntax.OperatorDereference, receiver)
+	}
+
+	return name, receiver, true
+}
+
+// isAddressable tells whether address of value of expr can be taken in C. Struct literals are compound literals, or
+// temporary variables in C89.
+func (c *Coder) isAddressable(ctx *Context, expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.Identifier, *ast.IndexExpression, *ast.StructLiteral:
+		return true
+
+	case *ast.MemberExpression:
+		return c.InferExpressionType(ctx, e.Object).PointerLevel > 0 || c.isAddressable(ctx, e.Object)
+
+	case *ast.PrefixExpression:
+		return e.Operator.Token == ast.Asterisk
+
+	case *ast.ParenthesizedExpression:
+		return c.isAddressable(ctx, e.Expression)
+	}
+
+	return false
+}
+
+// methodName returns name of function called by member of a module, a type or an instance. Type of receiver is
+// returned for instance methods, and is nil for others.
+func (c *Coder) methodName(ctx *Context, call *ast.CallExpression) (string, *csyntax.Type, bool) {
+	member, ok := call.Callee.(*ast.MemberExpression)
+	if !ok {
+		return "", nil, false
+	}
+
+	if id, ok := member.Object.(*ast.Identifier); ok {
+		if ctx.IsImport(id.Name) {
+			return QualifiedName(id.Name, member.Member.Name), nil, true
+		}
+
+		if _, isVariable := ctx.Find(id.Name); !isVariable {
+			if _, isType := ctx.Types[id.Name]; isType {
+				name := MethodName(id.Name, member.Member.Name)
+				if info, found := ctx.Functions[name]; found && info.Declaration.Receiver == nil {
+					return name, nil, true
+				}
+			}
+		}
+	}
+
+	t := c.InferExpressionType(ctx, member.Object)
+	name := MethodName(string(t.Base), member.Member.Name)
+	info, found := ctx.Functions[name]
+	if !found || info.Declaration.Receiver == nil {
+		return "", nil, false
+	}
+
+	return name, t, true
+}
+
+// calledFunction returns the function called, which is not found for functions out of context, like those in C.
+func (c *Coder) calledFunction(ctx *Context, call *ast.CallExpression) (*FunctionInfo, bool) {
+	name, ok := call.FunctionName()
+	if resolved, _, isResolved := c.methodName(ctx, call); isResolved {
+		name, ok = resolved, true
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	info, found := ctx.Functions[name]
+	return info, found
+}
+
+// sourcePosition returns file name and line of node in source, which are reported by runtime checks.
+func sourcePosition(node ast.Node) []csyntax.Expression {
+	filename, line, _ := node.Context().Position()
+	return []csyntax.Expression{csyntax.NewStringLiteral(filename), csyntax.NewIntegerLiteral(int64(line + 1))}
+}
+
+// OutputIndexExpression outputs element of array, list or pointer. Index of list is always checked at runtime, and
+// in debug mode, index of fixed size array is checked too. Out of bounds access aborts with position in source.
+func (c *Coder) OutputIndexExpression(ctx *Context, e *ast.IndexExpression) csyntax.Expression {
+	object := c.OutputExpression(ctx, e.Object)
+	index := c.OutputExpression(ctx, e.Index)
+
+	typ := c.InferExpressionType(ctx, e.Object)
+	if typ.IsList() {
+		return listElement(ctx, typ, object, index, e.Index)
+	}
+
+	if _, constant := c.constantInteger(ctx, e.Index); c.Debug && typ.IsArray() && !constant {
+		args := append([]csyntax.Expression{index, csyntax.NewIntegerLiteral(int64(typ.ArraySize))}, sourcePosition(e.Index)...)
+		index = csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeCheckIndex)), args...)
+	}
+
+	return csyntax.NewIndexExpression(object, index)
+}
+
+// listElement outputs element of a list in type of typ, index is checked at runtime and reported at position of node.
+func listElement(ctx *Context, typ *csyntax.Type, list csyntax.Expression, index csyntax.Expression, node ast.Node) csyntax.Expression {
+	args := append([]csyntax.Expression{list, index}, sourcePosition(node)...)
+	at := csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListAt)), args...)
+	element := typ.Element()
+	return csyntax.NewUnaryExpression(csyntax.OperatorDereference,
+		csyntax.NewCastExpression(csyntax.NewType(string(element.Base), element.PointerLevel+1), at))
+}
+
+// OutputNewExpression outputs allocation in heap. Lists are created by runtime, and other values are copied from a
+// compound literal into memory allocated, or zero initialized if no value is given.
+func (c *Coder) OutputNewExpression(ctx *Context, e *ast.NewExpression) csyntax.Expression {
+	if l, ok := e.ListLiteral(); ok {
+		return c.OutputListLiteral(ctx, l)
+	}
+
+	typ := c.newValueType(ctx, e)
+	var value csyntax.Expression = csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+	switch v := e.Value.(type) {
+	case *ast.StructLiteral:
+		if len(v.Fields) > 0 {
+			value = addressOfValue(c.OutputStructLiteral(ctx, v))
+		}
+
+	case *ast.CallExpression:
+		values := make([]csyntax.Expression, 0, 4)
+		for _, arg := range v.Arguments.Expressions {
+			values = append(values, c.OutputExpression(ctx, arg.Expression))
+		}
+
+		if len(values) > 0 {
+			value = c.outputInitialValue(ctx, typ, values[0])
+		}
+	}
+
+	alloc := csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeNew)), csyntax.NewSizeofType(typ), value)
+	return csyntax.NewCastExpression(csyntax.NewType(string(typ.Base), typ.PointerLevel+1), alloc)
+}
+
+// OutputDeleteStatement frees memory owned by target, and sets target to null to avoid dangling pointer.
+func (c *Coder) OutputDeleteStatement(ctx *Context, s *ast.DeleteStatement) []csyntax.Statement {
+	return []csyntax.Statement{
+		outputFree(ctx, c.InferExpressionType(ctx, s.Target), c.OutputExpression(ctx, s.Target)),
+		csyntax.NewAssignmentStatementTo(c.OutputExpression(ctx, s.Target), csyntax.OperatorAssign, csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))),
+	}
+}
+
+// OutputListLiteral outputs a list allocated in heap, elements are copied from an array literal.
+func (c *Coder) OutputListLiteral(ctx *Context, l *ast.ArrayLiteral) csyntax.Expression {
+	element := c.OutputType(ctx, l.Type.ElementType)
+	count := l.Elements.Length()
+	length := count
+	if l.Type.Size != nil {
+		if size, ok := c.constantInteger(ctx, l.Type.Size); ok {
+			length = int(size)
+		}
+	}
+
+	var values csyntax.Expression = csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+	if count > 0 {
+		values = c.outputElements(ctx, element, l.Elements)
+	}
+
+	return csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListNew)),
+		csyntax.NewSizeofType(element),
+		csyntax.NewIntegerLiteral(int64(length)),
+		csyntax.NewIntegerLiteral(int64(count)),
+		values,
+	)
+}
+
+// outputElements outputs values as an array in type of element.
+func (c *Coder) outputElements(ctx *Context, element *csyntax.Type, list *ast.ExpressionList) csyntax.Expression {
+	values := make([]csyntax.Expression, 0, list.Length())
+	for _, item := range list.Expressions {
+		values = append(values, c.OutputExpression(ctx, item.Expression))
+	}
+
+	array := csyntax.NewArrayType(string(element.Base), element.PointerLevel, len(values))
+	return c.outputArrayValue(ctx, array, values)
+}
+
+// OutputBuiltinCall outputs builtin functions of lists and arrays. Length and capacity of fixed size arrays are
+// constants, and lists are resized by runtime functions.
+func (c *Coder) OutputBuiltinCall(ctx *Context, name string, call *ast.CallExpression) csyntax.Expression {
+	list := call.Arguments.Expressions[0].Expression
+	typ := c.InferExpressionType(ctx, list)
+	object := c.OutputExpression(ctx, list)
+
+	switch name {
+	case check.BuiltinLength, check.BuiltinCap:
+		if typ.IsArray() {
+			return csyntax.NewIntegerLiteral(int64(typ.ArraySize))
+		}
+
+		function := RuntimeListLength
+		if name == check.BuiltinCap {
+			function = RuntimeListCap
+		}
+
+		return csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(function)), object)
+
+	case check.BuiltinAppend:
+		element := typ.Element()
+		values := ast.NewExpressionList()
+		values.Expressions = call.Arguments.Expressions[1:]
+		return csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListAppend)),
+			csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, object),
+			csyntax.NewSizeofType(element),
+			csyntax.NewIntegerLiteral(int64(values.Length())),
+			c.outputElements(ctx, element, values),
+		)
+
+	default:
+		index := call.Arguments.Expressions[1].Expression
+		args := append([]csyntax.Expression{object, c.OutputExpression(ctx, index)}, sourcePosition(index)...)
+		return csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListRemove)), args...)
+	}
+}
@@ -4,7 +4,14 @@ import (
 	"testing"
 
 	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
+
+	"github.com/flily/magi-c/coder/check"
+	"github.com/flily/magi-c/coder/csyntax"
+	"github.com/flily/magi-c/context"
 )
 
 const (
@@ -14,7 +21,12 @@ const (
 func testOutputCode(t *testing.T, code string, expected string) {
 	t.Helper()
 
-	coder := NewCoder(".", ".")
+	testOutputCodeBy(t, NewCoder(".", "."), code, expected)
+}
+
+func testOutputCodeBy(t *testing.T, coder *Coder, code string, expected string) {
+	t.Helper()
+
 	indexName, err := coder.ParseFileContent(testFilename, []byte(code))
 	if err != nil {
 		t.Fatalf("ParseFileContent failed:\n%s", err)
@@ -107,6 +119,37 @@ func TestOutputFilename(t *testing.T) {
 	}
 }
 
+func TestCoderCheckRules(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    c := 1",
+		"}",
+	}, "\n")
+
+	coder := NewCoder(".", ".")
+	if _, err := coder.ParseFileContent(testFilename, []byte(code)); err != nil {
+		t.Fatalf("ParseFileContent failed:\n%s", err)
+	}
+
+	if err := coder.Check(testFilename); err != nil {
+		t.Fatalf("Check failed:\n%s", err)
+	}
+
+	if count := coder.Diagnostics(testFilename).CountRule(check.RuleUnusedVariable); count != 1 {
+		t.Fatalf("expected 1 warning of rule %s, got %d", check.RuleUnusedVariable, count)
+	}
+
+	coder.Checks.WarningAsError = true
+	if err := coder.Check(testFilename); err == nil {
+		t.Fatalf("Check expected to fail with warnings raised to errors")
+	}
+
+	coder.Checks.Rules[check.RuleUnusedVariable] = context.Ignored
+	if err := coder.Check(testFilename); err != nil {
+		t.Fatalf("Check failed:\n%s", err)
+	}
+}
+
 func TestCoderBasicLeastVoidMain(t *testing.T) {
 	souce := strings.Join([]string{
 		`fun main() {`,
@@ -332,14 +375,16 @@ func TestCoderOnSimpleFunctionWithAddAndSub(t *testing.T) {
 	}, "\n")
 
 	expected := strings.Join([]string{
+		`#include <stddef.h>`,
+		``,
 		`#line 1 "test.mc"`,
 		`int addAndSub(int* __out__0, int* __out__1, int a, int b)`,
 		`{`,
 		`#line 2 "test.mc"`,
-		`    if (NULL == __out__0) {`,
+		`    if (NULL != __out__0) {`,
 		`        *__out__0 = a + b;`,
 		`    }`,
-		`    if (NULL == __out__1) {`,
+		`    if (NULL != __out__1) {`,
 		`        *__out__1 = a - b;`,
 		`    }`,
 		`    return 0;`,
@@ -407,3 +452,1878 @@ func TestCodeSimpleHelloWorld2(t *testing.T) {
 
 	testOutputCode(t, source, expected)
 }
+
+func TestCoderOnFunctionReturnString(t *testing.T) {
+	source := strings.Join([]string{
+		`fun greeting() (*int8) {`,
+		`    return "hello, \"world\"\t\u{4e2d}\n"`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		``,
+		`#line 1 "test.mc"`,
+		`int8_t* greeting()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    return "hello, \"world\"\t\344\270\255\n";`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderWithComments(t *testing.T) {
+	source := strings.Join([]string{
+		`// entry point`,
+		`// of the program`,
+		`fun main() (int) {`,
+		`    /* nothing */`,
+		`    return 0 // done */`,
+		`    // end of body`,
+		`}`,
+		`// end of file`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`/*`,
+		` * entry point`,
+		` * of the program`,
+		` */`,
+		`#line 3 "test.mc"`,
+		`int main()`,
+		`{`,
+		`    /* nothing */`,
+		`#line 5 "test.mc"`,
+		`    return 0;`,
+		`    /* done * / */`,
+		`    /* end of body */`,
+		`}`,
+		``,
+		`/* end of file */`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnFunctionReturnExpression(t *testing.T) {
+	source := strings.Join([]string{
+		`fun calc(a int, b int) (bool) {`,
+		`    return (a + b) * -a == 3 and not (a >= b) or a & ~b != 0`,
+		`}`,
+		``,
+		`fun values() (bool, float64, *int32) {`,
+		`    return true, 2.5, null`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		`#include <stddef.h>`,
+		``,
+		`#line 1 "test.mc"`,
+		`int calc(int a, int b)`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    return ((((a + b) * (-a)) == 3) && (!(a >= b))) || ((a & (~b)) != 0);`,
+		`}`,
+		``,
+		`#line 5 "test.mc"`,
+		`int values(int* __out__0, double* __out__1, int32_t** __out__2)`,
+		`{`,
+		`#line 6 "test.mc"`,
+		`    if (NULL != __out__0) {`,
+		`        *__out__0 = 1;`,
+		`    }`,
+		`    if (NULL != __out__1) {`,
+		`        *__out__1 = 2.5;`,
+		`    }`,
+		`    if (NULL != __out__2) {`,
+		`        *__out__2 = NULL;`,
+		`    }`,
+		`    return 0;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnFunctionCall(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    divmod(7, 2)`,
+		`    return add(1, 2)`,
+		`}`,
+		``,
+		`fun add(a int, b int) (int) {`,
+		`    return a + b`,
+		`}`,
+		``,
+		`fun divmod(a int, b int) (int, int) {`,
+		`    return a / b, a % b`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stddef.h>`,
+		``,
+		`int divmod(int* __out__0, int* __out__1, int a, int b);`,
+		`int add(int a, int b);`,
+		``,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    divmod(NULL, NULL, 7, 2);`,
+		``,
+		`#line 3 "test.mc"`,
+		`    return add(1, 2);`,
+		`}`,
+		``,
+		`#line 6 "test.mc"`,
+		`int add(int a, int b)`,
+		`{`,
+		`#line 7 "test.mc"`,
+		`    return a + b;`,
+		`}`,
+		``,
+		`#line 10 "test.mc"`,
+		`int divmod(int* __out__0, int* __out__1, int a, int b)`,
+		`{`,
+		`#line 11 "test.mc"`,
+		`    if (NULL != __out__0) {`,
+		`        *__out__0 = a / b;`,
+		`    }`,
+		`    if (NULL != __out__1) {`,
+		`        *__out__1 = a % b;`,
+		`    }`,
+		`    return 0;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnVariableDeclarations(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    const a int32 = 1`,
+		`    var p *uint8 = null`,
+		`    var f = 2.5 * a`,
+		`    b := a`,
+		`    q, _ := divmod(7, 2)`,
+		`    return q`,
+		`}`,
+		``,
+		`fun divmod(a int, b int) (int, int) {`,
+		`    return a / b, a % b`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		`#include <stddef.h>`,
+		`#include <stdlib.h>`,
+		``,
+		`int divmod(int* __out__0, int* __out__1, int a, int b);`,
+		``,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    const int32_t a = 1;`,
+		``,
+		`#line 3 "test.mc"`,
+		`    uint8_t* p = NULL;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    double f = 2.5 * a;`,
+		``,
+		`#line 5 "test.mc"`,
+		`    int32_t b = a;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    int q;`,
+		`    divmod(&q, NULL, 7, 2);`,
+		``,
+		`#line 7 "test.mc"`,
+		`    free(p);`,
+		`    return q;`,
+		`}`,
+		``,
+		`#line 10 "test.mc"`,
+		`int divmod(int* __out__0, int* __out__1, int a, int b)`,
+		`{`,
+		`#line 11 "test.mc"`,
+		`    if (NULL != __out__0) {`,
+		`        *__out__0 = a / b;`,
+		`    }`,
+		`    if (NULL != __out__1) {`,
+		`        *__out__1 = a % b;`,
+		`    }`,
+		`    return 0;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnAssignments(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var a int = 1`,
+		`    var ref p *int = &a`,
+		`    var b = 2`,
+		`    a = b + 1`,
+		`    a += 2`,
+		`    *p <<= 1`,
+		`    *p = 3`,
+		`    p[1] = a`,
+		`    a, b = b, a`,
+		`    a, _ = divmod(a, b)`,
+		`    return a`,
+		`}`,
+		``,
+		`fun divmod(a int, b int) (int, int) {`,
+		`    return a / b, a % b`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stddef.h>`,
+		``,
+		`int divmod(int* __out__0, int* __out__1, int a, int b);`,
+		``,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    int a = 1;`,
+		``,
+		`#line 3 "test.mc"`,
+		`    int* p = &a;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    int b = 2;`,
+		``,
+		`#line 5 "test.mc"`,
+		`    a = b + 1;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    a += 2;`,
+		``,
+		`#line 7 "test.mc"`,
+		`    *p <<= 1;`,
+		``,
+		`#line 8 "test.mc"`,
+		`    *p = 3;`,
+		``,
+		`#line 9 "test.mc"`,
+		`    p[1] = a;`,
+		``,
+		`#line 10 "test.mc"`,
+		`    int __tmp__0 = b;`,
+		`    int __tmp__1 = a;`,
+		`    a = __tmp__0;`,
+		`    b = __tmp__1;`,
+		``,
+		`#line 11 "test.mc"`,
+		`    divmod(&a, NULL, a, b);`,
+		``,
+		`#line 12 "test.mc"`,
+		`    return a;`,
+		`}`,
+		``,
+		`#line 15 "test.mc"`,
+		`int divmod(int* __out__0, int* __out__1, int a, int b)`,
+		`{`,
+		`#line 16 "test.mc"`,
+		`    if (NULL != __out__0) {`,
+		`        *__out__0 = a / b;`,
+		`    }`,
+		`    if (NULL != __out__1) {`,
+		`        *__out__1 = a % b;`,
+		`    }`,
+		`    return 0;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnIfStatements(t *testing.T) {
+	source := strings.Join([]string{
+		`fun sign(a int) (int) {`,
+		`    if (a > 0) {`,
+		`        // positive`,
+		`        r := 1.5`,
+		`        return 1`,
+		`    } elif a == 0 {`,
+		`        return 0`,
+		`    } else {`,
+		`        r := a`,
+		`        if r < -10 {`,
+		`            r = -10`,
+		`        }`,
+		`    }`,
+		`    return -1`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#line 1 "test.mc"`,
+		`int sign(int a)`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    if (a > 0) {`,
+		`        /* positive */`,
+		`#line 4 "test.mc"`,
+		`        double r = 1.5;`,
+		``,
+		`#line 5 "test.mc"`,
+		`        return 1;`,
+		`    } else if (a == 0) {`,
+		`#line 7 "test.mc"`,
+		`        return 0;`,
+		`    } else {`,
+		`#line 9 "test.mc"`,
+		`        int r = a;`,
+		``,
+		`#line 10 "test.mc"`,
+		`        if (r < (-10)) {`,
+		`#line 11 "test.mc"`,
+		`            r = -10;`,
+		`        }`,
+		`    }`,
+		``,
+		`#line 14 "test.mc"`,
+		`    return -1;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestCoderOnLoopStatements(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    #inline c`,
+		`    int arr[3] = {1, 2, 3};`,
+		`    #end-inline c`,
+		`    var s = 0`,
+		`    foreach (x in arr) {`,
+		`        s += x`,
+		`    }`,
+		`    for (i := 0; i < 10; i++) {`,
+		`        if i == 3 {`,
+		`            break`,
+		`        }`,
+		`    }`,
+		`    for (;;) {`,
+		`        continue`,
+		`    }`,
+		`    while s > 0 {`,
+		`        s--`,
+		`    }`,
+		`    do {`,
+		`        s = s + 1`,
+		`    } while (s < 3)`,
+		`    return s`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    int arr[3] = {1, 2, 3};`,
+		``,
+		`#line 5 "test.mc"`,
+		`    int s = 0;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    for (int __tmp__0 = 0; __tmp__0 < (sizeof(arr) / sizeof(arr[0])); __tmp__0++) {`,
+		`        int x = arr[__tmp__0];`,
+		`#line 7 "test.mc"`,
+		`        s += x;`,
+		`    }`,
+		``,
+		`#line 9 "test.mc"`,
+		`    for (int i = 0; i < 10; i++) {`,
+		`#line 10 "test.mc"`,
+		`        if (i == 3) {`,
+		`#line 11 "test.mc"`,
+		`            break;`,
+		`        }`,
+		`    }`,
+		``,
+		`#line 14 "test.mc"`,
+		`    for (; ; ) {`,
+		`#line 15 "test.mc"`,
+		`        continue;`,
+		`    }`,
+		``,
+		`#line 17 "test.mc"`,
+		`    while (s > 0) {`,
+		`#line 18 "test.mc"`,
+		`        s--;`,
+		`    }`,
+		``,
+		`#line 20 "test.mc"`,
+		`    do {`,
+		`#line 21 "test.mc"`,
+		`        s = s + 1;`,
+		`    } while (s < 3);`,
+		``,
+		`#line 23 "test.mc"`,
+		`    return s;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputStructDeclarations(t *testing.T) {
+	source := strings.Join([]string{
+		`type Line struct {`,
+		`    from Point`,
+		`    to   Point`,
+		`}`,
+		``,
+		`type Ref *Line`,
+		``,
+		`struct Point { x int32; y int32 }`,
+		``,
+		`fun main() {`,
+		`    var l Line`,
+		`    ref p := &l.to`,
+		`    p.x = l.from.y`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		``,
+		`typedef struct Line Line;`,
+		`typedef struct Point Point;`,
+		``,
+		`#line 8 "test.mc"`,
+		`struct Point {`,
+		`    int32_t x;`,
+		`    int32_t y;`,
+		`};`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Line {`,
+		`    Point from;`,
+		`    Point to;`,
+		`};`,
+		``,
+		`#line 6 "test.mc"`,
+		`typedef Line* Ref;`,
+		``,
+		`#line 10 "test.mc"`,
+		`void main()`,
+		`{`,
+		`#line 11 "test.mc"`,
+		`    Line l;`,
+		``,
+		`#line 12 "test.mc"`,
+		`    Point* p = &l.to;`,
+		``,
+		`#line 13 "test.mc"`,
+		`    p->x = l.from.y;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputMethods(t *testing.T) {
+	source := strings.Join([]string{
+		`struct Point { x int; y int }`,
+		``,
+		`fun (p Point) Sum() (int) {`,
+		`    return p.x + p.y`,
+		`}`,
+		``,
+		`fun Point.Zero() (int) {`,
+		`    return 0`,
+		`}`,
+		``,
+		`fun main() {`,
+		`    var a Point`,
+		`    ref q := &a`,
+		`    a.x = Point.Zero()`,
+		`    a.y = a.Sum() + q.Sum()`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`typedef struct Point Point;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Point {`,
+		`    int x;`,
+		`    int y;`,
+		`};`,
+		``,
+		`#line 3 "test.mc"`,
+		`int Point_Sum(Point* p)`,
+		`{`,
+		`#line 4 "test.mc"`,
+		`    return p->x + p->y;`,
+		`}`,
+		``,
+		`#line 7 "test.mc"`,
+		`int Point_Zero()`,
+		`{`,
+		`#line 8 "test.mc"`,
+		`    return 0;`,
+		`}`,
+		``,
+		`#line 11 "test.mc"`,
+		`void main()`,
+		`{`,
+		`#line 12 "test.mc"`,
+		`    Point a;`,
+		``,
+		`#line 13 "test.mc"`,
+		`    Point* q = &a;`,
+		``,
+		`#line 14 "test.mc"`,
+		`    a.x = Point_Zero();`,
+		``,
+		`#line 15 "test.mc"`,
+		`    a.y = Point_Sum(&a) + Point_Sum(q);`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputGlobalVariables(t *testing.T) {
+	source := strings.Join([]string{
+		`fun bump() with(count int) {`,
+		`    global var total int`,
+		`    count++`,
+		`    total += count`,
+		`}`,
+		``,
+		`global var count int = 0`,
+		`global var total int = 10`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#line 7 "test.mc"`,
+		`int count = 0;`,
+		`#line 8 "test.mc"`,
+		`int total = 10;`,
+		``,
+		`#line 1 "test.mc"`,
+		`void bump()`,
+		`{`,
+		`#line 3 "test.mc"`,
+		`    count++;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    total += count;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputArrays(t *testing.T) {
+	source := strings.Join([]string{
+		`global var table int[4] = int[4]{1, 2}`,
+		``,
+		`fun main() with(table int[4]) (int) {`,
+		`    nums := int[]{1, 2, 3}`,
+		`    var buf int[8]`,
+		`    buf[1] = nums[2] + table[3]`,
+		`    return buf[1]`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#line 1 "test.mc"`,
+		`int table[4] = {1, 2};`,
+		``,
+		`#line 3 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 4 "test.mc"`,
+		`    int nums[3] = {1, 2, 3};`,
+		``,
+		`#line 5 "test.mc"`,
+		`    int buf[8] = {0};`,
+		``,
+		`#line 6 "test.mc"`,
+		`    buf[1] = nums[2] + table[3];`,
+		``,
+		`#line 7 "test.mc"`,
+		`    return buf[1];`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputArrayBoundsChecks(t *testing.T) {
+	source := strings.Join([]string{
+		`fun get(i int) (int) {`,
+		`    nums := int[]{1, 2, 3}`,
+		`    return nums[i] + nums[0]`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdio.h>`,
+		`#include <stdlib.h>`,
+		``,
+		`static long __magic_check_index(long index, long length, const char *file, int line)`,
+		`{`,
+		`    if (index < 0 || index >= length) {`,
+		`        fprintf(stderr, "%s:%d: index out of range [%ld] with length %ld\n", file, line, index, length);`,
+		`        abort();`,
+		`    }`,
+		``,
+		`    return index;`,
+		`}`,
+		``,
+		`#line 1 "test.mc"`,
+		`int get(int i)`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    int nums[3] = {1, 2, 3};`,
+		``,
+		`#line 3 "test.mc"`,
+		`    return nums[__magic_check_index(i, 3, "test.mc", 3)] + nums[0];`,
+		`}`,
+		``,
+	}, "\n")
+
+	coder := NewCoder(".", ".")
+	coder.Debug = true
+	testOutputCodeBy(t, coder, source, expected)
+}
+
+// expectRuntime returns expected output of runtime functions in names, which SHALL be in order of output.
+func expectRuntime(includes []string, names ...string) []string {
+	lines := make([]string, 0, 100)
+	for _, include := range includes {
+		lines = append(lines, "#include <"+include+">")
+	}
+
+	for _, name := range names {
+		for _, f := range runtimeFunctions {
+			if f.Name == name {
+				lines = append(lines, "")
+				lines = append(lines, strings.Split(f.Code, "\n")...)
+			}
+		}
+	}
+
+	return append(lines, "")
+}
+
+func TestOutputLists(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    l := new int[]{1, 2}`,
+		`    var m int[]`,
+		`    append(m, l[0], 3)`,
+		`    foreach (x in m) {`,
+		`        remove(l, x)`,
+		`    }`,
+		`    arr := int[4]{}`,
+		`    return len(l) + len(arr)`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stddef.h", "stdlib.h", "stdio.h", "string.h"},
+		RuntimeCheckIndex, RuntimeRealloc, RuntimeList, RuntimeListNew, RuntimeListLength, RuntimeListAt,
+		RuntimeListAppend, RuntimeListRemove, RuntimeListFree)
+	expected := strings.Join(append(runtime,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    __magic_list* l = __magic_list_new(sizeof(int), 2, 2, (int[2]){1, 2});`,
+		``,
+		`#line 3 "test.mc"`,
+		`    __magic_list* m = NULL;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    __magic_list_append(&m, sizeof(int), 2, (int[2]){*(int*)__magic_list_at(l, 0, "test.mc", 4), 3});`,
+		``,
+		`#line 5 "test.mc"`,
+		`    for (long __tmp__0 = 0; __tmp__0 < __magic_list_len(m); __tmp__0++) {`,
+		`        int x = *(int*)__magic_list_at(m, __tmp__0, "test.mc", 5);`,
+		`#line 6 "test.mc"`,
+		`        __magic_list_remove(l, x, "test.mc", 6);`,
+		`    }`,
+		``,
+		`#line 8 "test.mc"`,
+		`    int arr[4] = {0};`,
+		``,
+		`#line 9 "test.mc"`,
+		`    long __tmp__1 = __magic_list_len(l) + 4;`,
+		`    __magic_list_free(m);`,
+		`    __magic_list_free(l);`,
+		`    return __tmp__1;`,
+		`}`,
+		``,
+	), "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputPointerArithmetic(t *testing.T) {
+	source := strings.Join([]string{
+		`fun count() (int) {`,
+		`    var buf uint32[4]`,
+		`    ref p := &buf[0]`,
+		`    ref q := p +>> 4`,
+		`    ref r := q -<< 1 uint32`,
+		`    var n int = 0`,
+		`    for (c := p; c != r +>> 3 uint32; c = c +>> 1 uint32) {`,
+		`        n += 1`,
+		`    }`,
+		`    var t *uint32 = p`,
+		`    t = &buf[1]`,
+		`    ref u := t +>> 2 uint16`,
+		`    return n + *u`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join(append(expectRuntime([]string{"stdint.h"}),
+		`#line 1 "test.mc"`,
+		`int count()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    uint32_t buf[4] = {0};`,
+		``,
+		`#line 3 "test.mc"`,
+		`    uint32_t* p = &buf[0];`,
+		``,
+		`#line 4 "test.mc"`,
+		`    uint32_t* q = (uint32_t*)((uint8_t*)p + 4);`,
+		``,
+		`#line 5 "test.mc"`,
+		`    uint32_t* r = q - 1;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    int n = 0;`,
+		``,
+		`#line 7 "test.mc"`,
+		`    for (uint32_t* c = p; c != (r + 3); c = c + 1) {`,
+		`#line 8 "test.mc"`,
+		`        n += 1;`,
+		`    }`,
+		``,
+		`#line 10 "test.mc"`,
+		`    uint32_t* t = p;`,
+		``,
+		`#line 11 "test.mc"`,
+		`    t = &buf[1];`,
+		``,
+		`#line 12 "test.mc"`,
+		`    uint32_t* u = (uint32_t*)((uint16_t*)t + 2);`,
+		``,
+		`#line 13 "test.mc"`,
+		`    return n + (*u);`,
+		`}`,
+		``,
+	), "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputPointerArithmeticChecks(t *testing.T) {
+	source := strings.Join([]string{
+		`fun count() (int) {`,
+		`    var buf uint32[4]`,
+		`    ref p := &buf[0]`,
+		`    ref q := p +>> 4`,
+		`    ref r := q -<< 1 uint32`,
+		`    var n int = 0`,
+		`    for (c := p; c != r +>> 3 uint32; c = c +>> 1 uint32) {`,
+		`        n += 1`,
+		`    }`,
+		`    var t *uint32 = p`,
+		`    t = &buf[1]`,
+		`    ref u := t +>> 2 uint16`,
+		`    return n + *u`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stdint.h", "stdio.h", "stdlib.h"}, RuntimeCheckPointer)
+	expected := strings.Join(append(runtime,
+		`#line 1 "test.mc"`,
+		`int count()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    uint32_t buf[4] = {0};`,
+		``,
+		`#line 3 "test.mc"`,
+		`    uint32_t* p = &buf[0];`,
+		``,
+		`#line 4 "test.mc"`,
+		`    uint32_t* q = (uint32_t*)__magic_check_pointer((uint8_t*)p + 4, buf, sizeof(buf), "test.mc", 4);`,
+		``,
+		`#line 5 "test.mc"`,
+		`    uint32_t* r = (uint32_t*)__magic_check_pointer(q - 1, buf, sizeof(buf), "test.mc", 5);`,
+		``,
+		`#line 6 "test.mc"`,
+		`    int n = 0;`,
+		``,
+		`#line 7 "test.mc"`,
+		`    for (uint32_t* c = p; c != (uint32_t*)__magic_check_pointer(r + 3, buf, sizeof(buf), "test.mc", 7); c = (uint32_t*)__magic_check_pointer(c + 1, buf, sizeof(buf), "test.mc", 7)) {`,
+		`#line 8 "test.mc"`,
+		`        n += 1;`,
+		`    }`,
+		``,
+		`#line 10 "test.mc"`,
+		`    uint32_t* t = p;`,
+		``,
+		`#line 11 "test.mc"`,
+		`    t = &buf[1];`,
+		``,
+		`#line 12 "test.mc"`,
+		`    uint32_t* u = (uint32_t*)((uint16_t*)t + 2);`,
+		``,
+		`#line 13 "test.mc"`,
+		`    return n + (*u);`,
+		`}`,
+		``,
+	), "\n")
+
+	coder := NewCoder(".", ".")
+	coder.Debug = true
+	testOutputCodeBy(t, coder, source, expected)
+}
+
+func TestOutputNewAndDelete(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int`,
+		`    y int`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    p := new int(5)`,
+		`    pt := new Point{x: 3, y: *p}`,
+		`    empty := new Point{}`,
+		`    ref r := pt`,
+		`    d := ref empty`,
+		`    l := new int[]{1}`,
+		`    delete p`,
+		`    delete l`,
+		`    return r.x + d.y`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stddef.h", "stdlib.h", "stdio.h", "string.h"},
+		RuntimeRealloc, RuntimeNew, RuntimeList, RuntimeListNew, RuntimeListFree)
+	expected := strings.Join(append(runtime,
+		`typedef struct Point Point;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Point {`,
+		`    int x;`,
+		`    int y;`,
+		`};`,
+		``,
+		`#line 6 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 7 "test.mc"`,
+		`    int* p = (int*)__magic_new(sizeof(int), &(int){5});`,
+		``,
+		`#line 8 "test.mc"`,
+		`    Point* pt = (Point*)__magic_new(sizeof(Point), &(Point){.x = 3, .y = *p});`,
+		``,
+		`#line 9 "test.mc"`,
+		`    Point* empty = (Point*)__magic_new(sizeof(Point), NULL);`,
+		``,
+		`#line 10 "test.mc"`,
+		`    Point* r = pt;`,
+		``,
+		`#line 11 "test.mc"`,
+		`    Point* d = empty;`,
+		``,
+		`#line 12 "test.mc"`,
+		`    __magic_list* l = __magic_list_new(sizeof(int), 1, 1, (int[1]){1});`,
+		``,
+		`#line 13 "test.mc"`,
+		`    free(p);`,
+		`    p = NULL;`,
+		``,
+		`#line 14 "test.mc"`,
+		`    __magic_list_free(l);`,
+		`    l = NULL;`,
+		``,
+		`#line 15 "test.mc"`,
+		`    int __tmp__0 = r->x + d->y;`,
+		`    __magic_list_free(l);`,
+		`    free(empty);`,
+		`    free(pt);`,
+		`    free(p);`,
+		`    return __tmp__0;`,
+		`}`,
+		``,
+	), "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputOwnership(t *testing.T) {
+	source := strings.Join([]string{
+		`type Node struct {`,
+		`    v int`,
+		`    next *Node`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    head := new Node{v: 1}`,
+		`    second := new Node{v: 2}`,
+		`    head.next = second`,
+		`    for (i := 0; i < 4; i++) {`,
+		`        p := new int(i)`,
+		`        if (i == 2) {`,
+		`            break`,
+		`        }`,
+		`    }`,
+		`    head = new Node{v: 3, next: head}`,
+		`    ref r := head.next`,
+		`    return r.v + head.v`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stddef.h", "stdlib.h", "stdio.h", "string.h"}, RuntimeRealloc, RuntimeNew)
+	expected := strings.Join(append(runtime,
+		`typedef struct Node Node;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Node {`,
+		`    int v;`,
+		`    Node* next;`,
+		`};`,
+		``,
+		`static void __magic_free_Node(Node* value);`,
+		``,
+		`static void __magic_free_Node(Node* value)`,
+		`{`,
+		`    if (value == NULL) {`,
+		`        return;`,
+		`    }`,
+		``,
+		`    __magic_free_Node(value->next);`,
+		`    free(value);`,
+		`}`,
+		``,
+		`#line 6 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 7 "test.mc"`,
+		`    Node* head = (Node*)__magic_new(sizeof(Node), &(Node){.v = 1});`,
+		``,
+		`#line 8 "test.mc"`,
+		`    Node* second = (Node*)__magic_new(sizeof(Node), &(Node){.v = 2});`,
+		``,
+		`#line 9 "test.mc"`,
+		`    Node* __tmp__0 = second;`,
+		`    second = NULL;`,
+		`    __magic_free_Node(head->next);`,
+		`    head->next = __tmp__0;`,
+		``,
+		`#line 10 "test.mc"`,
+		`    for (int i = 0; i < 4; i++) {`,
+		`#line 11 "test.mc"`,
+		`        int* p = (int*)__magic_new(sizeof(int), &(int){i});`,
+		``,
+		`#line 12 "test.mc"`,
+		`        if (i == 2) {`,
+		`#line 13 "test.mc"`,
+		`            free(p);`,
+		`            break;`,
+		`        }`,
+		``,
+		`        free(p);`,
+		`    }`,
+		``,
+		`#line 16 "test.mc"`,
+		`    Node* __tmp__1 = (Node*)__magic_new(sizeof(Node), &(Node){.v = 3, .next = head});`,
+		`    head = NULL;`,
+		`    head = __tmp__1;`,
+		``,
+		`#line 17 "test.mc"`,
+		`    Node* r = head->next;`,
+		``,
+		`#line 18 "test.mc"`,
+		`    int __tmp__2 = r->v + head->v;`,
+		`    __magic_free_Node(second);`,
+		`    __magic_free_Node(head);`,
+		`    return __tmp__2;`,
+		`}`,
+		``,
+	), "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+// testRunCodeBy translates code, compiles output by C compiler and runs it, which SHALL exit with code expected.
+// Output in C89 is compiled in pedantic mode. It is skipped if no C compiler is found.
+func testRunCodeBy(t *testing.T, coder *Coder, code string, expected int) {
+	t.Helper()
+
+	testRunCodeWith(t, coder, nil, code, expected)
+}
+
+// testRunCodeWith runs code like testRunCodeBy, and output is compiled with extra flags. Errors reported by
+// sanitizers fail the test.
+func testRunCodeWith(t *testing.T, coder *Coder, extra []string, code string, expected int) {
+	t.Helper()
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler found")
+	}
+
+	if _, err := coder.ParseFileContent(testFilename, []byte(code)); err != nil {
+		t.Fatalf("ParseFileContent failed:\n%s", err)
+	}
+
+	if err := coder.Check(testFilename); err != nil {
+		t.Fatalf("Check failed:\n%s", err)
+	}
+
+	dir := t.TempDir()
+	source, binary := filepath.Join(dir, "test.c"), filepath.Join(dir, "test")
+	if err := coder.OutputToFile(testFilename, source); err != nil {
+		t.Fatalf("OutputToFile failed:\n%s", err)
+	}
+
+	flags := []string{"-std=c99"}
+	if coder.Standard == csyntax.C89 {
+		flags = []string{"-std=c89", "-pedantic-errors"}
+	}
+
+	args := append(append(flags, extra...), "-Wall", "-Werror", "-Wno-unused-variable", "-o", binary, source)
+	if output, err := exec.Command(cc, args...).CombinedOutput(); err != nil {
+		content, _ := os.ReadFile(source)
+		t.Fatalf("compile failed:\n%s\n%s", output, content)
+	}
+
+	exitCode := 0
+	output, err := exec.Command(binary).CombinedOutput()
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatalf("run failed: %s", err)
+		}
+
+		exitCode = exitErr.ExitCode()
+	}
+
+	if strings.Contains(string(output), "Sanitizer") {
+		content, _ := os.ReadFile(source)
+		t.Fatalf("sanitizer error:\n%s\n%s", output, content)
+	}
+
+	if exitCode != expected {
+		t.Fatalf("exit code mismatch, expected %d, got %d", expected, exitCode)
+	}
+}
+
+// testRunCodeSanitized runs code compiled with address sanitizer, which reports invalid accesses and leaks. It is
+// skipped if the sanitizer is not supported.
+func testRunCodeSanitized(t *testing.T, code string, expected int) {
+	t.Helper()
+
+	flags := []string{"-fsanitize=address", "-fno-omit-frame-pointer", "-g"}
+	probe := exec.Command("cc", append(flags, "-x", "c", "-o", filepath.Join(t.TempDir(), "probe"), "-")...)
+	probe.Stdin = strings.NewReader("int main(void) { return 0; }\n")
+	if err := probe.Run(); err != nil {
+		t.Skip("address sanitizer not supported")
+	}
+
+	testRunCodeWith(t, NewCoder(".", "."), flags, code, expected)
+}
+
+func testRunCode(t *testing.T, code string, expected int) {
+	t.Helper()
+
+	testRunCodeBy(t, NewCoder(".", "."), code, expected)
+}
+
+func TestRunOwnershipCallResult(t *testing.T) {
+	source := strings.Join([]string{
+		`fun pick(p *int32) (*int32) {`,
+		`    return p`,
+		`}`,
+		``,
+		`fun make(v int32) (*int32) {`,
+		`    p := new int32(v)`,
+		`    return p`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var x int32 = 3`,
+		`    var ref q *int32 = pick(&x)`,
+		`    r := make(4)`,
+		`    return *q + *r`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 7)
+}
+
+func TestRunOwnershipStructFieldsUninitialized(t *testing.T) {
+	source := strings.Join([]string{
+		`type Box struct {`,
+		`    p *int32`,
+		`    items int[]`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var a Box`,
+		`    var b Box`,
+		`    append(b.items, 4, 5)`,
+		`    b.p = new int32(3)`,
+		`    return len(b.items) + *b.p`,
+		`}`,
+	}, "\n")
+
+	testRunCodeSanitized(t, source, 5)
+}
+
+func TestRunOwnershipReassigned(t *testing.T) {
+	source := strings.Join([]string{
+		`type Box struct {`,
+		`    p *int32`,
+		`    items int[]`,
+		`}`,
+		``,
+		`fun two() (int, *int32) {`,
+		`    return 1, new int32(2)`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var b Box`,
+		`    b.p = new int32(1)`,
+		`    b.p = new int32(2)`,
+		`    b.items = new int[]{1, 2}`,
+		`    b.items = new int[]{3}`,
+		`    var n int = 0`,
+		`    b.p, n = new int32(3), 4`,
+		`    q := new int32(9)`,
+		`    var a int = 0`,
+		`    a, q = two()`,
+		`    a, b.p = two()`,
+		`    return len(b.items) + *b.p + n + a + *q`,
+		`}`,
+	}, "\n")
+
+	testRunCodeSanitized(t, source, 10)
+}
+
+func TestRunOwnershipBorrowedValues(t *testing.T) {
+	source := strings.Join([]string{
+		`fun make() (int, *int32) {`,
+		`    return 1, new int32(2)`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    ref t := "yo"`,
+		`    k, q := make()`,
+		`    return k + *q`,
+		`}`,
+	}, "\n")
+
+	testRunCodeSanitized(t, source, 3)
+}
+
+func TestRunOwnershipGlobal(t *testing.T) {
+	source := strings.Join([]string{
+		`global var gp *int32`,
+		``,
+		`fun use() with(gp *int32) (int32) {`,
+		`    var ref p *int32 = gp`,
+		`    return *p`,
+		`}`,
+		``,
+		`fun main() with(gp *int32) (int) {`,
+		`    gp = new int32(5)`,
+		`    return use() + use()`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 10)
+}
+
+func TestOutputOwnershipStructFields(t *testing.T) {
+	source := strings.Join([]string{
+		`type Node struct {`,
+		`    value *int32`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var n Node = Node{value: new int32(1)}`,
+		`    m := n`,
+		`    return *m.value`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stdint.h", "stddef.h", "stdlib.h", "stdio.h", "string.h"}, RuntimeRealloc, RuntimeNew)
+	expected := strings.Join(append(runtime,
+		`typedef struct Node Node;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Node {`,
+		`    int32_t* value;`,
+		`};`,
+		``,
+		`#line 5 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 6 "test.mc"`,
+		`    Node n = {.value = (int32_t*)__magic_new(sizeof(int32_t), &(int32_t){1})};`,
+		``,
+		`#line 7 "test.mc"`,
+		`    Node m = n;`,
+		`    n.value = NULL;`,
+		``,
+		`#line 8 "test.mc"`,
+		`    int32_t __tmp__0 = *m.value;`,
+		`    free(m.value);`,
+		`    free(n.value);`,
+		`    return __tmp__0;`,
+		`}`,
+		``,
+	), "\n")
+
+	testOutputCode(t, source, expected)
+	testRunCode(t, source, 1)
+}
+
+func TestOutputSizeof(t *testing.T) {
+	source := strings.Join([]string{
+		`type Header struct {`,
+		`    kind uint8`,
+		`    length uint32`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var buf uint8[sizeof(uint32) * 4]`,
+		`    var h Header`,
+		`    var ref p *Header = &h`,
+		`    n := sizeof(Header) + sizeof(*p)`,
+		`    return n + sizeof(buf) + sizeof(h.length)`,
+		`}`,
+	}, "\n")
+
+	expected := func(n string) string {
+		return strings.Join([]string{
+			`#include <stdint.h>`,
+			`#include <stddef.h>`,
+			``,
+			`typedef struct Header Header;`,
+			``,
+			`#line 1 "test.mc"`,
+			`struct Header {`,
+			`    uint8_t kind;`,
+			`    uint32_t length;`,
+			`};`,
+			``,
+			`#line 6 "test.mc"`,
+			`int main()`,
+			`{`,
+			`#line 7 "test.mc"`,
+			`    uint8_t buf[16] = {0};`,
+			``,
+			`#line 8 "test.mc"`,
+			`    Header h;`,
+			``,
+			`#line 9 "test.mc"`,
+			`    Header* p = &h;`,
+			``,
+			`#line 10 "test.mc"`,
+			`    size_t n = ` + n + `;`,
+			``,
+			`#line 11 "test.mc"`,
+			`    return (n + 16) + 4;`,
+			`}`,
+			``,
+		}, "\n")
+	}
+
+	testOutputCode(t, source, expected("sizeof(Header) + sizeof(*p)"))
+
+	coder := NewCoder(".", ".")
+	coder.Target, _ = check.LookupTargetProfile("x86_64")
+	testOutputCodeBy(t, coder, source, expected("8 + 8"))
+}
+
+func TestOutputArrayParameters(t *testing.T) {
+	source := strings.Join([]string{
+		`type Header struct {`,
+		`    kind uint8`,
+		`    length uint32`,
+		`}`,
+		``,
+		`fun total(a int32[4], h Header[2]) (int32) {`,
+		`    var s int32 = 0`,
+		`    foreach (v in a) {`,
+		`        s += v`,
+		`    }`,
+		`    return s + sizeof(a) + sizeof(h)`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    a := int32[]{1, 2, 3, 4}`,
+		`    var h Header[2]`,
+		`    return total(a, h)`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		``,
+		`typedef struct Header Header;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Header {`,
+		`    uint8_t kind;`,
+		`    uint32_t length;`,
+		`};`,
+		``,
+		`#line 6 "test.mc"`,
+		`int32_t total(int32_t* a, Header* h)`,
+		`{`,
+		`#line 7 "test.mc"`,
+		`    int32_t s = 0;`,
+		``,
+		`#line 8 "test.mc"`,
+		`    for (int __tmp__0 = 0; __tmp__0 < 4; __tmp__0++) {`,
+		`        int32_t v = a[__tmp__0];`,
+		`#line 9 "test.mc"`,
+		`        s += v;`,
+		`    }`,
+		``,
+		`#line 11 "test.mc"`,
+		`    return (s + 16) + sizeof(Header[2]);`,
+		`}`,
+		``,
+		`#line 14 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 15 "test.mc"`,
+		`    int32_t a[4] = {1, 2, 3, 4};`,
+		``,
+		`#line 16 "test.mc"`,
+		`    Header h[2] = {0};`,
+		``,
+		`#line 17 "test.mc"`,
+		`    return total(a, h);`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+	testRunCode(t, source, 42)
+}
+
+func TestOutputStructLiterals(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int`,
+		`    y int`,
+		`}`,
+		``,
+		`global var origin Point = Point{y: 3}`,
+		``,
+		`fun main() (int) {`,
+		`    p := Point{x: 1, y: 2}`,
+		`    var q Point = Point{}`,
+		`    q = Point{x: p.y}`,
+		`    return q.x`,
+		`}`,
+	}, "\n")
+
+	expected := func(origin string, body ...string) string {
+		lines := []string{
+			`typedef struct Point Point;`,
+			``,
+			`#line 1 "test.mc"`,
+			`struct Point {`,
+			`    int x;`,
+			`    int y;`,
+			`};`,
+			``,
+			`#line 6 "test.mc"`,
+			`Point origin = ` + origin + `;`,
+			``,
+			`#line 8 "test.mc"`,
+			`int main()`,
+			`{`,
+		}
+
+		lines = append(lines, body...)
+		return strings.Join(append(lines,
+			``,
+			`#line 12 "test.mc"`,
+			`    return q.x;`,
+			`}`,
+			``,
+		), "\n")
+	}
+
+	testOutputCode(t, source, expected(`{.y = 3}`,
+		`#line 9 "test.mc"`,
+		`    Point p = {.x = 1, .y = 2};`,
+		``,
+		`#line 10 "test.mc"`,
+		`    Point q = {0};`,
+		``,
+		`#line 11 "test.mc"`,
+		`    q = (Point){.x = p.y};`,
+	))
+
+	coder := NewCoder(".", ".")
+	coder.Standard = csyntax.C89
+	testOutputCodeBy(t, coder, source, expected(`{0, 3}`,
+		`    Point __tmp__0 = {0};`,
+		``,
+		`#line 9 "test.mc"`,
+		`    Point p = {1, 2};`,
+		``,
+		`#line 10 "test.mc"`,
+		`    Point q = {0};`,
+		``,
+		`#line 11 "test.mc"`,
+		`    q = (__tmp__0.x = p.y, __tmp__0);`,
+	))
+}
+
+func TestRunStructLiteralsInConditions(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int32`,
+		`    y int32`,
+		`}`,
+		``,
+		`fun getx(p Point) (int32) {`,
+		`    return p.x`,
+		`}`,
+		``,
+		`fun count(n *int32) (int32) {`,
+		`    *n += 1`,
+		`    return *n`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var i int32 = 0`,
+		`    var n int32 = 0`,
+		`    while getx(Point{x: i}) < 3 {`,
+		`        i += 1`,
+		`    }`,
+		`    if i > 5 and getx(Point{x: count(&n)}) == 1 {`,
+		`        i = 0`,
+		`    }`,
+		`    return i * 10 + n`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 30)
+
+	coder := NewCoder(".", ".")
+	coder.Standard = csyntax.C89
+	testRunCodeBy(t, coder, source, 30)
+}
+
+func TestRunNullLiteral(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var ref p *int32 = null`,
+		`    if p == null {`,
+		`        return 3`,
+		`    }`,
+		`    return 0`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 3)
+}
+
+func TestRunMultipleReturnValues(t *testing.T) {
+	source := strings.Join([]string{
+		`fun divmod(a int, b int) (int, int) {`,
+		`    return a / b, a % b`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    q, r := divmod(17, 5)`,
+		`    var s int`,
+		`    s, _ = divmod(9, 2)`,
+		`    return q * 10 + r + s`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 36)
+}
+
+func TestRunArrayAddress(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var buf uint8[4] = uint8[4]{1, 2, 3, 4}`,
+		`    var ref p *uint8 = &buf`,
+		`    ref q := &buf`,
+		`    return *p + *(q +>> 3)`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 5)
+}
+
+func TestRunMethodOfCallResult(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int32`,
+		`    y int32`,
+		`}`,
+		``,
+		`fun (p Point) Sum() (int32) {`,
+		`    return p.x + p.y`,
+		`}`,
+		``,
+		`fun origin(x int32) (Point) {`,
+		`    return Point{x: x, y: 2}`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var s int32 = origin(3).Sum()`,
+		`    return s + (origin(1)).Sum() + Point{x: 4, y: 5}.Sum()`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 17)
+
+	coder := NewCoder(".", ".")
+	coder.Standard = csyntax.C89
+	testRunCodeBy(t, coder, source, 17)
+}
+
+func TestOutputC89Declarations(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var x int32 = 2`,
+		`    x += 1`,
+		`    b := int32[]{x, 1}`,
+		`    for (i := 0; i < 2; i++) {`,
+		`        x += b[i]`,
+		`    }`,
+		`    p := new int32(x)`,
+		`    return *p`,
+		`}`,
+	}, "\n")
+
+	runtime := expectRuntime([]string{"stdint.h", "stddef.h", "stdlib.h", "stdio.h", "string.h"}, RuntimeRealloc, RuntimeNew)
+	expected := strings.Join(append(runtime,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`    int32_t __tmp__0;`,
+		``,
+		`#line 2 "test.mc"`,
+		`    int32_t x = 2;`,
+		``,
+		`#line 3 "test.mc"`,
+		`    x += 1;`,
+		``,
+		`    {`,
+		`#line 4 "test.mc"`,
+		`        int32_t b[2] = {0};`,
+		`        b[0] = x;`,
+		`        b[1] = 1;`,
+		``,
+		`#line 5 "test.mc"`,
+		`        {`,
+		`            int i = 0;`,
+		`            for (; i < 2; i++) {`,
+		`#line 6 "test.mc"`,
+		`                x += b[i];`,
+		`            }`,
+		`        }`,
+		``,
+		`        {`,
+		`#line 8 "test.mc"`,
+		`            int32_t* p = (int32_t*)__magic_new(sizeof(int32_t), (__tmp__0 = x, &__tmp__0));`,
+		``,
+		`#line 9 "test.mc"`,
+		`            int32_t __tmp__1 = *p;`,
+		`            free(p);`,
+		`            return __tmp__1;`,
+		`        }`,
+		`    }`,
+		`}`,
+		``,
+	), "\n")
+
+	coder := NewCoder(".", ".")
+	coder.Standard = csyntax.C89
+	testOutputCodeBy(t, coder, source, expected)
+	testRunCodeBy(t, coder, source, 7)
+}
+
+func TestRunC89(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int32`,
+		`    y int32`,
+		`}`,
+		``,
+		`fun (p *Point) Sum() (int32) {`,
+		`    return p.x + p.y`,
+		`}`,
+		``,
+		`fun sum(a int32[3]) (int32) {`,
+		`    var s int32 = 0`,
+		`    for (i := 0; i < 3; i++) {`,
+		`        s += a[i]`,
+		`    }`,
+		`    return s`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var x int32 = 2`,
+		`    b := int32[]{x, x + 1}`,
+		`    var c int32[4] = int32[4]{x}`,
+		`    l := new int32[]{x, 5}`,
+		`    append(l, x)`,
+		`    t := sum(int32[3]{x}) + sum(int32[]{1, x, 3})`,
+		`    foreach (v in l) {`,
+		`        t += v`,
+		`    }`,
+		`    n := Point{x: 3, y: x}`,
+		`    p := new Point{y: 1}`,
+		`    t += n.Sum() + p.Sum()`,
+		`    return b[0] + b[1] + c[0] + c[1] + t`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 30)
+
+	coder := NewCoder(".", ".")
+	coder.Standard = csyntax.C89
+	testRunCodeBy(t, coder, source, 30)
+}
+
+func TestOutputTypeMapping(t *testing.T) {
+	source := strings.Join([]string{
+		`type Point struct {`,
+		`    x int32`,
+		`    y int32`,
+		`}`,
+		``,
+		`fun scale(p *Point, k float64, flags uint8) (int64) {`,
+		`    return p.x * k`,
+		`}`,
+		``,
+		`fun split(v uint64) (uint32, *Point) {`,
+		`    return v >> 32, null`,
+		`}`,
+		``,
+		`fun sum(values int16[4], ok bool) (float32) {`,
+		`    return values[0]`,
+		`}`,
+		``,
+		`fun main() (int) {`,
+		`    var pt Point`,
+		`    hi, p := split(7)`,
+		`    s := scale(&pt, 1.5, 2)`,
+		`    return hi`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		`#include <stddef.h>`,
+		`#include <stdlib.h>`,
+		``,
+		`typedef struct Point Point;`,
+		``,
+		`#line 1 "test.mc"`,
+		`struct Point {`,
+		`    int32_t x;`,
+		`    int32_t y;`,
+		`};`,
+		``,
+		`#line 6 "test.mc"`,
+		`int64_t scale(Point* p, double k, uint8_t flags)`,
+		`{`,
+		`#line 7 "test.mc"`,
+		`    return p->x * k;`,
+		`}`,
+		``,
+		`#line 10 "test.mc"`,
+		`int split(uint32_t* __out__0, Point** __out__1, uint64_t v)`,
+		`{`,
+		`#line 11 "test.mc"`,
+		`    if (NULL != __out__0) {`,
+		`        *__out__0 = v >> 32;`,
+		`    }`,
+		`    if (NULL != __out__1) {`,
+		`        *__out__1 = NULL;`,
+		`    }`,
+		`    return 0;`,
+		`}`,
+		``,
+		`#line 14 "test.mc"`,
+		`float sum(int16_t* values, int ok)`,
+		`{`,
+		`#line 15 "test.mc"`,
+		`    return values[0];`,
+		`}`,
+		``,
+		`#line 18 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 19 "test.mc"`,
+		`    Point pt;`,
+		``,
+		`#line 20 "test.mc"`,
+		`    uint32_t hi;`,
+		`    Point* p;`,
+		`    split(&hi, &p, 7);`,
+		``,
+		`#line 21 "test.mc"`,
+		`    int64_t s = scale(&pt, 1.5, 2);`,
+		``,
+		`#line 22 "test.mc"`,
+		`    free(p);`,
+		`    return hi;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputArithmeticConversions(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var a uint8 = 1`,
+		`    var b int64 = 2`,
+		`    var f float32 = 3`,
+		`    var g float64 = 4`,
+		`    s := a + b`,
+		`    m := f * g`,
+		`    k := 2 * a`,
+		`    return s + m + k`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		``,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    uint8_t a = 1;`,
+		``,
+		`#line 3 "test.mc"`,
+		`    int64_t b = 2;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    float f = 3;`,
+		``,
+		`#line 5 "test.mc"`,
+		`    double g = 4;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    int64_t s = a + b;`,
+		``,
+		`#line 7 "test.mc"`,
+		`    double m = f * g;`,
+		``,
+		`#line 8 "test.mc"`,
+		`    uint8_t k = 2 * a;`,
+		``,
+		`#line 9 "test.mc"`,
+		`    return (s + m) + k;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestOutputConversions(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var a int32 = 300`,
+		`    var d int16 = int16(a)`,
+		`    e := uint8(a + 1)`,
+		`    f := float64(a) / 7`,
+		`    return int(e) + int(d) - int(f)`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`#include <stdint.h>`,
+		``,
+		`#line 1 "test.mc"`,
+		`int main()`,
+		`{`,
+		`#line 2 "test.mc"`,
+		`    int32_t a = 300;`,
+		``,
+		`#line 3 "test.mc"`,
+		`    int16_t d = (int16_t)a;`,
+		``,
+		`#line 4 "test.mc"`,
+		`    uint8_t e = (uint8_t)(a + 1);`,
+		``,
+		`#line 5 "test.mc"`,
+		`    double f = (double)a / 7;`,
+		``,
+		`#line 6 "test.mc"`,
+		`    return ((int)e + (int)d) - (int)f;`,
+		`}`,
+		``,
+	}, "\n")
+
+	testOutputCode(t, source, expected)
+}
+
+func TestRunConversions(t *testing.T) {
+	source := strings.Join([]string{
+		`fun main() (int) {`,
+		`    var a int32 = 300`,
+		`    var d int16 = int16(a)`,
+		`    e := uint8(a + 1)`,
+		`    f := float64(a) / 7`,
+		`    return int(e) + int(d) - int(f) - 250`,
+		`}`,
+	}, "\n")
+
+	testRunCode(t, source, 53)
+}
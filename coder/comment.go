@@ -0,0 +1,44 @@
+package coder
+
+import (
+	"strings"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+var commentEscaper = strings.NewReplacer("/*", "/ *", "*/", "* /")
+
+// OutputComments converts comments to C comments, line comments in consecutive lines are merged into one.
+func OutputComments(comments []*ast.Comment) []*csyntax.Comment {
+	result := make([]*csyntax.Comment, 0, len(comments))
+
+	var lines []string
+	lastLine := -1
+	flush := func() {
+		if len(lines) > 0 {
+			result = append(result, csyntax.NewComment(lines...))
+			lines = nil
+		}
+	}
+
+	for _, comment := range comments {
+		_, line, _ := comment.Context().Position()
+		if comment.Block || line != lastLine+1 {
+			flush()
+		}
+
+		for _, text := range comment.Lines {
+			lines = append(lines, commentEscaper.Replace(text))
+		}
+
+		if comment.Block {
+			flush()
+		}
+
+		lastLine = line
+	}
+
+	flush()
+	return result
+}
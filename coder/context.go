@@ -1,7 +1,11 @@
 package coder
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/check"
 	"github.com/flily/magi-c/coder/csyntax"
 )
 
@@ -11,6 +15,20 @@ type VariableInfo struct {
 	CodeName   string
 	CodeType   csyntax.Type
 	Assigned   string
+
+	// Allocation is the variable a pointer points into, which is known if the pointer is derived from its address.
+	Allocation *VariableInfo
+
+	// Owner tells whether the variable owns heap data, which is freed when the variable goes out of scope.
+	Owner bool
+
+	// Fields tells whether the variable is a structure value owning pointers and lists in its fields, which are freed
+	// when the variable goes out of scope.
+	Fields bool
+
+	// Parameter tells whether the variable is an argument of function. Arrays in arguments are passed by pointers,
+	// and CodeType keeps their declared length.
+	Parameter bool
 }
 
 type VariableMap struct {
@@ -46,6 +64,9 @@ func (m *VariableMap) Get(name string) (*VariableInfo, bool) {
 type Frame struct {
 	Variables *VariableMap
 	Next      *Frame
+
+	// Loop tells whether the frame is body of a loop, which is left by 'break' and 'continue'.
+	Loop bool
 }
 
 func NewFrameOn(next *Frame) *Frame {
@@ -78,11 +99,47 @@ func (f *Frame) AddName(nameInSource string, nameInCode string) bool {
 	return true
 }
 
+type FunctionInfo struct {
+	Name        string
+	CodeName    string
+	Declaration *ast.FunctionDeclaration
+	ReturnCount int
+	Defined     bool
+	Static      bool
+}
+
 type Context struct {
+	Module        string
+	Imports       []string
 	Global        *Frame
 	FunctionIn    *VariableMap
 	FunctionOut   *VariableMap
 	FunctionFrame *Frame
+	TempCount     int
+
+	Functions        map[string]*FunctionInfo
+	ForwardFunctions []*FunctionInfo
+
+	Types map[string]*ast.TypeDeclaration
+
+	// Runtime records runtime functions used in output.
+	Runtime map[string]bool
+
+	// Includes records standard headers required by types used in output.
+	Includes []string
+
+	// Reassigned records variables reassigned in current function, whose allocations are not tracked.
+	Reassigned map[string]bool
+
+	// Destructors records structures freed with their owned fields, in order of use.
+	Destructors []string
+
+	// Temporaries records declarations of temporary variables at start of current function, like values of struct
+	// literals in C89, which are assigned where the literals are evaluated.
+	Temporaries []csyntax.Statement
+
+	// Typed records types of expressions resolved by checker, which is nil if the document is not checked.
+	Typed *check.TypeInfo
 }
 
 func NewContext() *Context {
@@ -90,11 +147,198 @@ func NewContext() *Context {
 		Global:      NewFrame(),
 		FunctionIn:  NewVariableMap(),
 		FunctionOut: NewVariableMap(),
+		Functions:   make(map[string]*FunctionInfo),
+		Types:       make(map[string]*ast.TypeDeclaration),
+		Runtime:     make(map[string]bool),
 	}
 
 	return ctx
 }
 
+// UseRuntime marks a runtime function and those it requires used, and returns its name.
+func (ctx *Context) UseRuntime(name string) string {
+	if ctx.Runtime[name] {
+		return name
+	}
+
+	ctx.Runtime[name] = true
+	for _, f := range runtimeFunctions {
+		if f.Name == name {
+			for _, required := range f.Requires {
+				ctx.UseRuntime(required)
+			}
+		}
+	}
+
+	return name
+}
+
+// UseInclude marks a standard header required, and returns name of the type declared in it.
+func (ctx *Context) UseInclude(header string, name string) string {
+	if !slices.Contains(ctx.Includes, header) {
+		ctx.Includes = append(ctx.Includes, header)
+	}
+
+	return name
+}
+
+// UseDestructor marks destructor of a structure used, and returns its name.
+func (ctx *Context) UseDestructor(typeName string) string {
+	if !slices.Contains(ctx.Destructors, typeName) {
+		ctx.Destructors = append(ctx.Destructors, typeName)
+	}
+
+	return DestructorName(typeName)
+}
+
+// DestructorName returns name of function freeing a structure with its owned fields, like '__magic_free_Node'.
+func DestructorName(typeName string) string {
+	return DefaultDestructorPrefix + typeName
+}
+
+// MethodName returns name of method in C code, which is prefixed with name of its type, like 'Point_Distance'.
+func MethodName(typeName string, name string) string {
+	return typeName + "_" + name
+}
+
+// FunctionName returns name of function or method in C code, without prefix of module.
+func FunctionName(decl *ast.FunctionDeclaration) string {
+	if typeName, ok := decl.ReceiverType(); ok {
+		return MethodName(typeName, decl.Name.Name)
+	}
+
+	return decl.Name.Name
+}
+
+// ModuleSymbolName returns name of a symbol of module in C code, which is prefixed with name of module, like
+// 'foo_bar'. Symbols of documents without module declaration are not prefixed.
+func ModuleSymbolName(module string, name string) string {
+	if len(module) == 0 {
+		return name
+	}
+
+	return module + "_" + name
+}
+
+// QualifiedName returns name of an exported symbol referenced in other modules, like 'foo.bar'.
+func QualifiedName(module string, name string) string {
+	return module + "." + name
+}
+
+func newFunctionInfo(name string, codeName string, decl *ast.FunctionDeclaration) *FunctionInfo {
+	info := &FunctionInfo{
+		Name:        name,
+		CodeName:    codeName,
+		Declaration: decl,
+	}
+
+	if decl.ReturnTypes != nil {
+		info.ReturnCount = decl.ReturnTypes.Length()
+	}
+
+	return info
+}
+
+func (c *Context) RegisterFunction(decl *ast.FunctionDeclaration) *FunctionInfo {
+	name := FunctionName(decl)
+	info := newFunctionInfo(name, name, decl)
+	if !isMainFunction(decl) {
+		// functions not exported are only visible in module
+		info.CodeName = ModuleSymbolName(c.Module, name)
+		info.Static = len(c.Module) > 0 && !decl.IsExported()
+	}
+
+	c.Functions[info.Name] = info
+	return info
+}
+
+// FunctionOf returns information of a function declared in current document.
+func (c *Context) FunctionOf(decl *ast.FunctionDeclaration) *FunctionInfo {
+	if info, found := c.Functions[FunctionName(decl)]; found && info.Declaration == decl {
+		return info
+	}
+
+	return c.RegisterFunction(decl)
+}
+
+// RegisterImportedFunction registers an exported function of imported module, which is called as 'foo.bar()'.
+// Imported functions are declared by prototypes of module, and are never declared forward.
+func (c *Context) RegisterImportedFunction(module string, decl *ast.FunctionDeclaration) *FunctionInfo {
+	name := FunctionName(decl)
+	info := newFunctionInfo(QualifiedName(module, name), ModuleSymbolName(module, name), decl)
+	info.Defined = true
+	c.Functions[info.Name] = info
+	return info
+}
+
+// IsImport checks whether a name refers to an imported module, rather than a variable.
+func (c *Context) IsImport(name string) bool {
+	if _, found := c.Find(name); found {
+		return false
+	}
+
+	return slices.Contains(c.Imports, name)
+}
+
+func (c *Context) RegisterType(decl *ast.TypeDeclaration) {
+	c.Types[decl.Name.Name] = decl
+}
+
+// StructOf returns structure definition of a named type, aliases are followed.
+func (c *Context) StructOf(name string) (*ast.StructType, bool) {
+	seen := make(map[string]bool)
+	for !seen[name] {
+		seen[name] = true
+		decl, found := c.Types[name]
+		if !found {
+			return nil, false
+		}
+
+		if s, ok := decl.StructType(); ok {
+			return s, true
+		}
+
+		alias, ok := decl.Definition.(*ast.SimpleType)
+		if !ok || len(alias.PointerAsterisk) > 0 {
+			return nil, false
+		}
+
+		name = alias.Identifier.Name
+	}
+
+	return nil, false
+}
+
+// UseFunction looks up a function to call, and records it to be declared forward if it is not defined yet.
+func (c *Context) UseFunction(name string) (*FunctionInfo, bool) {
+	info, found := c.Functions[name]
+	if !found {
+		return nil, false
+	}
+
+	if !info.Defined && !slices.Contains(c.ForwardFunctions, info) {
+		c.ForwardFunctions = append(c.ForwardFunctions, info)
+	}
+
+	return info, true
+}
+
+// EnterFunction resets function scoped states before output a function.
+func (c *Context) EnterFunction(decl *ast.FunctionDeclaration) {
+	c.FunctionOf(decl).Defined = true
+
+	c.FunctionIn = NewVariableMap()
+	c.FunctionOut = NewVariableMap()
+	c.TempCount = 0
+	c.Temporaries = nil
+	c.Reassigned = reassignedVariables(decl.Statements, make(map[string]bool))
+	c.PushFrame()
+}
+
+func (c *Context) LeaveFunction() {
+	c.PopFrame()
+}
+
 func (c *Context) IsGlobalContext() bool {
 	return c.FunctionFrame == nil
 }
@@ -116,6 +360,16 @@ func (c *Context) Find(name string) (*VariableInfo, bool) {
 	return nil, false
 }
 
+// variableType returns type of variable in source, which is nil if it is inferred.
+func (c *Context) variableType(name string) (ast.Type, bool) {
+	info, found := c.Find(name)
+	if !found {
+		return nil, false
+	}
+
+	return info.SourceType, true
+}
+
 func (c *Context) RegisterVariable(nameInSource string, nameInCode string) bool {
 	if c.IsGlobalContext() {
 		if _, found := c.Global.GetName(nameInSource); found {
@@ -129,6 +383,38 @@ func (c *Context) RegisterVariable(nameInSource string, nameInCode string) bool
 	return top.AddName(nameInSource, nameInCode)
 }
 
+// DeclareVariable registers a variable with its types in current frame.
+func (c *Context) DeclareVariable(nameInSource string, sourceType ast.Type, nameInCode string, codeType *csyntax.Type) (*VariableInfo, bool) {
+	if !c.RegisterVariable(nameInSource, nameInCode) {
+		return nil, false
+	}
+
+	info, _ := c.Find(nameInSource)
+	info.SourceType = sourceType
+	if codeType != nil {
+		info.CodeType = *codeType
+	}
+
+	return info, true
+}
+
+// TempName returns a new name for temporary variable, which is unique in current function.
+func (c *Context) TempName() string {
+	name := fmt.Sprintf("%s%d", DefaultTempVarPrefix, c.TempCount)
+	c.TempCount++
+	return name
+}
+
+// DeclareTemporary declares a temporary variable at start of current function, which is initialized by value if it
+// is not nil.
+func (c *Context) DeclareTemporary(typ *csyntax.Type, value csyntax.Expression) *csyntax.Identifier {
+	name := c.TempName()
+	declarator := csyntax.NewTypedDeclarator(typ, name, value)
+	c.Temporaries = append(c.Temporaries,
+		csyntax.NewDeclarationStatement(csyntax.NewVariableDeclaration(string(typ.Base), []csyntax.VariableDeclarationItem{declarator})))
+	return csyntax.NewIdentifier(name)
+}
+
 func (c *Context) PushFrame() *Frame {
 	top := c.FunctionFrame
 	frame := NewFrameOn(top)
@@ -136,6 +422,24 @@ func (c *Context) PushFrame() *Frame {
 	return frame
 }
 
+// PushLoopFrame pushes a frame for body of loop.
+func (c *Context) PushLoopFrame() *Frame {
+	frame := c.PushFrame()
+	frame.Loop = true
+	return frame
+}
+
+// LoopFrame returns frame of the innermost loop body, or nil if not in loop.
+func (c *Context) LoopFrame() *Frame {
+	for frame := c.FunctionFrame; frame != nil; frame = frame.Next {
+		if frame.Loop {
+			return frame
+		}
+	}
+
+	return nil
+}
+
 func (c *Context) PopFrame() {
 	if c.FunctionFrame != nil {
 		c.FunctionFrame = c.FunctionFrame.Next
@@ -15,6 +15,19 @@ const (
 	EOLCRLF = "\r\n"
 )
 
+// LookupCStandard finds C standard by name, like 'c89' and 'c99'.
+func LookupCStandard(name string) (CStandard, bool) {
+	switch name {
+	case "c89", "c90", "ansi":
+		return C89, true
+
+	case "c99":
+		return C99, true
+	}
+
+	return C99, false
+}
+
 type StyleBoolean bool
 
 func (b StyleBoolean) Not() StyleBoolean {
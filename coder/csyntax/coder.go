@@ -93,9 +93,13 @@ func (s *CodeStyle) Comma() ElementCollection {
 }
 
 func (s *CodeStyle) Assign() ElementCollection {
+	return s.AssignOperator(OperatorAssign)
+}
+
+func (s *CodeStyle) AssignOperator(op Punctuator) ElementCollection {
 	result := []CodeElement{
 		s.AssignmentSpacing.Select(DelimiterSpace),
-		OperatorAssign,
+		op,
 		s.AssignmentSpacing.Select(DelimiterSpace),
 	}
 
@@ -3,6 +3,7 @@ package csyntax
 type VariableDeclarationItem struct {
 	PointerLevel int
 	Name         string
+	ArraySize    int
 	Initializer  Expression
 }
 
@@ -16,6 +17,13 @@ func NewVariableDeclarator(name string, pointerLevel int, initializer Expression
 	return d
 }
 
+// NewTypedDeclarator creates declarator of a variable in type t, whose base type is declared by declaration.
+func NewTypedDeclarator(t *Type, name string, initializer Expression) VariableDeclarationItem {
+	d := NewVariableDeclarator(name, t.PointerLevel, initializer)
+	d.ArraySize = t.ArraySize
+	return d
+}
+
 type VariableDeclaration struct {
 	Type       StringElement
 	Declarator []VariableDeclarationItem
@@ -50,6 +58,9 @@ func (v *VariableDeclaration) Write(out *StyleWriter, level Level) error {
 				DelimiterSpace,
 			).On(decl.PointerLevel <= 0 && i == 0),
 			StringElement(decl.Name),
+			NewElementCollection(
+				OperatorLeftBracket, NewIntegerStringElement(decl.ArraySize), OperatorRightBracket,
+			).On(decl.ArraySize > 0),
 			NewElementCollection(
 				out.style.Assign(), decl.Initializer,
 			).On(decl.Initializer != nil),
@@ -109,3 +109,15 @@ func TestParameterListWrite(t *testing.T) {
 	expected := "int a, float* b"
 	checkOutputOnStyle(t, testStyle1, expected, paramList)
 }
+
+func TestVariableDeclarationArray(t *testing.T) {
+	declarator := NewTypedDeclarator(NewArrayType("int", 0, 3), "a",
+		NewInitializerList(NewIntegerLiteral(1), NewIntegerLiteral(2)))
+	decl := NewVariableDeclaration("int", []VariableDeclarationItem{declarator})
+
+	checkInterfaceCodeElement(decl)
+	checkInterfaceDeclaration(decl)
+
+	expected := "int a[3] = {1, 2}"
+	checkOutputOnStyle(t, testStyle1, expected, decl)
+}
@@ -1,6 +1,7 @@
 package csyntax
 
 type FunctionDeclaration struct {
+	Static     bool
 	ReturnType *Type
 	Name       StringElement
 	Parameters *ParameterList
@@ -27,6 +28,7 @@ func (f *FunctionDeclaration) AddStatement(stmt Statement) {
 
 func (f *FunctionDeclaration) Write(out *StyleWriter, level Level) error {
 	err := out.WriteIndentLine(level,
+		NewElementCollection(KeywordStatic, DelimiterSpace).On(f.Static),
 		f.ReturnType, DelimiterSpace, f.Name, OperatorLeftParen, f.Parameters, OperatorRightParen,
 		out.style.FunctionNewLine(), OperatorLeftBrace, out.style.EOL,
 		f.Body,
@@ -34,3 +36,95 @@ func (f *FunctionDeclaration) Write(out *StyleWriter, level Level) error {
 
 	return err
 }
+
+type FunctionPrototype struct {
+	Static     bool
+	ReturnType *Type
+	Name       StringElement
+	Parameters *ParameterList
+}
+
+func NewFunctionPrototype(name string, returnType *Type, parameters *ParameterList) *FunctionPrototype {
+	f := &FunctionPrototype{
+		ReturnType: returnType,
+		Name:       StringElement(name),
+		Parameters: parameters,
+	}
+
+	return f
+}
+
+func (f *FunctionDeclaration) Prototype() *FunctionPrototype {
+	p := NewFunctionPrototype(string(f.Name), f.ReturnType, f.Parameters)
+	p.Static = f.Static
+	return p
+}
+
+func (f *FunctionPrototype) codeElement()    {}
+func (f *FunctionPrototype) definitionNode() {}
+
+func (f *FunctionPrototype) Write(out *StyleWriter, level Level) error {
+	return out.WriteIndentLine(level,
+		NewElementCollection(KeywordStatic, DelimiterSpace).On(f.Static),
+		f.ReturnType, DelimiterSpace, f.Name, OperatorLeftParen, f.Parameters, OperatorRightParen,
+		PunctuatorSemicolon)
+}
+
+type TypedefDeclaration struct {
+	Type *Type
+	Name StringElement
+}
+
+func NewTypedefDeclaration(t *Type, name string) *TypedefDeclaration {
+	d := &TypedefDeclaration{
+		Type: t,
+		Name: StringElement(name),
+	}
+
+	return d
+}
+
+// NewStructTypedef declares a structure forward, so that it can be referenced by name before defined.
+func NewStructTypedef(name string) *TypedefDeclaration {
+	return NewTypedefDeclaration(NewConcreteType(string(KeywordStruct)+" "+name), name)
+}
+
+func (d *TypedefDeclaration) codeElement()    {}
+func (d *TypedefDeclaration) definitionNode() {}
+
+func (d *TypedefDeclaration) Write(out *StyleWriter, level Level) error {
+	return out.WriteIndentLine(level,
+		KeywordTypedef, DelimiterSpace, d.Type,
+		NewElementCollection(DelimiterSpace).On(d.Type.PointerLevel == 0 || !bool(out.style.PointerSpacingAfter)),
+		d.Name, PunctuatorSemicolon)
+}
+
+type StructDefinition struct {
+	Name   StringElement
+	Fields *CodeBlock
+}
+
+func NewStructDefinition(name string, fields []Statement) *StructDefinition {
+	d := &StructDefinition{
+		Name:   StringElement(name),
+		Fields: NewCodeBlock(fields),
+	}
+
+	return d
+}
+
+// NewStructField creates a field declaration, which is written in the same form as a variable declaration.
+func NewStructField(t *Type, name string) *DeclarationStatement {
+	declarator := NewTypedDeclarator(t, name, nil)
+	return NewDeclarationStatement(NewVariableDeclaration(string(t.Base), []VariableDeclarationItem{declarator}))
+}
+
+func (d *StructDefinition) codeElement()    {}
+func (d *StructDefinition) definitionNode() {}
+
+func (d *StructDefinition) Write(out *StyleWriter, level Level) error {
+	return out.WriteIndentLine(level,
+		KeywordStruct, DelimiterSpace, d.Name, DelimiterSpace, OperatorLeftBrace, out.style.EOL,
+		d.Fields,
+		out.style.GetIndent(level), OperatorRightBrace, PunctuatorSemicolon)
+}
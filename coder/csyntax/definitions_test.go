@@ -73,3 +73,69 @@ func TestFunctionDeclarationWithSimpleReturnStyle2(t *testing.T) {
 	}, "\n")
 	checkOutputOnStyle(t, testStyle2, expected, f)
 }
+
+func TestFunctionPrototypeWrite(t *testing.T) {
+	f := NewFunctionDeclaration("add",
+		NewConcreteType("int"),
+		NewParameterList(
+			NewParameterListItem(NewConcreteType("int"), "a"),
+			NewParameterListItem(NewPointerType("int"), "b"),
+		),
+		nil,
+	)
+
+	p := f.Prototype()
+	checkInterfaceCodeElement(p)
+	checkInterfaceDefinition(p)
+
+	expected := "int add(int a, int* b);\n"
+	checkOutputOnStyle(t, testStyle1, expected, p)
+}
+
+func TestStaticFunctionWrite(t *testing.T) {
+	f := NewFunctionDeclaration("helper", NewConcreteType("void"), NewParameterList(), nil)
+	f.Static = true
+
+	expected := strings.Join([]string{
+		"static void helper()",
+		"{",
+		"}",
+		"",
+	}, "\n")
+	checkOutputOnStyle(t, testStyle1, expected, f)
+	checkOutputOnStyle(t, testStyle1, "static void helper();\n", f.Prototype())
+}
+
+func TestTypedefDeclarationWrite(t *testing.T) {
+	cases := []struct {
+		decl     *TypedefDeclaration
+		expected string
+	}{
+		{NewStructTypedef("Point"), "typedef struct Point Point;\n"},
+		{NewTypedefDeclaration(NewConcreteType("double"), "Celsius"), "typedef double Celsius;\n"},
+		{NewTypedefDeclaration(NewPointerType("Node"), "NodeRef"), "typedef Node* NodeRef;\n"},
+	}
+
+	for _, c := range cases {
+		checkInterfaceCodeElement(c.decl)
+		checkOutputOnStyle(t, testStyle1, c.expected, c.decl)
+	}
+}
+
+func TestStructDefinitionWrite(t *testing.T) {
+	def := NewStructDefinition("Node", []Statement{
+		NewStructField(NewConcreteType("int32_t"), "value"),
+		NewStructField(NewPointerType("Node"), "next"),
+	})
+
+	checkInterfaceCodeElement(def)
+
+	expected := strings.Join([]string{
+		"struct Node {",
+		"    int32_t value;",
+		"    Node* next;",
+		"};",
+		"",
+	}, "\n")
+	checkOutputOnStyle(t, testStyle1, expected, def)
+}
@@ -186,3 +186,182 @@ func TestUnaryExpressionNested(t *testing.T) {
 	checkInterfaceExpression(expr2)
 	checkOutputOnStyle(t, testStyle1, expected2, expr2)
 }
+
+func TestCallExpressionWrite(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewCallExpression(NewIdentifier("f")),
+			Expected: "f()",
+		},
+		{
+			Result: NewCallExpression(NewIdentifier("add"),
+				NewIdentifier("a"),
+				NewInfixExpression(NewIdentifier("b"), OperatorAdd, NewIntegerLiteral(1)),
+			),
+			Expected: "add(a, b + 1)",
+		},
+		{
+			Result: NewInfixExpression(
+				NewCallExpression(NewIdentifier("f"), NewIdentifier("x")),
+				OperatorMultiply,
+				NewIntegerLiteral(2),
+			),
+			Expected: "f(x) * 2",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestAssignmentExpressionToTarget(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewAssignmentExpressionTo(NewIdentifier("x"), OperatorAssignAdd, NewIntegerLiteral(1)),
+			Expected: "x += 1",
+		},
+		{
+			Result: NewAssignmentExpressionTo(
+				NewUnaryExpression(OperatorDereference, NewIdentifier("p")),
+				OperatorAssignShiftLeft,
+				NewInfixExpression(NewIdentifier("n"), OperatorAdd, NewIntegerLiteral(2)),
+			),
+			Expected: "*p <<= n + 2",
+		},
+		{
+			Result: NewAssignmentExpressionTo(
+				NewIndexExpression(NewIdentifier("a"), NewIdentifier("i")),
+				OperatorAssign,
+				NewIntegerLiteral(0),
+			),
+			Expected: "a[i] = 0",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestMemberExpressionWrite(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewMemberExpression(NewIdentifier("point"), "x"),
+			Expected: "point.x",
+		},
+		{
+			Result:   NewPointerMemberExpression(NewIdentifier("node"), "next"),
+			Expected: "node->next",
+		},
+		{
+			Result: NewMemberExpression(
+				NewUnaryExpression(OperatorDereference, NewIdentifier("p")), "x"),
+			Expected: "(*p).x",
+		},
+		{
+			Result: NewPointerMemberExpression(
+				NewPointerMemberExpression(NewIdentifier("list"), "head"), "value"),
+			Expected: "list->head->value",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestIndexExpressionWrite(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewIndexExpression(NewIdentifier("a"), NewIntegerLiteral(3)),
+			Expected: "a[3]",
+		},
+		{
+			Result: NewIndexExpression(NewIdentifier("a"),
+				NewInfixExpression(NewIdentifier("i"), OperatorAdd, NewIntegerLiteral(1))),
+			Expected: "a[i + 1]",
+		},
+		{
+			Result: NewIndexExpression(
+				NewMemberExpression(NewIdentifier("m"), "rows"), NewIdentifier("i")),
+			Expected: "m.rows[i]",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestSizeofExpressionWrite(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewSizeofExpression(NewIdentifier("a")),
+			Expected: "sizeof(a)",
+		},
+		{
+			Result: NewInfixExpression(
+				NewSizeofExpression(NewIdentifier("a")),
+				OperatorDivide,
+				NewSizeofExpression(NewIndexExpression(NewIdentifier("a"), NewIntegerLiteral(0))),
+			),
+			Expected: "sizeof(a) / sizeof(a[0])",
+		},
+		{
+			Result:   NewSizeofType(NewPointerType("int")),
+			Expected: "sizeof(int*)",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestCastExpressionWrite(t *testing.T) {
+	ExpressionTestCases{
+		{
+			Result:   NewCastExpression(NewPointerType("int"), NewIdentifier("p")),
+			Expected: "(int*)p",
+		},
+		{
+			Result: NewUnaryExpression(OperatorDereference,
+				NewCastExpression(NewPointerType("int"), NewCallExpression(NewIdentifier("at"), NewIdentifier("i")))),
+			Expected: "*(int*)at(i)",
+		},
+		{
+			Result: NewCastExpression(NewConcreteType("long"),
+				NewInfixExpression(NewIdentifier("a"), OperatorAdd, NewIntegerLiteral(1))),
+			Expected: "(long)(a + 1)",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestInitializerListWrite(t *testing.T) {
+	values := NewInitializerList(NewIntegerLiteral(1), NewIntegerLiteral(2), NewIntegerLiteral(3))
+
+	ExpressionTestCases{
+		{
+			Result:   values,
+			Expected: "{1, 2, 3}",
+		},
+		{
+			Result:   NewInitializerList(NewIntegerLiteral(0)),
+			Expected: "{0}",
+		},
+		{
+			Result:   NewCompoundLiteral(NewArrayType("int", 0, 3), values),
+			Expected: "(int[3]){1, 2, 3}",
+		},
+		{
+			Result: NewCompoundLiteral(NewConcreteType("Point"), NewInitializerList(
+				NewDesignatedInitializer("x", NewIntegerLiteral(1)),
+				NewDesignatedInitializer("y", NewIdentifier("y")),
+			)),
+			Expected: "(Point){.x = 1, .y = y}",
+		},
+	}.Run(t, testStyle1)
+}
+
+func TestCommaExpressionWrite(t *testing.T) {
+	temp := NewIdentifier("t")
+
+	ExpressionTestCases{
+		{
+			Result: NewCommaExpression(
+				NewAssignmentExpressionTo(NewMemberExpression(temp, "x"), OperatorAssign, NewIntegerLiteral(1)),
+				temp,
+			),
+			Expected: "(t.x = 1, t)",
+		},
+		{
+			Result: NewCallExpression(NewIdentifier("f"), NewCommaExpression(
+				NewAssignmentExpressionTo(NewIndexExpression(temp, NewIntegerLiteral(0)), OperatorAssign,
+					NewInfixExpression(NewIdentifier("a"), OperatorAdd, NewIntegerLiteral(1))),
+				temp,
+			)),
+			Expected: "f((t[0] = a + 1, t))",
+		},
+	}.Run(t, testStyle1)
+}
@@ -78,6 +78,8 @@ type AssignmentExpression struct {
 	ExpressionBase[*AssignmentExpression]
 	LeftIdentifier   *Identifier
 	LeftPointerLevel int
+	Target           Expression
+	Operator         Punctuator
 	RightExpression  Expression
 }
 
@@ -85,23 +87,39 @@ func NewAssignmentExpression(leftIdentifier string, leftPointerLevel int, rightE
 	e := &AssignmentExpression{
 		LeftIdentifier:   NewIdentifier(leftIdentifier),
 		LeftPointerLevel: leftPointerLevel,
+		Operator:         OperatorAssign,
 		RightExpression:  rightExpression,
 	}
 
 	return e.Init(e)
 }
 
+// NewAssignmentExpressionTo creates an assignment on any lvalue expression, with plain or compound operator.
+func NewAssignmentExpressionTo(target Expression, operator Punctuator, rightExpression Expression) *AssignmentExpression {
+	e := &AssignmentExpression{
+		Target:          target,
+		Operator:        operator,
+		RightExpression: rightExpression,
+	}
+
+	return e.Init(e)
+}
+
 func (e *AssignmentExpression) codeElement()    {}
 func (e *AssignmentExpression) expressionNode() {}
 
 func (e *AssignmentExpression) Write(out *StyleWriter, level Level) error {
+	if e.Target != nil {
+		return out.Write(level, e.Target, out.style.AssignOperator(e.Operator), e.RightExpression)
+	}
+
 	pointer := PunctuatorAsterisk.Duplicate(e.LeftPointerLevel)
 	parts := []CodeElement{
 		pointer,
 		NewElementCollection(
 			out.style.PointerSpacingBefore.Select(DelimiterSpace),
 		).On(e.LeftPointerLevel > 0),
-		e.LeftIdentifier, out.style.Assign(), e.RightExpression,
+		e.LeftIdentifier, out.style.AssignOperator(e.Operator), e.RightExpression,
 	}
 
 	return out.Write(level, parts...)
@@ -185,3 +203,290 @@ func (e *UnaryExpression) Write(out *StyleWriter, level Level) error {
 		OperatorRightParen.Select(level.ParanthesisLevel > 0),
 	)
 }
+
+type CallExpression struct {
+	ExpressionBase[*CallExpression]
+	Function  Expression
+	Arguments []Expression
+}
+
+func NewCallExpression(function Expression, arguments ...Expression) *CallExpression {
+	expr := &CallExpression{
+		Function:  function,
+		Arguments: arguments,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *CallExpression) codeElement()    {}
+func (e *CallExpression) expressionNode() {}
+
+func (e *CallExpression) Write(out *StyleWriter, level Level) error {
+	// arguments are delimited by comma, no parentheses required.
+	argLevel := NewLevel(level.IndentLevel, 0)
+	parts := make([]CodeElement, 0, 2*len(e.Arguments))
+	for i, arg := range e.Arguments {
+		parts = append(parts, out.style.Comma().On(i > 0), arg)
+	}
+
+	if err := out.Write(level, e.Function, OperatorLeftParen); err != nil {
+		return err
+	}
+
+	if err := out.Write(argLevel, parts...); err != nil {
+		return err
+	}
+
+	return out.Write(level, OperatorRightParen)
+}
+
+type MemberExpression struct {
+	ExpressionBase[*MemberExpression]
+	Object Expression
+	Member *Identifier
+	Arrow  bool
+}
+
+func NewMemberExpression(object Expression, member string) *MemberExpression {
+	expr := &MemberExpression{
+		Object: object,
+		Member: NewIdentifier(member),
+	}
+
+	return expr.Init(expr)
+}
+
+func NewPointerMemberExpression(object Expression, member string) *MemberExpression {
+	expr := NewMemberExpression(object, member)
+	expr.Arrow = true
+	return expr
+}
+
+func (e *MemberExpression) codeElement()    {}
+func (e *MemberExpression) expressionNode() {}
+
+func (e *MemberExpression) Write(out *StyleWriter, level Level) error {
+	op := OperatorDot
+	if e.Arrow {
+		op = OperatorArrow
+	}
+
+	if err := out.Write(level.NextParanthesis(), e.Object); err != nil {
+		return err
+	}
+
+	return out.Write(level, op, e.Member)
+}
+
+type IndexExpression struct {
+	ExpressionBase[*IndexExpression]
+	Object Expression
+	Index  Expression
+}
+
+func NewIndexExpression(object Expression, index Expression) *IndexExpression {
+	expr := &IndexExpression{
+		Object: object,
+		Index:  index,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *IndexExpression) codeElement()    {}
+func (e *IndexExpression) expressionNode() {}
+
+func (e *IndexExpression) Write(out *StyleWriter, level Level) error {
+	if err := out.Write(level.NextParanthesis(), e.Object); err != nil {
+		return err
+	}
+
+	if err := out.Write(level, OperatorLeftBracket); err != nil {
+		return err
+	}
+
+	if err := out.Write(NewLevel(level.IndentLevel, 0), e.Index); err != nil {
+		return err
+	}
+
+	return out.Write(level, OperatorRightBracket)
+}
+
+type SizeofExpression struct {
+	ExpressionBase[*SizeofExpression]
+	Operand Expression
+	Type    *Type
+}
+
+func NewSizeofExpression(operand Expression) *SizeofExpression {
+	expr := &SizeofExpression{
+		Operand: operand,
+	}
+
+	return expr.Init(expr)
+}
+
+// NewSizeofType creates size of a type, like 'sizeof(int)'.
+func NewSizeofType(t *Type) *SizeofExpression {
+	expr := &SizeofExpression{
+		Type: t,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *SizeofExpression) codeElement()    {}
+func (e *SizeofExpression) expressionNode() {}
+
+func (e *SizeofExpression) Write(out *StyleWriter, level Level) error {
+	if err := out.Write(level, OperatorSizeOf, OperatorLeftParen); err != nil {
+		return err
+	}
+
+	var operand CodeElement = e.Operand
+	if e.Type != nil {
+		operand = e.Type.Abstract()
+	}
+
+	if err := out.Write(NewLevel(level.IndentLevel, 0), operand); err != nil {
+		return err
+	}
+
+	return out.Write(level, OperatorRightParen)
+}
+
+// CastExpression converts operand to type, like '(int *)p'.
+type CastExpression struct {
+	ExpressionBase[*CastExpression]
+	Type    *Type
+	Operand Expression
+}
+
+func NewCastExpression(t *Type, operand Expression) *CastExpression {
+	expr := &CastExpression{
+		Type:    t,
+		Operand: operand,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *CastExpression) codeElement()    {}
+func (e *CastExpression) expressionNode() {}
+
+func (e *CastExpression) Write(out *StyleWriter, level Level) error {
+	if err := out.Write(level, OperatorLeftParen, e.Type.Abstract(), OperatorRightParen); err != nil {
+		return err
+	}
+
+	return out.Write(level.NextParanthesis(), e.Operand)
+}
+
+// InitializerList is list of values in braces, which initializes an array or a structure.
+type InitializerList struct {
+	ExpressionBase[*InitializerList]
+	Values []Expression
+}
+
+func NewInitializerList(values ...Expression) *InitializerList {
+	expr := &InitializerList{
+		Values: values,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *InitializerList) codeElement()    {}
+func (e *InitializerList) expressionNode() {}
+
+func (e *InitializerList) Write(out *StyleWriter, level Level) error {
+	parts := make([]CodeElement, 0, 2*len(e.Values)+2)
+	parts = append(parts, OperatorLeftBrace)
+	for i, value := range e.Values {
+		parts = append(parts, out.style.Comma().On(i > 0), value)
+	}
+	parts = append(parts, OperatorRightBrace)
+
+	return out.Write(NewLevel(level.IndentLevel, 0), parts...)
+}
+
+// DesignatedInitializer initializes a named member in initializer list, like '.x = 1', introduced in C99.
+type DesignatedInitializer struct {
+	ExpressionBase[*DesignatedInitializer]
+	Member *Identifier
+	Value  Expression
+}
+
+func NewDesignatedInitializer(member string, value Expression) *DesignatedInitializer {
+	expr := &DesignatedInitializer{
+		Member: NewIdentifier(member),
+		Value:  value,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *DesignatedInitializer) codeElement()    {}
+func (e *DesignatedInitializer) expressionNode() {}
+
+func (e *DesignatedInitializer) Write(out *StyleWriter, level Level) error {
+	return out.Write(level, OperatorDot, e.Member, out.style.AssignOperator(OperatorAssign), e.Value)
+}
+
+// CompoundLiteral is an unnamed object of type initialized by a list, introduced in C99.
+type CompoundLiteral struct {
+	ExpressionBase[*CompoundLiteral]
+	Type        *Type
+	Initializer *InitializerList
+}
+
+func NewCompoundLiteral(t *Type, initializer *InitializerList) *CompoundLiteral {
+	expr := &CompoundLiteral{
+		Type:        t,
+		Initializer: initializer,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *CompoundLiteral) codeElement()    {}
+func (e *CompoundLiteral) expressionNode() {}
+
+func (e *CompoundLiteral) Write(out *StyleWriter, level Level) error {
+	return out.Write(level, OperatorLeftParen, e.Type.Abstract(), OperatorRightParen, e.Initializer)
+}
+
+// CommaExpression evaluates expressions in order and yields the last one, it is always parenthesized to be used as
+// an argument.
+type CommaExpression struct {
+	ExpressionBase[*CommaExpression]
+	Expressions []Expression
+}
+
+func NewCommaExpression(expressions ...Expression) *CommaExpression {
+	expr := &CommaExpression{
+		Expressions: expressions,
+	}
+
+	return expr.Init(expr)
+}
+
+func (e *CommaExpression) codeElement()    {}
+func (e *CommaExpression) expressionNode() {}
+
+// Last returns the expression yielded.
+func (e *CommaExpression) Last() Expression {
+	return e.Expressions[len(e.Expressions)-1]
+}
+
+func (e *CommaExpression) Write(out *StyleWriter, level Level) error {
+	parts := make([]CodeElement, 0, 2*len(e.Expressions)+2)
+	parts = append(parts, OperatorLeftParen)
+	for i, expr := range e.Expressions {
+		parts = append(parts, out.style.Comma().On(i > 0), expr)
+	}
+	parts = append(parts, OperatorRightParen)
+
+	return out.Write(NewLevel(level.IndentLevel, 0), parts...)
+}
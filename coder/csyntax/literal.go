@@ -1,5 +1,11 @@
 package csyntax
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type IntegerFormat int
 
 const (
@@ -72,3 +78,92 @@ func (i *Integer) Write(out *StyleWriter, level Level) error {
 
 	return out.Write(level, elem)
 }
+
+type Float struct {
+	ExpressionBase[*Float]
+	Value float64
+}
+
+func NewFloatLiteral(value float64) *Float {
+	f := &Float{
+		Value: value,
+	}
+
+	return f.Init(f)
+}
+
+func (f *Float) codeElement()    {}
+func (f *Float) expressionNode() {}
+
+func (f *Float) Write(out *StyleWriter, level Level) error {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+
+	return out.Write(level, StringElement(s))
+}
+
+type String struct {
+	ExpressionBase[*String]
+	Value string
+}
+
+func NewStringLiteral(value string) *String {
+	s := &String{
+		Value: value,
+	}
+
+	return s.Init(s)
+}
+
+func (s *String) codeElement()    {}
+func (s *String) expressionNode() {}
+
+// Quote returns the C string literal of the value. Non-printable bytes are written in octal escapes,
+// which are never greedy beyond 3 digits, and "??" is broken to avoid trigraphs.
+func (s *String) Quote() string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for i := 0; i < len(s.Value); i++ {
+		c := s.Value[i]
+		switch c {
+		case '"':
+			b.WriteString(`\"`)
+
+		case '\\':
+			b.WriteString(`\\`)
+
+		case '\n':
+			b.WriteString(`\n`)
+
+		case '\r':
+			b.WriteString(`\r`)
+
+		case '\t':
+			b.WriteString(`\t`)
+
+		case '?':
+			if i > 0 && s.Value[i-1] == '?' {
+				b.WriteString(`\?`)
+			} else {
+				b.WriteByte(c)
+			}
+
+		default:
+			if c < 0x20 || c >= 0x7f {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+
+	b.WriteByte('"')
+	return b.String()
+}
+
+func (s *String) Write(out *StyleWriter, level Level) error {
+	return out.Write(level, StringElement(s.Quote()))
+}
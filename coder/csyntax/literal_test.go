@@ -21,3 +21,41 @@ func TestIntegerWrite(t *testing.T) {
 		checkOutputOnStyle(t, testStyle1, c.expected, c.value)
 	}
 }
+
+func TestStringWrite(t *testing.T) {
+	cases := []struct {
+		value    *String
+		expected string
+	}{
+		{NewStringLiteral("hello, world"), `"hello, world"`},
+		{NewStringLiteral("line\n"), `"line\n"`},
+		{NewStringLiteral("tab\tcr\r"), `"tab\tcr\r"`},
+		{NewStringLiteral(`say "hi" \ bye`), `"say \"hi\" \\ bye"`},
+		{NewStringLiteral("\x00\x1b[0m"), `"\000\033[0m"`},
+		{NewStringLiteral("中"), `"\344\270\255"`},
+		{NewStringLiteral("what??!"), `"what?\?!"`},
+	}
+
+	for _, c := range cases {
+		checkInterfaceCodeElement(c.value)
+		checkInterfaceExpression(c.value)
+		checkOutputOnStyle(t, testStyle1, c.expected, c.value)
+	}
+}
+
+func TestFloatWrite(t *testing.T) {
+	cases := []struct {
+		value    *Float
+		expected string
+	}{
+		{NewFloatLiteral(3.5), `3.5`},
+		{NewFloatLiteral(2), `2.0`},
+		{NewFloatLiteral(1e-10), `1e-10`},
+	}
+
+	for _, c := range cases {
+		checkInterfaceCodeElement(c.value)
+		checkInterfaceExpression(c.value)
+		checkOutputOnStyle(t, testStyle1, c.expected, c.value)
+	}
+}
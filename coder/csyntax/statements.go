@@ -134,6 +134,14 @@ func NewAssignmentStatement(leftIdentifier string, leftPointerLevel int, rightEx
 	return s
 }
 
+func NewAssignmentStatementTo(target Expression, operator Punctuator, rightExpression Expression) *AssignmentStatement {
+	s := &AssignmentStatement{
+		Expression: NewAssignmentExpressionTo(target, operator, rightExpression),
+	}
+
+	return s
+}
+
 func (s *AssignmentStatement) codeElement()   {}
 func (s *AssignmentStatement) statementNode() {}
 
@@ -146,6 +154,25 @@ func (s *AssignmentStatement) Write(out *StyleWriter, level Level) error {
 	return out.WriteIndentLine(level, parts...)
 }
 
+type ExpressionStatement struct {
+	Expression Expression
+}
+
+func NewExpressionStatement(expression Expression) *ExpressionStatement {
+	s := &ExpressionStatement{
+		Expression: expression,
+	}
+
+	return s
+}
+
+func (s *ExpressionStatement) codeElement()   {}
+func (s *ExpressionStatement) statementNode() {}
+
+func (s *ExpressionStatement) Write(out *StyleWriter, level Level) error {
+	return out.WriteIndentLine(level, s.Expression, PunctuatorSemicolon)
+}
+
 type ReturnStatement struct {
 	Expression Expression
 }
@@ -213,6 +240,15 @@ func (s *IfStatement) codeElement()   {}
 func (s *IfStatement) statementNode() {}
 
 func (s *IfStatement) Write(out *StyleWriter, level Level) error {
+	if err := out.WriteIndent(level); err != nil {
+		return err
+	}
+
+	return s.writeChain(out, level)
+}
+
+// writeChain writes the if statement without leading indent, so that it can follow 'else' in else-if chain.
+func (s *IfStatement) writeChain(out *StyleWriter, level Level) error {
 	parts := []CodeElement{
 		KeywordIf, out.style.IfSpacing.Select(DelimiterSpace), OperatorLeftParen, s.Expression, OperatorRightParen,
 		out.style.IfNewLine(level), out.style.IfBraceIndent, OperatorLeftBrace, out.style.EOL,
@@ -222,13 +258,17 @@ func (s *IfStatement) Write(out *StyleWriter, level Level) error {
 
 	if s.ElseBody.Length() > 0 {
 		first := s.ElseBody.GetStatement(0)
-		if _, ok := first.(*IfStatement); ok {
+		if next, ok := first.(*IfStatement); ok && s.ElseBody.Length() == 1 {
 			parts = append(parts,
 				out.style.IfNewLine(level), out.style.IfBraceIndent,
-				KeywordElse, DelimiterSpace, first,
+				KeywordElse, DelimiterSpace,
 			)
 
-			return out.Write(level, parts...)
+			if err := out.Write(level, parts...); err != nil {
+				return err
+			}
+
+			return next.writeChain(out, level)
 
 		} else {
 			parts = append(parts,
@@ -241,7 +281,7 @@ func (s *IfStatement) Write(out *StyleWriter, level Level) error {
 		}
 	}
 
-	return out.WriteIndentLine(level, parts...)
+	return out.WriteLine(level, parts...)
 }
 
 type WhileStatement struct {
@@ -341,6 +381,32 @@ func (s *ForStatement) Write(out *StyleWriter, level Level) error {
 	return out.WriteIndentLine(level, parts...)
 }
 
+// BlockStatement is a compound statement in braces, variables declared in it are not visible outside.
+type BlockStatement struct {
+	Body *CodeBlock
+}
+
+func NewBlockStatement(body *CodeBlock) *BlockStatement {
+	s := &BlockStatement{
+		Body: body,
+	}
+
+	return s
+}
+
+func (s *BlockStatement) codeElement()   {}
+func (s *BlockStatement) statementNode() {}
+
+func (s *BlockStatement) Write(out *StyleWriter, level Level) error {
+	parts := []CodeElement{
+		OperatorLeftBrace, out.style.EOL,
+		s.Body,
+		out.style.GetIndent(level), OperatorRightBrace,
+	}
+
+	return out.WriteIndentLine(level, parts...)
+}
+
 type KeywordStatement struct {
 	Keyword Keyword
 }
@@ -564,6 +564,30 @@ func TestWhileStatementStyle2(t *testing.T) {
 	checkOutputOnStyle(t, testStyle2, expected, whileStmt)
 }
 
+func TestBlockStatement(t *testing.T) {
+	body := NewCodeBlock([]Statement{
+		NewDeclarationStatement(NewVariableDeclaration("int", []VariableDeclarationItem{
+			NewVariableDeclarator("i", 0, NewIntegerLiteral(0)),
+		})),
+		NewAssignmentStatement("i", 0, NewInfixExpression(NewIdentifier("i"), OperatorAdd, NewIntegerLiteral(1))),
+	})
+
+	block := NewBlockStatement(body)
+
+	checkInterfaceCodeElement(block)
+	checkInterfaceStatement(block)
+
+	expected := strings.Join([]string{
+		"{",
+		"    int i = 0;",
+		"    i = i + 1;",
+		"}",
+		"",
+	}, "\n")
+	checkOutputOnStyle(t, testStyle1, expected, block)
+	checkOutputOnStyle(t, testStyle2, expected, block)
+}
+
 func TestDoWhileStatementStyle1(t *testing.T) {
 	body := NewCodeBlock([]Statement{
 		NewAssignmentStatement("i", 0, NewInfixExpression(NewIdentifier("i"), OperatorAdd, NewIntegerLiteral(1))),
@@ -874,3 +898,43 @@ func TestSwitchStatementStyle2(t *testing.T) {
 	}, "\n")
 	checkOutputOnStyle(t, testStyle2, expected, switchStmt)
 }
+
+func TestExpressionStatementWrite(t *testing.T) {
+	stmt := NewExpressionStatement(NewCallExpression(NewIdentifier("puts"), NewStringLiteral("hi")))
+
+	checkInterfaceCodeElement(stmt)
+	checkInterfaceStatement(stmt)
+
+	expected := "puts(\"hi\");\n"
+	checkOutputOnStyle(t, testStyle1, expected, stmt)
+}
+
+func TestIfElseChainStatement(t *testing.T) {
+	chain := NewIfElseChainStatement(
+		[]*IfStatement{
+			NewIfStatement(
+				NewInfixExpression(NewIdentifier("a"), OperatorEqual, NewIntegerLiteral(0)),
+				NewCodeBlock([]Statement{NewReturnStatement(NewIntegerLiteral(0))}),
+			),
+			NewIfStatement(
+				NewInfixExpression(NewIdentifier("a"), OperatorEqual, NewIntegerLiteral(1)),
+				NewCodeBlock([]Statement{NewReturnStatement(NewIntegerLiteral(1))}),
+			),
+		},
+		NewCodeBlock([]Statement{NewReturnStatement(NewIntegerLiteral(2))}),
+	)
+
+	block := NewCodeBlock([]Statement{chain})
+
+	expected := strings.Join([]string{
+		"    if (a == 0) {",
+		"        return 0;",
+		"    } else if (a == 1) {",
+		"        return 1;",
+		"    } else {",
+		"        return 2;",
+		"    }",
+		"",
+	}, "\n")
+	checkOutputOnStyle(t, testStyle1, expected, block)
+}
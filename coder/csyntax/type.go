@@ -3,6 +3,10 @@ package csyntax
 type Type struct {
 	Base         StringElement
 	PointerLevel int
+	ArraySize    int
+
+	// ListElement is type of elements if the type is a pointer to list in runtime, it is not written.
+	ListElement *Type
 }
 
 func NewType(base string, pointerLevel int) *Type {
@@ -22,6 +26,42 @@ func NewPointerType(base string) *Type {
 	return NewType(base, 1)
 }
 
+// NewArrayType creates type of a fixed size array, whose elements are in type of base and pointer level.
+func NewArrayType(base string, pointerLevel int, size int) *Type {
+	t := NewType(base, pointerLevel)
+	t.ArraySize = size
+	return t
+}
+
+// NewListType creates type of pointer to a list header in runtime, whose elements are in type of element.
+func NewListType(header string, element *Type) *Type {
+	t := NewPointerType(header)
+	t.ListElement = element
+	return t
+}
+
+// Element returns type of elements in an array, a list or a pointer.
+func (t *Type) Element() *Type {
+	if t.ListElement != nil {
+		e := *t.ListElement
+		return &e
+	}
+
+	if t.ArraySize > 0 {
+		return NewType(string(t.Base), t.PointerLevel)
+	}
+
+	return NewType(string(t.Base), t.PointerLevel-1)
+}
+
+func (t *Type) IsArray() bool {
+	return t.ArraySize > 0
+}
+
+func (t *Type) IsList() bool {
+	return t.ListElement != nil
+}
+
 func (t *Type) codeElement() {}
 
 func (t *Type) Write(out *StyleWriter, level Level) error {
@@ -32,6 +72,33 @@ func (t *Type) Write(out *StyleWriter, level Level) error {
 			PunctuatorAsterisk.Duplicate(t.PointerLevel),
 			out.style.PointerSpacingAfter.Select(DelimiterSpace),
 		).On(t.PointerLevel > 0),
+		NewElementCollection(
+			OperatorLeftBracket, NewIntegerStringElement(t.ArraySize), OperatorRightBracket,
+		).On(t.ArraySize > 0),
+	}
+
+	return out.Write(level, parts...)
+}
+
+// Abstract returns type written without declarator, like in casts and sizeof, which has no spacing after asterisks.
+func (t *Type) Abstract() CodeElement {
+	return (*abstractType)(t)
+}
+
+type abstractType Type
+
+func (t *abstractType) codeElement() {}
+
+func (t *abstractType) Write(out *StyleWriter, level Level) error {
+	parts := []CodeElement{
+		t.Base,
+		NewElementCollection(
+			out.style.PointerSpacingBefore.Select(DelimiterSpace),
+			PunctuatorAsterisk.Duplicate(t.PointerLevel),
+		).On(t.PointerLevel > 0),
+		NewElementCollection(
+			OperatorLeftBracket, NewIntegerStringElement(t.ArraySize), OperatorRightBracket,
+		).On(t.ArraySize > 0),
 	}
 
 	return out.Write(level, parts...)
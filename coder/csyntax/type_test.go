@@ -28,3 +28,16 @@ func TestTypeWriteOnPointerType2(t *testing.T) {
 	expected := "char **"
 	checkOutputOnStyle(t, testStyle2, expected, ty)
 }
+
+func TestTypeWriteOnArrayType(t *testing.T) {
+	ty := NewArrayType("int", 0, 4)
+	checkInterfaceCodeElement(ty)
+
+	expected := "int[4]"
+	checkOutputOnStyle(t, testStyle1, expected, ty)
+
+	element := ty.Element()
+	if element.IsArray() || element.Base != "int" || element.PointerLevel != 0 {
+		t.Errorf("wrong element type of array: %+v", element)
+	}
+}
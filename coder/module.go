@@ -0,0 +1,243 @@
+package coder
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/check"
+	"github.com/flily/magi-c/coder/csyntax"
+	"github.com/flily/magi-c/context"
+)
+
+// ModuleName returns name declared by 'module' in document.
+func ModuleName(doc *ast.Document) (string, bool) {
+	for _, decl := range doc.Declarations {
+		if m, ok := decl.(*ast.ModuleDeclaration); ok {
+			return m.Name.Name, true
+		}
+	}
+
+	return "", false
+}
+
+func isMainFunction(decl *ast.FunctionDeclaration) bool {
+	return decl.Name.Name == DefaultMainEntryName && !decl.IsMethod()
+}
+
+// sourceDirectory returns directory in which imported modules are searched.
+func (c *Coder) sourceDirectory() string {
+	stat, err := os.Stat(c.SourceBase)
+	if err == nil && !stat.IsDir() {
+		return filepath.Dir(c.SourceBase)
+	}
+
+	return c.SourceBase
+}
+
+// loadModule finds document of an imported module, source file 'name.mc' in source directory is parsed if the
+// module is not loaded yet.
+func (c *Coder) loadModule(imp *ast.ImportDeclaration) (*ast.Document, error) {
+	name := imp.Name.Name
+	if doc, found := c.ImportedDocument(name); found {
+		return doc, nil
+	}
+
+	filename := filepath.Join(c.sourceDirectory(), name+DefaultSourceSuffix)
+	if _, err := os.Stat(filename); err != nil {
+		return nil, imp.Name.Context().Error("module '%s' not found", name).
+			With("no source file '%s'", filename).
+			ByRule(check.RuleUndefinedName, context.Error)
+	}
+
+	index, err := c.ParseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := c.Refs.Documents[index]
+	if declared, _ := ModuleName(doc); declared != name {
+		return nil, imp.Name.Context().Error("source file '%s' is not module '%s'", filename, name).
+			With("'module %s' SHALL be declared in file", name).
+			ByRule(check.RuleModuleName, context.Error)
+	}
+
+	return doc, nil
+}
+
+// ImportedDocument returns document of a loaded module.
+func (c *Coder) ImportedDocument(name string) (*ast.Document, bool) {
+	index, found := c.Refs.Module(name)
+	if !found {
+		return nil, false
+	}
+
+	return c.Refs.Get(index)
+}
+
+// ResolveImports loads modules imported by a source file recursively, and returns modules imported directly.
+// Import cycles are reported with the full chain of imports.
+func (c *Coder) ResolveImports(source string) (map[string]*ast.Document, error) {
+	doc, found := c.Refs.Get(source)
+	if !found {
+		return nil, nil
+	}
+
+	root := source
+	if root == "." {
+		root = filepath.Base(c.SourceBase)
+	}
+
+	if name, ok := ModuleName(doc); ok {
+		root = name
+	}
+
+	return c.resolveImports(doc, []string{root})
+}
+
+func (c *Coder) resolveImports(doc *ast.Document, chain []string) (map[string]*ast.Document, error) {
+	result := make(map[string]*ast.Document)
+	for _, decl := range doc.Declarations {
+		imp, ok := decl.(*ast.ImportDeclaration)
+		if !ok {
+			continue
+		}
+
+		name := imp.Name.Name
+		next := append(slices.Clone(chain), name)
+		if slices.Contains(chain, name) {
+			return nil, imp.Name.Context().Error("import cycle not allowed: %s", strings.Join(next, " -> ")).
+				With("imported here").
+				ByRule(check.RuleImportCycle, context.Error)
+		}
+
+		imported, err := c.loadModule(imp)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := c.resolveImports(imported, next); err != nil {
+			return nil, err
+		}
+
+		result[name] = imported
+	}
+
+	return result, nil
+}
+
+func (c *Coder) globalType(ctx *Context, d *ast.GlobalDeclaration) *csyntax.Type {
+	var typ *csyntax.Type
+	if d.Variable.IsAuto() && d.Variable.Value != nil {
+		typ = c.InferExpressionType(ctx, d.Variable.Value)
+
+	} else {
+		typ = c.OutputType(ctx, d.Variable.Type)
+	}
+
+	if typ == nil {
+		typ = csyntax.NewConcreteType("int")
+	}
+
+	return typ
+}
+
+// registerGlobal registers a global variable of current document, which is prefixed by name of module in C code.
+func (c *Coder) registerGlobal(ctx *Context, d *ast.GlobalDeclaration) *VariableInfo {
+	name := d.Variable.Name.Name
+	info, _ := ctx.DeclareVariable(name, d.Variable.Type, ModuleSymbolName(ctx.Module, name), c.globalType(ctx, d))
+	if info != nil && !d.Variable.IsRef() {
+		// global owners are never freed at scope exit, but values are moved out of them
+		info.Owner = isOwnerType(&info.CodeType)
+	}
+
+	return info
+}
+
+// registerImport registers exported functions and global variables of an imported module, which are referenced
+// as 'foo.bar'.
+func (c *Coder) registerImport(ctx *Context, module string, doc *ast.Document) {
+	ctx.Imports = append(ctx.Imports, module)
+	for _, decl := range doc.Declarations {
+		switch d := decl.(type) {
+		case *ast.FunctionDeclaration:
+			if d.IsExported() && !d.IsMethod() {
+				ctx.RegisterImportedFunction(module, d)
+			}
+
+		case *ast.GlobalDeclaration:
+			if d.IsExported() {
+				name := d.Variable.Name.Name
+				codeName := ModuleSymbolName(module, name)
+				ctx.DeclareVariable(QualifiedName(module, name), d.Variable.Type, codeName, c.globalType(ctx, d))
+			}
+		}
+	}
+}
+
+func (c *Coder) outputGlobalVariable(ctx *Context, info *VariableInfo, storage csyntax.Keyword, value csyntax.Expression) *csyntax.DeclarationStatement {
+	base := string(info.CodeType.Base)
+	if len(storage) > 0 {
+		base = string(storage) + " " + base
+	}
+
+	if storage != csyntax.KeywordExtern {
+		value = declarationInitializer(ctx, &info.CodeType, value)
+	}
+
+	declarator := csyntax.NewTypedDeclarator(&info.CodeType, info.CodeName, value)
+	decl := csyntax.NewVariableDeclaration(base, []csyntax.VariableDeclarationItem{declarator})
+	return csyntax.NewDeclarationStatement(decl)
+}
+
+// OutputGlobalDeclaration outputs a global variable at file scope, global variables not exported by a module are
+// static.
+func (c *Coder) OutputGlobalDeclaration(ctx *Context, d *ast.GlobalDeclaration) *csyntax.DeclarationStatement {
+	info, found := ctx.Global.GetName(d.Variable.Name.Name)
+	if !found {
+		info = c.registerGlobal(ctx, d)
+	}
+
+	var value csyntax.Expression
+	if d.Variable.Value != nil {
+		value = c.OutputExpression(ctx, d.Variable.Value)
+	}
+
+	var storage csyntax.Keyword
+	if len(ctx.Module) > 0 && !d.IsExported() {
+		storage = csyntax.KeywordStatic
+	}
+
+	return c.outputGlobalVariable(ctx, info, storage, value)
+}
+
+// OutputImports outputs declarations of exported global variables and prototypes of exported functions of
+// imported modules.
+func (c *Coder) OutputImports(ctx *Context) [][]csyntax.CodeElement {
+	result := make([][]csyntax.CodeElement, 0, len(ctx.Imports))
+	for _, module := range ctx.Imports {
+		doc, _ := c.ImportedDocument(module)
+		chunk := make([]csyntax.CodeElement, 0, len(doc.Declarations))
+		for _, decl := range doc.Declarations {
+			switch d := decl.(type) {
+			case *ast.GlobalDeclaration:
+				if info, found := ctx.Find(QualifiedName(module, d.Variable.Name.Name)); found {
+					chunk = append(chunk, c.outputGlobalVariable(ctx, info, csyntax.KeywordExtern, nil))
+				}
+
+			case *ast.FunctionDeclaration:
+				if info, found := ctx.Functions[QualifiedName(module, FunctionName(d))]; found {
+					chunk = append(chunk, c.outputFunctionSignature(ctx, info).Prototype())
+				}
+			}
+		}
+
+		if len(chunk) > 0 {
+			result = append(result, chunk)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,221 @@
+package coder
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSourceFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("write file '%s' failed: %s", name, err)
+		}
+	}
+
+	return dir
+}
+
+func TestModuleOutput(t *testing.T) {
+	module := strings.Join([]string{
+		`module math`,
+		`export global var count int = 0`,
+		`global var base int = 3`,
+		`fun inc(a int) with(base int) (int) {`,
+		`    return a + base`,
+		`}`,
+		`export fun add(a int, b int) (int) {`,
+		`    return inc(a) + b`,
+		`}`,
+		``,
+	}, "\n")
+
+	source := strings.Join([]string{
+		`import math`,
+		`fun main() {`,
+		`    var x = math.add(1, 2)`,
+		`    var c = math.count`,
+		`}`,
+		``,
+	}, "\n")
+
+	dir := writeSourceFiles(t, map[string]string{
+		"math.mc": module,
+		"test.mc": source,
+	})
+
+	coder := NewCoder(dir, ".")
+	_, err := coder.ParseFile(filepath.Join(dir, "test.mc"))
+	if err != nil {
+		t.Fatalf("ParseFile failed:\n%s", err)
+	}
+
+	err = coder.Check(testFilename)
+	if err != nil {
+		t.Fatalf("Check failed:\n%s", err)
+	}
+
+	expectedModule := strings.Join([]string{
+		`#line 2 "math.mc"`,
+		`int math_count = 0;`,
+		`#line 3 "math.mc"`,
+		`static int math_base = 3;`,
+		``,
+		`#line 4 "math.mc"`,
+		`static int math_inc(int a)`,
+		`{`,
+		`#line 5 "math.mc"`,
+		`    return a + math_base;`,
+		`}`,
+		``,
+		`#line 7 "math.mc"`,
+		`int math_add(int a, int b)`,
+		`{`,
+		`#line 8 "math.mc"`,
+		`    return math_inc(a) + b;`,
+		`}`,
+		``,
+	}, "\n")
+
+	expectedSource := strings.Join([]string{
+		`extern int math_count;`,
+		`int math_add(int a, int b);`,
+		``,
+		`#line 2 "test.mc"`,
+		`void main()`,
+		`{`,
+		`#line 3 "test.mc"`,
+		`    int x = math_add(1, 2);`,
+		``,
+		`#line 4 "test.mc"`,
+		`    int c = math_count;`,
+		`}`,
+		``,
+	}, "\n")
+
+	cases := []struct {
+		index    string
+		expected string
+	}{
+		{"math.mc", expectedModule},
+		{testFilename, expectedSource},
+	}
+
+	for _, c := range cases {
+		buf := bytes.NewBuffer(nil)
+		if err := coder.OutputTo(c.index, buf); err != nil {
+			t.Fatalf("OutputTo '%s' failed:\n%s", c.index, err)
+		}
+
+		// #line directives refer to absolute paths in temporary directory
+		output := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+		if output != c.expected {
+			t.Errorf("Output code of '%s' mismatch:\nExpect:\n%s\nGot:\n%s", c.index, c.expected, output)
+		}
+	}
+}
+
+func TestModuleOutputInferredTypes(t *testing.T) {
+	module := strings.Join([]string{
+		`module math`,
+		`export global var ratio float64 = 0.5`,
+		``,
+	}, "\n")
+
+	source := strings.Join([]string{
+		`import math`,
+		`fun main() {`,
+		`    r := math.ratio`,
+		`}`,
+		``,
+	}, "\n")
+
+	dir := writeSourceFiles(t, map[string]string{
+		"math.mc": module,
+		"test.mc": source,
+	})
+
+	coder := NewCoder(dir, ".")
+	if _, err := coder.ParseFile(filepath.Join(dir, "test.mc")); err != nil {
+		t.Fatalf("ParseFile failed:\n%s", err)
+	}
+
+	if err := coder.Check(testFilename); err != nil {
+		t.Fatalf("Check failed:\n%s", err)
+	}
+
+	// type of module member is resolved by checker
+	expected := strings.Join([]string{
+		`extern double math_ratio;`,
+		``,
+		`#line 2 "test.mc"`,
+		`void main()`,
+		`{`,
+		`#line 3 "test.mc"`,
+		`    double r = math_ratio;`,
+		`}`,
+		``,
+	}, "\n")
+
+	buf := bytes.NewBuffer(nil)
+	if err := coder.OutputTo(testFilename, buf); err != nil {
+		t.Fatalf("OutputTo failed:\n%s", err)
+	}
+
+	output := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+	if output != expected {
+		t.Errorf("Output code mismatch:\nExpect:\n%s\nGot:\n%s", expected, output)
+	}
+}
+
+func TestModuleImportErrors(t *testing.T) {
+	cases := []struct {
+		files    map[string]string
+		expected string
+	}{
+		{
+			map[string]string{
+				"test.mc": "import io\n",
+			},
+			"module 'io' not found",
+		},
+		{
+			map[string]string{
+				"test.mc": "import io\n",
+				"io.mc":   "module os\n",
+			},
+			"is not module 'io'",
+		},
+		{
+			map[string]string{
+				"test.mc": "import a\n",
+				"a.mc":    "module a\nimport b\n",
+				"b.mc":    "module b\nimport a\n",
+			},
+			"import cycle not allowed: test.mc -> a -> b -> a",
+		},
+	}
+
+	for _, c := range cases {
+		dir := writeSourceFiles(t, c.files)
+		coder := NewCoder(dir, ".")
+		if _, err := coder.ParseFile(filepath.Join(dir, "test.mc")); err != nil {
+			t.Fatalf("ParseFile failed:\n%s", err)
+		}
+
+		err := coder.Check(testFilename)
+		if err == nil {
+			t.Fatalf("Check expected to fail with '%s'", c.expected)
+		}
+
+		if !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Check error mismatch, expect '%s', got:\n%s", c.expected, err)
+		}
+	}
+}
@@ -6,8 +6,46 @@ import (
 )
 
 var magicOperatorMap = map[ast.TokenType]csyntax.Punctuator{
-	ast.Plus: csyntax.OperatorAdd,
-	ast.Sub:  csyntax.OperatorSubtract,
+	ast.Or:                 csyntax.OperatorLogicalOr,
+	ast.And:                csyntax.OperatorLogicalAnd,
+	ast.Equal:              csyntax.OperatorEqual,
+	ast.NotEqual:           csyntax.OperatorNotEqual,
+	ast.LessThan:           csyntax.OperatorLessThan,
+	ast.LessThanOrEqual:    csyntax.OperatorLessEqual,
+	ast.GreaterThan:        csyntax.OperatorGreaterThan,
+	ast.GreaterThanOrEqual: csyntax.OperatorGreaterEqual,
+	ast.Plus:               csyntax.OperatorAdd,
+	ast.Sub:                csyntax.OperatorSubtract,
+	ast.Asterisk:           csyntax.OperatorMultiply,
+	ast.Slash:              csyntax.OperatorDivide,
+	ast.Percent:            csyntax.OperatorModulo,
+	ast.VerticalBar:        csyntax.OperatorBitwiseOr,
+	ast.Caret:              csyntax.OperatorBitwiseXor,
+	ast.Ampersand:          csyntax.OperatorBitwiseAnd,
+	ast.ShiftLeft:          csyntax.OperatorShiftLeft,
+	ast.ShiftRight:         csyntax.OperatorShiftRight,
+}
+
+var magicPrefixOperatorMap = map[ast.TokenType]csyntax.Punctuator{
+	ast.Sub:       csyntax.OperatorNegative,
+	ast.Tilde:     csyntax.OperatorBitwiseNot,
+	ast.Not:       csyntax.OperatorLogicalNot,
+	ast.Ampersand: csyntax.OperatorAddressOf,
+	ast.Asterisk:  csyntax.OperatorDereference,
+}
+
+var magicAssignOperatorMap = map[ast.TokenType]csyntax.Punctuator{
+	ast.Assign:           csyntax.OperatorAssign,
+	ast.AddAssign:        csyntax.OperatorAssignAdd,
+	ast.SubAssign:        csyntax.OperatorAssignSubtract,
+	ast.MulAssign:        csyntax.OperatorAssignMultiply,
+	ast.DivAssign:        csyntax.OperatorAssignDivide,
+	ast.ModAssign:        csyntax.OperatorAssignModulo,
+	ast.AndAssign:        csyntax.OperatorAssignAnd,
+	ast.OrAssign:         csyntax.OperatorAssignOr,
+	ast.XorAssign:        csyntax.OperatorAssignXor,
+	ast.ShiftLeftAssign:  csyntax.OperatorAssignShiftLeft,
+	ast.ShiftRightAssign: csyntax.OperatorAssignShiftRight,
 }
 
 func OperatorMap(op ast.TokenType) csyntax.Punctuator {
@@ -18,3 +56,21 @@ func OperatorMap(op ast.TokenType) csyntax.Punctuator {
 
 	panic("unsupported operator: " + op.String())
 }
+
+func PrefixOperatorMap(op ast.TokenType) csyntax.Punctuator {
+	p, found := magicPrefixOperatorMap[op]
+	if found {
+		return p
+	}
+
+	panic("unsupported prefix operator: " + op.String())
+}
+
+func AssignOperatorMap(op ast.TokenType) csyntax.Punctuator {
+	p, found := magicAssignOperatorMap[op]
+	if found {
+		return p
+	}
+
+	panic("unsupported assignment operator: " + op.String())
+}
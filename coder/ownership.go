@@ -0,0 +1,324 @@
+package coder
+
+import (
+	"slices"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+// isOwnerType tells whether a variable in type t may own heap data, which are pointers and lists.
+func isOwnerType(t *csyntax.Type) bool {
+	return !t.IsArray() && t.PointerLevel > 0
+}
+
+// isReference tells whether a pointer declared with value is a reference, which is declared with 'ref', or
+// initialized with 'ref p' or another reference.
+func isReference(ctx *Context, ref bool, value ast.Expression) bool {
+	if ref {
+		return true
+	}
+
+	switch e := value.(type) {
+	case *ast.ParenthesizedExpression:
+		return isReference(ctx, false, e.Expression)
+
+	case *ast.PrefixExpression:
+		return e.Operator.Token == ast.Ref
+
+	case *ast.Identifier:
+		info, found := ctx.Find(e.Name)
+		return found && isOwnerType(&info.CodeType) && !info.Owner
+	}
+
+	return false
+}
+
+// declareOwner marks a pointer or list just declared as owner of its value, and a structure value as owner of its
+// fields.
+func declareOwner(ctx *Context, name *ast.Identifier) {
+	if name.IsDummy() {
+		return
+	}
+
+	info, found := ctx.Find(name.Name)
+	if !found {
+		return
+	}
+
+	info.Owner = isOwnerType(&info.CodeType)
+	info.Fields = len(valueOwnedFields(ctx, &info.CodeType)) > 0
+}
+
+// movedSources returns owning pointers moved by value, which are set to null after assigned. Pointers in members and
+// elements are moved out, and values of fields in struct literal are moved into the new structure.
+func (c *Coder) movedSources(ctx *Context, value ast.Expression) []csyntax.Expression {
+	switch e := value.(type) {
+	case *ast.Identifier:
+		info, found := ctx.Find(e.Name)
+		if found && info.Owner {
+			return []csyntax.Expression{csyntax.NewIdentifier(info.CodeName)}
+		}
+
+		if found && info.Fields {
+			// structure copied owns the fields, which are moved out of the source
+			fields := valueOwnedFields(ctx, &info.CodeType)
+			result := make([]csyntax.Expression, 0, len(fields))
+			for _, field := range fields {
+				result = append(result, csyntax.NewMemberExpression(csyntax.NewIdentifier(info.CodeName), field.Name.Name))
+			}
+
+			return result
+		}
+
+	case *ast.ParenthesizedExpression:
+		return c.movedSources(ctx, e.Expression)
+
+	case *ast.StructLiteral:
+		var result []csyntax.Expression
+		for _, field := range e.Fields {
+			result = append(result, c.movedSources(ctx, field.Value)...)
+		}
+
+		return result
+
+	case *ast.MemberExpression, *ast.IndexExpression:
+		if isOwnerType(c.InferExpressionType(ctx, e)) {
+			return []csyntax.Expression{c.OutputExpression(ctx, e)}
+		}
+
+	case *ast.NewExpression:
+		literal, ok := e.StructLiteral()
+		if !ok {
+			return nil
+		}
+
+		var result []csyntax.Expression
+		for _, field := range literal.Fields {
+			result = append(result, c.movedSources(ctx, field.Value)...)
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// assignedSources returns owning pointers moved by assignment, values assigned to references are not moved.
+func (c *Coder) assignedSources(ctx *Context, target ast.Expression, value ast.Expression) []csyntax.Expression {
+	if id, ok := target.(*ast.Identifier); ok {
+		info, found := ctx.Find(id.Name)
+		if id.IsDummy() || (found && !info.Owner) {
+			return nil
+		}
+	}
+
+	return c.movedSources(ctx, value)
+}
+
+func outputMoves(ctx *Context, moved []csyntax.Expression) []csyntax.Statement {
+	stmts := make([]csyntax.Statement, 0, len(moved))
+	for _, source := range moved {
+		null := csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+		stmts = append(stmts, csyntax.NewAssignmentStatementTo(source, csyntax.OperatorAssign, null))
+	}
+
+	return stmts
+}
+
+// outputOwnedDeclaration outputs declaration of variable in function, a pointer declared owns its value unless it
+// is a reference, and owners in value are moved to it.
+func (c *Coder) outputOwnedDeclaration(ctx *Context, decl *ast.VariableDeclaration) []csyntax.Statement {
+	if stmts, ok := c.outputSequencedDeclaration(ctx, decl.Name, decl.Type, decl.IsConst(), decl.Value); ok {
+		moved := c.movedSources(ctx, decl.Value)
+		declareOwner(ctx, decl.Name)
+		return append(stmts, outputMoves(ctx, moved)...)
+	}
+
+	ref := isReference(ctx, decl.IsRef(), decl.Value)
+	var moved []csyntax.Expression
+	if !ref && decl.Value != nil {
+		moved = c.movedSources(ctx, decl.Value)
+	}
+
+	stmts := []csyntax.Statement{c.OutputVariableDeclaration(ctx, decl)}
+	if !ref {
+		declareOwner(ctx, decl.Name)
+	}
+
+	return append(stmts, outputMoves(ctx, moved)...)
+}
+
+// isSimpleValue tells whether value is a variable or literal, which is returned without temporary variable.
+func isSimpleValue(value ast.Expression) bool {
+	switch value.(type) {
+	case *ast.Identifier, *ast.IntegerLiteral, *ast.FloatLiteral, *ast.BooleanLiteral, *ast.NullLiteral:
+		return true
+	}
+
+	return false
+}
+
+// ownedFields returns fields of pointers and lists in structure pointed by t, which are freed with the structure.
+func ownedFields(ctx *Context, t *csyntax.Type) []*ast.FieldDeclaration {
+	if t.PointerLevel != 1 || t.IsList() || t.IsArray() {
+		return nil
+	}
+
+	s, ok := ctx.StructOf(string(t.Base))
+	if !ok {
+		return nil
+	}
+
+	fields := make([]*ast.FieldDeclaration, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		switch typ := field.Type.(type) {
+		case *ast.SimpleType:
+			if len(typ.PointerAsterisk) > 0 {
+				fields = append(fields, field)
+			}
+
+		case *ast.ListType:
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// valueOwnedFields returns fields of pointers and lists in structure value in type t, which are freed when the
+// variable of structure goes out of scope.
+func valueOwnedFields(ctx *Context, t *csyntax.Type) []*ast.FieldDeclaration {
+	if t.PointerLevel != 0 || t.IsList() || t.IsArray() {
+		return nil
+	}
+
+	return ownedFields(ctx, csyntax.NewPointerType(string(t.Base)))
+}
+
+// outputVariableFree frees value owned by variable, or values of owned fields of a structure variable.
+func (c *Coder) outputVariableFree(ctx *Context, info *VariableInfo) []csyntax.Statement {
+	if info.Owner {
+		return []csyntax.Statement{outputFree(ctx, &info.CodeType, csyntax.NewIdentifier(info.CodeName))}
+	}
+
+	if !info.Fields {
+		return nil
+	}
+
+	fields := valueOwnedFields(ctx, &info.CodeType)
+	stmts := make([]csyntax.Statement, 0, len(fields))
+	for _, field := range fields {
+		member := csyntax.NewMemberExpression(csyntax.NewIdentifier(info.CodeName), field.Name.Name)
+		stmts = append(stmts, outputFree(ctx, c.OutputType(ctx, field.Type), member))
+	}
+
+	return stmts
+}
+
+// outputFree frees value owned by target, lists are freed by runtime, and structures with owned fields are freed by
+// their destructors.
+func outputFree(ctx *Context, typ *csyntax.Type, target csyntax.Expression) csyntax.Statement {
+	free := csyntax.NewIdentifier(ctx.UseInclude("stdlib.h", "free"))
+	if typ.IsList() {
+		free = csyntax.NewIdentifier(ctx.UseRuntime(RuntimeListFree))
+
+	} else if len(ownedFields(ctx, typ)) > 0 {
+		free = csyntax.NewIdentifier(ctx.UseDestructor(string(typ.Base)))
+	}
+
+	return csyntax.NewExpressionStatement(csyntax.NewCallExpression(free, target))
+}
+
+// OutputDestructors outputs functions freeing structures with their owned fields. Destructors may call each other
+// recursively, so they are declared by prototypes first.
+func (c *Coder) OutputDestructors(ctx *Context) [][]csyntax.CodeElement {
+	if len(ctx.Destructors) == 0 {
+		return nil
+	}
+
+	definitions := make([]*csyntax.FunctionDeclaration, 0, len(ctx.Destructors))
+	for i := 0; i < len(ctx.Destructors); i++ {
+		// destructors of fields are appended while output
+		definitions = append(definitions, c.outputDestructor(ctx, ctx.Destructors[i]))
+	}
+
+	prototypes := make([]csyntax.CodeElement, 0, len(definitions))
+	result := make([][]csyntax.CodeElement, 0, len(definitions)+1)
+	for _, f := range definitions {
+		prototypes = append(prototypes, f.Prototype())
+		result = append(result, []csyntax.CodeElement{f})
+	}
+
+	return append([][]csyntax.CodeElement{prototypes}, result...)
+}
+
+func (c *Coder) outputDestructor(ctx *Context, typeName string) *csyntax.FunctionDeclaration {
+	typ := csyntax.NewPointerType(typeName)
+	value := csyntax.NewIdentifier("value")
+	params := csyntax.NewParameterList(csyntax.NewParameterListItem(typ, "value"))
+	f := csyntax.NewFunctionDeclaration(DestructorName(typeName), csyntax.NewConcreteType("void"), params, nil)
+	f.Static = true
+
+	cond := csyntax.NewInfixExpression(value, csyntax.OperatorEqual, csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL")))
+	f.AddStatement(csyntax.NewIfStatement(cond, csyntax.NewCodeBlock([]csyntax.Statement{csyntax.NewReturnStatement(nil)})))
+	f.AddStatement(csyntax.NewEmptyLine())
+	for _, field := range ownedFields(ctx, typ) {
+		member := csyntax.NewPointerMemberExpression(value, field.Name.Name)
+		f.AddStatement(outputFree(ctx, c.OutputType(ctx, field.Type), member))
+	}
+
+	free := csyntax.NewIdentifier(ctx.UseInclude("stdlib.h", "free"))
+	f.AddStatement(csyntax.NewExpressionStatement(csyntax.NewCallExpression(free, value)))
+	return f
+}
+
+// outputScopeExit frees values of owners in frames from the top one to last, or to the function frame if last is
+// nil, and owned fields of structures too. Owners are freed in reverse order of declaration, except those moved out
+// of function by return.
+func (c *Coder) outputScopeExit(ctx *Context, last *Frame, except []*VariableInfo) []csyntax.Statement {
+	stmts := make([]csyntax.Statement, 0, 4)
+	for frame := ctx.FunctionFrame; frame != nil; frame = frame.Next {
+		variables := frame.Variables.Variables
+		for i := len(variables) - 1; i >= 0; i-- {
+			info := variables[i]
+			if !slices.Contains(except, info) {
+				stmts = append(stmts, c.outputVariableFree(ctx, info)...)
+			}
+		}
+
+		if frame == last {
+			break
+		}
+	}
+
+	return stmts
+}
+
+// returnedOwners returns owners moved to caller by a return value, which are not freed.
+func returnedOwners(ctx *Context, value ast.Expression) []*VariableInfo {
+	switch e := value.(type) {
+	case *ast.Identifier:
+		if info, found := ctx.Find(e.Name); found && (info.Owner || info.Fields) {
+			return []*VariableInfo{info}
+		}
+
+	case *ast.ParenthesizedExpression:
+		return returnedOwners(ctx, e.Expression)
+
+	case *ast.NewExpression:
+		literal, ok := e.StructLiteral()
+		if !ok {
+			return nil
+		}
+
+		var result []*VariableInfo
+		for _, field := range literal.Fields {
+			result = append(result, returnedOwners(ctx, field.Value)...)
+		}
+
+		return result
+	}
+
+	return nil
+}
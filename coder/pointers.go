@@ -0,0 +1,192 @@
+package coder
+
+import (
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+// pointerRoot returns name of the variable a pointer is derived from by pointer arithmetic, like 'p' in 'p +>> 4'.
+func pointerRoot(expr ast.Expression) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name, true
+
+	case *ast.ParenthesizedExpression:
+		return pointerRoot(e.Expression)
+
+	case *ast.PointerArithmeticExpression:
+		return pointerRoot(e.Pointer)
+	}
+
+	return "", false
+}
+
+// reassignedVariables collects variables assigned with values not derived from themselves in statements. Pointers
+// reassigned may point to another allocation in later iterations of loops, so their allocations are not tracked.
+func reassignedVariables(stmts []ast.Statement, result map[string]bool) map[string]bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.AssignmentStatement:
+			parallel := s.Targets.Length() == s.Values.Length()
+			for i, target := range s.Targets.Expressions {
+				id, ok := target.Expression.(*ast.Identifier)
+				if !ok {
+					continue
+				}
+
+				if s.Operator.Token != ast.Assign {
+					// compound assignments are arithmetic on the variable itself
+					continue
+				}
+
+				if parallel {
+					if root, ok := pointerRoot(s.Values.Expressions[i].Expression); ok && root == id.Name {
+						continue
+					}
+				}
+
+				result[id.Name] = true
+			}
+
+		case *ast.BlockStatement:
+			reassignedVariables(s.Statements, result)
+
+		case *ast.IfStatement:
+			for _, branch := range s.Branches {
+				reassignedVariables(branch.Body.Statements, result)
+			}
+
+			if s.ElseBody != nil {
+				reassignedVariables(s.ElseBody.Statements, result)
+			}
+
+		case *ast.WhileStatement:
+			reassignedVariables(s.Body.Statements, result)
+
+		case *ast.DoWhileStatement:
+			reassignedVariables(s.Body.Statements, result)
+
+		case *ast.ForStatement:
+			if s.Post != nil {
+				reassignedVariables([]ast.Statement{s.Post}, result)
+			}
+
+			reassignedVariables(s.Body.Statements, result)
+
+		case *ast.ForeachStatement:
+			reassignedVariables(s.Body.Statements, result)
+		}
+	}
+
+	return result
+}
+
+// allocationOf returns the variable whose storage a pointer expression points into, or nil if it is unknown.
+func allocationOf(ctx *Context, expr ast.Expression) *VariableInfo {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		if info, found := ctx.Find(e.Name); found {
+			if info.CodeType.IsArray() {
+				return info
+			}
+
+			return info.Allocation
+		}
+
+	case *ast.ParenthesizedExpression:
+		return allocationOf(ctx, e.Expression)
+
+	case *ast.PointerArithmeticExpression:
+		return allocationOf(ctx, e.Pointer)
+
+	case *ast.PrefixExpression:
+		if e.Operator.Token != ast.Ampersand {
+			return nil
+		}
+
+		switch operand := e.Operand.(type) {
+		case *ast.Identifier:
+			if info, found := ctx.Find(operand.Name); found && !info.CodeType.IsList() {
+				return info
+			}
+
+		case *ast.IndexExpression:
+			if id, ok := operand.Object.(*ast.Identifier); ok {
+				if info, found := ctx.Find(id.Name); found && info.CodeType.IsArray() {
+					return info
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// trackAllocation records allocation of a pointer variable just declared, allocation SHALL be computed before the
+// declaration, since the variable may shadow the one pointed to.
+func trackAllocation(ctx *Context, name *ast.Identifier, allocation *VariableInfo) {
+	if allocation == nil || ctx.Reassigned[name.Name] {
+		return
+	}
+
+	if info, found := ctx.Find(name.Name); found && info.CodeType.PointerLevel > 0 {
+		info.Allocation = allocation
+	}
+}
+
+// outputAllocationCheck wraps a pointer in runtime check that it stays inside the allocation. The check is dropped if
+// the allocated variable is shadowed at the point of use.
+func outputAllocationCheck(ctx *Context, pointer csyntax.Expression, allocation *VariableInfo, node ast.Node) (csyntax.Expression, bool) {
+	if info, found := ctx.Find(allocation.SourceName); !found || info != allocation {
+		return pointer, false
+	}
+
+	var base csyntax.Expression = csyntax.NewIdentifier(allocation.CodeName)
+	if !allocation.CodeType.IsArray() {
+		base = csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, base)
+	}
+
+	size := outputVariableSizeof(allocation)
+	args := append([]csyntax.Expression{pointer, base, size}, sourcePosition(node)...)
+	return csyntax.NewCallExpression(csyntax.NewIdentifier(ctx.UseRuntime(RuntimeCheckPointer)), args...), true
+}
+
+// OutputPointerArithmetic outputs shift of a pointer in bytes through 'uint8_t *', or in units of type by casting to
+// pointer of the unit. Pointer in debug mode is checked to stay inside the allocation, if it is known.
+func (c *Coder) OutputPointerArithmetic(ctx *Context, e *ast.PointerArithmeticExpression) csyntax.Expression {
+	typ := c.InferExpressionType(ctx, e.Pointer)
+	pointer := c.OutputExpression(ctx, e.Pointer)
+	offset := c.OutputExpression(ctx, e.Offset)
+
+	var unit *csyntax.Type
+	if e.Unit != nil {
+		unit = c.OutputType(ctx, e.Unit)
+
+	} else {
+		unit = csyntax.NewConcreteType(ctx.UseInclude("stdint.h", "uint8_t"))
+	}
+
+	unitPointer := csyntax.NewType(string(unit.Base), unit.PointerLevel+1)
+	casted := unitPointer.Base != typ.Base || unitPointer.PointerLevel != typ.PointerLevel
+	if casted {
+		pointer = csyntax.NewCastExpression(unitPointer, pointer)
+	}
+
+	op := csyntax.OperatorAdd
+	if e.IsSubtraction() {
+		op = csyntax.OperatorSubtract
+	}
+
+	var result csyntax.Expression = csyntax.NewInfixExpression(pointer, op, offset)
+	if allocation := allocationOf(ctx, e.Pointer); c.Debug && allocation != nil {
+		if checked, ok := outputAllocationCheck(ctx, result, allocation, e); ok {
+			result, casted = checked, true
+		}
+	}
+
+	if casted {
+		result = csyntax.NewCastExpression(csyntax.NewType(string(typ.Base), typ.PointerLevel), result)
+	}
+
+	return result
+}
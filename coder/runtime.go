@@ -0,0 +1,226 @@
+package coder
+
+import (
+	"slices"
+
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+const (
+	RuntimeCheckIndex   = "__magic_check_index"
+	RuntimeCheckPointer = "__magic_check_pointer"
+	RuntimeRealloc      = "__magic_realloc"
+	RuntimeNew          = "__magic_new"
+	RuntimeList         = "__magic_list"
+	RuntimeListNew      = "__magic_list_new"
+	RuntimeListLength   = "__magic_list_len"
+	RuntimeListCap      = "__magic_list_cap"
+	RuntimeListAt       = "__magic_list_at"
+	RuntimeListAppend   = "__magic_list_append"
+	RuntimeListRemove   = "__magic_list_remove"
+	RuntimeListFree     = "__magic_list_free"
+)
+
+// runtimeFunction is C code of a runtime support function, which is emitted as static into output using it.
+type runtimeFunction struct {
+	Name     string
+	Includes []string
+	Requires []string
+	Code     string
+}
+
+// runtimeFunctions are in order of output, functions SHALL be defined after those they depend on.
+var runtimeFunctions = []*runtimeFunction{
+	{
+		Name:     RuntimeCheckIndex,
+		Includes: []string{"stdio.h", "stdlib.h"},
+		Code: `static long __magic_check_index(long index, long length, const char *file, int line)
+{
+    if (index < 0 || index >= length) {
+        fprintf(stderr, "%s:%d: index out of range [%ld] with length %ld\n", file, line, index, length);
+        abort();
+    }
+
+    return index;
+}`,
+	},
+	{
+		Name:     RuntimeCheckPointer,
+		Includes: []string{"stdint.h", "stdio.h", "stdlib.h"},
+		Code: `static void *__magic_check_pointer(const void *pointer, const void *base, long size, const char *file, int line)
+{
+    long offset = (long)((uintptr_t)pointer - (uintptr_t)base);
+    if ((uintptr_t)pointer < (uintptr_t)base || offset > size) {
+        fprintf(stderr, "%s:%d: pointer out of allocation, offset %ld with size %ld\n", file, line, offset, size);
+        abort();
+    }
+
+    return (void *)pointer;
+}`,
+	},
+	{
+		Name:     RuntimeRealloc,
+		Includes: []string{"stdio.h", "stdlib.h"},
+		Code: `static void *__magic_realloc(void *ptr, long size)
+{
+    void *result = realloc(ptr, size);
+    if (result == NULL && size > 0) {
+        fprintf(stderr, "out of memory, allocating %ld bytes\n", size);
+        abort();
+    }
+
+    return result;
+}`,
+	},
+	{
+		Name:     RuntimeNew,
+		Includes: []string{"string.h"},
+		Requires: []string{RuntimeRealloc},
+		Code: `static void *__magic_new(long size, const void *value)
+{
+    void *result = __magic_realloc(NULL, size);
+    if (value != NULL) {
+        memcpy(result, value, size);
+    } else {
+        memset(result, 0, size);
+    }
+
+    return result;
+}`,
+	},
+	{
+		Name: RuntimeList,
+		Code: `typedef struct __magic_list {
+    char *data;
+    long length;
+    long capacity;
+    long size;
+} __magic_list;`,
+	},
+	{
+		Name:     RuntimeListNew,
+		Includes: []string{"string.h"},
+		Requires: []string{RuntimeList, RuntimeRealloc},
+		Code: `static __magic_list *__magic_list_new(long size, long length, long count, const void *values)
+{
+    __magic_list *list = __magic_realloc(NULL, sizeof(__magic_list));
+    list->data = NULL;
+    list->length = length;
+    list->capacity = length;
+    list->size = size;
+    if (length > 0) {
+        list->data = __magic_realloc(NULL, length * size);
+        memset(list->data, 0, length * size);
+    }
+
+    if (count > 0) {
+        memcpy(list->data, values, count * size);
+    }
+
+    return list;
+}`,
+	},
+	{
+		Name:     RuntimeListLength,
+		Requires: []string{RuntimeList},
+		Code: `static long __magic_list_len(const __magic_list *list)
+{
+    return list == NULL ? 0 : list->length;
+}`,
+	},
+	{
+		Name:     RuntimeListCap,
+		Requires: []string{RuntimeList},
+		Code: `static long __magic_list_cap(const __magic_list *list)
+{
+    return list == NULL ? 0 : list->capacity;
+}`,
+	},
+	{
+		Name:     RuntimeListAt,
+		Requires: []string{RuntimeCheckIndex, RuntimeListLength},
+		Code: `static void *__magic_list_at(__magic_list *list, long index, const char *file, int line)
+{
+    index = __magic_check_index(index, __magic_list_len(list), file, line);
+    return list->data + index * list->size;
+}`,
+	},
+	{
+		Name:     RuntimeListAppend,
+		Requires: []string{RuntimeListNew},
+		Code: `static void __magic_list_append(__magic_list **list, long size, long count, const void *values)
+{
+    __magic_list *l = *list;
+    if (l == NULL) {
+        l = *list = __magic_list_new(size, 0, 0, NULL);
+    }
+
+    if (l->length + count > l->capacity) {
+        long capacity = l->capacity > 0 ? l->capacity * 2 : 4;
+        while (capacity < l->length + count) {
+            capacity *= 2;
+        }
+
+        l->data = __magic_realloc(l->data, capacity * l->size);
+        l->capacity = capacity;
+    }
+
+    memcpy(l->data + l->length * l->size, values, count * l->size);
+    l->length += count;
+}`,
+	},
+	{
+		Name:     RuntimeListRemove,
+		Includes: []string{"string.h"},
+		Requires: []string{RuntimeCheckIndex, RuntimeListLength},
+		Code: `static void __magic_list_remove(__magic_list *list, long index, const char *file, int line)
+{
+    index = __magic_check_index(index, __magic_list_len(list), file, line);
+    memmove(list->data + index * list->size, list->data + (index + 1) * list->size,
+            (list->length - index - 1) * list->size);
+    list->length--;
+}`,
+	},
+	{
+		Name:     RuntimeListFree,
+		Includes: []string{"stdlib.h"},
+		Requires: []string{RuntimeList},
+		Code: `static void __magic_list_free(__magic_list *list)
+{
+    if (list != NULL) {
+        free(list->data);
+        free(list);
+    }
+}`,
+	},
+}
+
+// OutputRuntime outputs headers required and definitions of runtime functions used.
+func (c *Coder) OutputRuntime(ctx *Context) []csyntax.CodeElement {
+	includes := slices.Clone(ctx.Includes)
+	functions := make([]csyntax.CodeElement, 0, len(runtimeFunctions))
+	for _, f := range runtimeFunctions {
+		if !ctx.Runtime[f.Name] {
+			continue
+		}
+
+		for _, include := range f.Includes {
+			if !slices.Contains(includes, include) {
+				includes = append(includes, include)
+			}
+		}
+
+		functions = append(functions, csyntax.NewEmptyLine(), csyntax.NewInlineBlock(f.Code))
+	}
+
+	if len(includes) == 0 && len(functions) == 0 {
+		return nil
+	}
+
+	result := make([]csyntax.CodeElement, 0, len(includes)+len(functions))
+	for _, include := range includes {
+		result = append(result, csyntax.NewIncludeAngle(include))
+	}
+
+	return append(result, functions...)
+}
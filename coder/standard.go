@@ -0,0 +1,152 @@
+package coder
+
+import (
+	"slices"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+// declarationsFirst nests statements from a declaration following other statements into a block in C89, where
+// declarations SHALL be at start of block. Preprocessor lines and comments before the declaration are nested with
+// it.
+func (c *Coder) declarationsFirst(stmts []csyntax.Statement) []csyntax.Statement {
+	if c.Standard != csyntax.C89 {
+		return stmts
+	}
+
+	start := -1
+	for i, stmt := range stmts {
+		switch stmt.(type) {
+		case *csyntax.DeclarationStatement:
+			if start >= 0 {
+				block := csyntax.NewBlockStatement(csyntax.NewCodeBlock(c.declarationsFirst(stmts[start:])))
+				return append(slices.Clone(stmts[:start]), block)
+			}
+
+		case *csyntax.EmptyLine:
+			if start == i {
+				start = i + 1
+			}
+
+		case *csyntax.Context, *csyntax.Comment:
+
+		default:
+			start = i + 1
+		}
+	}
+
+	return stmts
+}
+
+// scopedForStatement moves variable declared in initializer of for loop into a block around the loop in C89.
+func (c *Coder) scopedForStatement(f *csyntax.ForStatement) csyntax.Statement {
+	decl, ok := f.Initializer.(*csyntax.VariableDeclaration)
+	if c.Standard != csyntax.C89 || !ok {
+		return f
+	}
+
+	f.Initializer = nil
+	return csyntax.NewBlockStatement(csyntax.NewCodeBlock([]csyntax.Statement{csyntax.NewDeclarationStatement(decl), f}))
+}
+
+// outputArrayValue outputs values as an array in type. It is a compound literal in C99, and in C89, a temporary
+// array declared at start of function, whose elements are assigned in a comma expression. The array is cleared
+// before if values do not fill it.
+func (c *Coder) outputArrayValue(ctx *Context, typ *csyntax.Type, values []csyntax.Expression) csyntax.Expression {
+	if c.Standard != csyntax.C89 || ctx.IsGlobalContext() {
+		return csyntax.NewCompoundLiteral(typ, csyntax.NewInitializerList(values...))
+	}
+
+	temp := ctx.DeclareTemporary(typ, nil)
+	exprs := make([]csyntax.Expression, 0, len(values)+2)
+	if len(values) < typ.ArraySize {
+		memset := csyntax.NewIdentifier(ctx.UseInclude("string.h", "memset"))
+		exprs = append(exprs, csyntax.NewCallExpression(memset, temp, csyntax.NewIntegerLiteral(0), csyntax.NewSizeofExpression(temp)))
+	}
+
+	for i, value := range values {
+		element := csyntax.NewIndexExpression(temp, csyntax.NewIntegerLiteral(int64(i)))
+		exprs = append(exprs, csyntax.NewAssignmentExpressionTo(element, csyntax.OperatorAssign, value))
+	}
+
+	return csyntax.NewCommaExpression(append(exprs, temp)...)
+}
+
+// outputInitialValue outputs pointer to initial value of allocation in type. It is address of a compound literal in
+// C99, and in C89, address of a temporary variable assigned in a comma expression.
+func (c *Coder) outputInitialValue(ctx *Context, typ *csyntax.Type, value csyntax.Expression) csyntax.Expression {
+	if c.Standard != csyntax.C89 {
+		literal := csyntax.NewCompoundLiteral(typ, csyntax.NewInitializerList(value))
+		return csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, literal)
+	}
+
+	temp := ctx.DeclareTemporary(typ, nil)
+	return csyntax.NewCommaExpression(
+		csyntax.NewAssignmentExpressionTo(temp, csyntax.OperatorAssign, value),
+		csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, temp))
+}
+
+// isConstantValue tells whether value is a literal, which can be an element of initializer list in C89.
+func isConstantValue(value ast.Expression) bool {
+	switch value.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.BooleanLiteral, *ast.NullLiteral, *ast.StringLiteral:
+		return true
+	}
+
+	return false
+}
+
+// sequencedArrayLiteral returns array literal initializing a local variable in C89.
+func (c *Coder) sequencedArrayLiteral(ctx *Context, value ast.Expression) (*ast.ArrayLiteral, bool) {
+	l, ok := value.(*ast.ArrayLiteral)
+	return l, ok && c.Standard == csyntax.C89 && !ctx.IsGlobalContext()
+}
+
+// outputArrayDeclaration declares a local variable initialized by array literal in C89. Elements are in initializer
+// list if all of them are constant, otherwise the array is zero initialized and assigned element by element.
+func (c *Coder) outputArrayDeclaration(ctx *Context, name *ast.Identifier, sourceType ast.Type, isConst bool, l *ast.ArrayLiteral) []csyntax.Statement {
+	typ := c.arrayLiteralType(ctx, l)
+	if _, auto := sourceType.(*ast.AutoType); sourceType != nil && !auto {
+		typ = c.OutputType(ctx, sourceType)
+	}
+
+	constant := true
+	values := make([]csyntax.Expression, 0, l.Elements.Length())
+	for _, item := range l.Elements.Expressions {
+		constant = constant && isConstantValue(item.Expression)
+		values = append(values, c.OutputExpression(ctx, item.Expression))
+	}
+
+	if constant && len(values) > 0 {
+		return []csyntax.Statement{
+			c.outputDeclarationStatement(ctx, name, sourceType, typ, isConst, csyntax.NewInitializerList(values...)),
+		}
+	}
+
+	stmts := []csyntax.Statement{
+		c.outputDeclarationStatement(ctx, name, sourceType, typ, false, zeroInitializer()),
+	}
+
+	for i, value := range values {
+		element := csyntax.NewIndexExpression(csyntax.NewIdentifier(name.Name), csyntax.NewIntegerLiteral(int64(i)))
+		stmts = append(stmts, csyntax.NewAssignmentStatementTo(element, csyntax.OperatorAssign, value))
+	}
+
+	return stmts
+}
+
+// outputSequencedDeclaration declares a local variable initialized by a struct or array literal in C89, as
+// initializers of structures and arrays SHALL be constant. Constant structures are initialized by value instead, and
+// constant arrays assigned element by element are not constant in C.
+func (c *Coder) outputSequencedDeclaration(ctx *Context, name *ast.Identifier, sourceType ast.Type, isConst bool, value ast.Expression) ([]csyntax.Statement, bool) {
+	if l, ok := c.sequencedStructLiteral(ctx, value); ok && !isConst {
+		return c.outputStructDeclaration(ctx, name, sourceType, l), true
+	}
+
+	if l, ok := c.sequencedArrayLiteral(ctx, value); ok {
+		return c.outputArrayDeclaration(ctx, name, sourceType, isConst, l), true
+	}
+
+	return nil, false
+}
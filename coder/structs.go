@@ -0,0 +1,129 @@
+package coder
+
+import (
+	"slices"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+// zeroInitializer initializes all members of a value to zero, empty initializer is not allowed before C23.
+func zeroInitializer() *csyntax.InitializerList {
+	return csyntax.NewInitializerList(csyntax.NewIntegerLiteral(0))
+}
+
+// structInitializer outputs fields of struct literal as designated initializers, missing fields are zero
+// initialized.
+func (c *Coder) structInitializer(ctx *Context, l *ast.StructLiteral) *csyntax.InitializerList {
+	if len(l.Fields) == 0 {
+		return zeroInitializer()
+	}
+
+	values := make([]csyntax.Expression, 0, len(l.Fields))
+	for _, field := range l.Fields {
+		values = append(values, csyntax.NewDesignatedInitializer(field.Name.Name, c.OutputExpression(ctx, field.Value)))
+	}
+
+	return csyntax.NewInitializerList(values...)
+}
+
+// positionalInitializer outputs fields of struct literal in order of declaration for C89, missing fields are zero.
+// Designated initializers are used if the structure is unknown, like those declared in C headers.
+func (c *Coder) positionalInitializer(ctx *Context, l *ast.StructLiteral) *csyntax.InitializerList {
+	s, found := ctx.StructOf(l.Type.Identifier.Name)
+	if !found || len(l.Fields) == 0 {
+		return c.structInitializer(ctx, l)
+	}
+
+	values := make([]csyntax.Expression, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		var value csyntax.Expression = csyntax.NewIntegerLiteral(0)
+		for _, f := range l.Fields {
+			if f.Name.Name == field.Name.Name {
+				value = c.OutputExpression(ctx, f.Value)
+			}
+		}
+
+		values = append(values, value)
+	}
+
+	return csyntax.NewInitializerList(values...)
+}
+
+// outputFieldAssignments assigns fields of struct literal to target one by one, which is zero initialized before.
+func (c *Coder) outputFieldAssignments(ctx *Context, target csyntax.Expression, l *ast.StructLiteral) []csyntax.Expression {
+	assigns := make([]csyntax.Expression, 0, len(l.Fields))
+	for _, field := range l.Fields {
+		member := csyntax.NewMemberExpression(target, field.Name.Name)
+		assigns = append(assigns, csyntax.NewAssignmentExpressionTo(member, csyntax.OperatorAssign, c.OutputExpression(ctx, field.Value)))
+	}
+
+	return assigns
+}
+
+// sequencedStructLiteral returns struct literal initializing a local variable in C89, which is zero initialized and
+// then assigned field by field.
+func (c *Coder) sequencedStructLiteral(ctx *Context, value ast.Expression) (*ast.StructLiteral, bool) {
+	l, ok := value.(*ast.StructLiteral)
+	return l, ok && c.Standard == csyntax.C89 && !ctx.IsGlobalContext()
+}
+
+// outputStructDeclaration declares a local variable initialized by struct literal in C89. Fields are initialized by
+// position if all of them are constant, otherwise they are assigned one by one.
+func (c *Coder) outputStructDeclaration(ctx *Context, name *ast.Identifier, sourceType ast.Type, l *ast.StructLiteral) []csyntax.Statement {
+	_, known := ctx.StructOf(l.Type.Identifier.Name)
+	constant := known && len(l.Fields) > 0
+	for _, field := range l.Fields {
+		constant = constant && isConstantValue(field.Value)
+	}
+
+	if constant {
+		return []csyntax.Statement{
+			c.outputDeclarationStatement(ctx, name, sourceType, c.OutputType(ctx, l.Type), false, c.positionalInitializer(ctx, l)),
+		}
+	}
+
+	stmts := []csyntax.Statement{
+		c.outputDeclarationStatement(ctx, name, sourceType, c.OutputType(ctx, l.Type), false, zeroInitializer()),
+	}
+
+	for _, assign := range c.outputFieldAssignments(ctx, csyntax.NewIdentifier(name.Name), l) {
+		stmts = append(stmts, csyntax.NewExpressionStatement(assign))
+	}
+
+	return stmts
+}
+
+// OutputStructLiteral outputs a structure value. It is a compound literal with designated initializers in C99, and
+// in C89, a temporary variable zero initialized at start of function, whose fields are assigned in a comma
+// expression where the literal is evaluated. Struct literals of global variables in C89 are initialized by position.
+func (c *Coder) OutputStructLiteral(ctx *Context, l *ast.StructLiteral) csyntax.Expression {
+	typ := c.OutputType(ctx, l.Type)
+	if c.Standard != csyntax.C89 {
+		return csyntax.NewCompoundLiteral(typ, c.structInitializer(ctx, l))
+	}
+
+	if ctx.IsGlobalContext() {
+		return csyntax.NewCompoundLiteral(typ, c.positionalInitializer(ctx, l))
+	}
+
+	// fields not in literal are never assigned, so they are zero in every evaluation.
+	temp := ctx.DeclareTemporary(typ, zeroInitializer())
+	assigns := c.outputFieldAssignments(ctx, temp, l)
+	if len(assigns) == 0 {
+		return temp
+	}
+
+	return csyntax.NewCommaExpression(append(assigns, temp)...)
+}
+
+// addressOfValue takes address of value, which is the temporary variable yielded if value is a comma expression.
+func addressOfValue(value csyntax.Expression) csyntax.Expression {
+	if comma, ok := value.(*csyntax.CommaExpression); ok {
+		exprs := slices.Clone(comma.Expressions)
+		exprs[len(exprs)-1] = addressOfValue(comma.Last())
+		return csyntax.NewCommaExpression(exprs...)
+	}
+
+	return csyntax.NewUnaryExpression(csyntax.OperatorAddressOf, value)
+}
@@ -0,0 +1,437 @@
+package coder
+
+import (
+	"strings"
+
+	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/coder/check"
+	"github.com/flily/magi-c/coder/csyntax"
+)
+
+var basicTypeMap = map[string]string{
+	"int8":    "int8_t",
+	"int16":   "int16_t",
+	"int32":   "int32_t",
+	"int64":   "int64_t",
+	"uint8":   "uint8_t",
+	"uint16":  "uint16_t",
+	"uint32":  "uint32_t",
+	"uint64":  "uint64_t",
+	"float32": "float",
+	"float64": "double",
+	"bool":    "int",
+}
+
+// TypeMap returns name of C type for a basic type, other types are kept as is.
+func TypeMap(name string) string {
+	if t, found := basicTypeMap[name]; found {
+		return t
+	}
+
+	return name
+}
+
+// isFixedWidthType tells whether a basic type is mapped to an integer type declared in stdint.h.
+func isFixedWidthType(name string) bool {
+	t, found := basicTypeMap[name]
+	return found && strings.HasSuffix(t, "_t")
+}
+
+// OutputType returns C type of a type in source, stdint.h is included if fixed width integers are used.
+func (c *Coder) OutputType(ctx *Context, t ast.Type) *csyntax.Type {
+	switch typ := t.(type) {
+	case *ast.SimpleType:
+		name := TypeMap(typ.Identifier.Name)
+		if isFixedWidthType(typ.Identifier.Name) {
+			name = ctx.UseInclude("stdint.h", name)
+		}
+
+		return csyntax.NewType(name, len(typ.PointerAsterisk))
+
+	case *ast.ArrayType:
+		element := c.OutputType(ctx, typ.ElementType)
+		size := 0
+		if typ.Size != nil {
+			if n, ok := c.constantInteger(ctx, typ.Size); ok {
+				size = int(n)
+			}
+		}
+
+		return csyntax.NewArrayType(string(element.Base), element.PointerLevel, size)
+
+	case *ast.ListType:
+		return csyntax.NewListType(ctx.UseRuntime(RuntimeList), c.OutputType(ctx, typ.ElementType))
+
+	default:
+		return nil
+	}
+}
+
+func isComparisonOperator(op ast.TokenType) bool {
+	switch op {
+	case ast.Equal, ast.NotEqual, ast.LessThan, ast.LessThanOrEqual, ast.GreaterThan, ast.GreaterThanOrEqual,
+		ast.And, ast.Or:
+		return true
+	}
+
+	return false
+}
+
+// elementType returns type of elements in a pointer, array or list.
+func elementType(t *csyntax.Type) *csyntax.Type {
+	if t.IsArray() || t.IsList() || t.PointerLevel > 0 {
+		return t.Element()
+	}
+
+	// FIXME: element type of unknown values is int for now
+	return csyntax.NewConcreteType("int")
+}
+
+// arrayLiteralType returns type of array literal, whose size is inferred from elements if it is omitted.
+func (c *Coder) arrayLiteralType(ctx *Context, l *ast.ArrayLiteral) *csyntax.Type {
+	t := c.OutputType(ctx, l.Type)
+	if !t.IsArray() {
+		t.ArraySize = l.Elements.Length()
+	}
+
+	return t
+}
+
+// listType returns type of list allocated by a list literal.
+func (c *Coder) listType(ctx *Context, l *ast.ArrayLiteral) *csyntax.Type {
+	return csyntax.NewListType(ctx.UseRuntime(RuntimeList), c.OutputType(ctx, l.Type.ElementType))
+}
+
+// newValueType returns type of value allocated by new expression other than lists.
+func (c *Coder) newValueType(ctx *Context, e *ast.NewExpression) *csyntax.Type {
+	switch v := e.Value.(type) {
+	case *ast.StructLiteral:
+		return c.OutputType(ctx, v.Type)
+
+	case *ast.CallExpression:
+		if id, ok := v.Callee.(*ast.Identifier); ok {
+			return c.OutputType(ctx, ast.NewSimpleType(nil, id))
+		}
+	}
+
+	// FIXME: values of unknown types are int for now
+	return csyntax.NewConcreteType("int")
+}
+
+// declarationInitializer converts compound literal of an array or a structure in declaration to initializer list,
+// arrays and structures owning fields without initial value are zero initialized, and lists without initial value
+// are empty.
+func declarationInitializer(ctx *Context, t *csyntax.Type, value csyntax.Expression) csyntax.Expression {
+	if t.IsList() && value == nil {
+		return csyntax.NewIdentifier(ctx.UseInclude("stddef.h", "NULL"))
+	}
+
+	if l, ok := value.(*csyntax.CompoundLiteral); ok && t.PointerLevel == 0 {
+		return l.Initializer
+	}
+
+	if value == nil && (t.IsArray() || len(valueOwnedFields(ctx, t)) > 0) {
+		return zeroInitializer()
+	}
+
+	return value
+}
+
+// InferExpressionType infers C type of an expression for auto declarations.
+func (c *Coder) InferExpressionType(ctx *Context, expr ast.Expression) *csyntax.Type {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		if info, found := ctx.Find(e.Name); found {
+			t := info.CodeType
+			return &t
+		}
+
+	case *ast.FloatLiteral:
+		return csyntax.NewConcreteType("double")
+
+	case *ast.StringLiteral:
+		return csyntax.NewPointerType("char")
+
+	case *ast.NullLiteral:
+		return csyntax.NewPointerType("void")
+
+	case *ast.ParenthesizedExpression:
+		return c.InferExpressionType(ctx, e.Expression)
+
+	case *ast.PrefixExpression:
+		operand := c.InferExpressionType(ctx, e.Operand)
+		switch e.Operator.Token {
+		case ast.Not:
+			return csyntax.NewConcreteType("int")
+
+		case ast.Ampersand:
+			return csyntax.NewType(string(operand.Base), operand.PointerLevel+1)
+
+		case ast.Asterisk:
+			if operand.PointerLevel > 0 {
+				return csyntax.NewType(string(operand.Base), operand.PointerLevel-1)
+			}
+		}
+
+		return operand
+
+	case *ast.IndexExpression:
+		return elementType(c.InferExpressionType(ctx, e.Object))
+
+	case *ast.PointerArithmeticExpression:
+		return c.InferExpressionType(ctx, e.Pointer)
+
+	case *ast.ArrayLiteral:
+		return c.arrayLiteralType(ctx, e)
+
+	case *ast.NewExpression:
+		if l, ok := e.ListLiteral(); ok {
+			return c.listType(ctx, l)
+		}
+
+		t := c.newValueType(ctx, e)
+		return csyntax.NewType(string(t.Base), t.PointerLevel+1)
+
+	case *ast.CallExpression:
+		if name, ok := check.BuiltinCall(e); ok && (name == check.BuiltinLength || name == check.BuiltinCap) {
+			return csyntax.NewConcreteType("long")
+		}
+
+		if name, ok := check.ConversionCall(e); ok {
+			return c.OutputType(ctx, ast.ASTBuildSimpleType(name))
+		}
+
+		if info, found := c.calledFunction(ctx, e); found && info.ReturnCount == 1 {
+			return c.returnTypes(ctx, info.Declaration)[0]
+		}
+
+	case *ast.SizeofExpression:
+		return csyntax.NewConcreteType(ctx.UseInclude("stddef.h", "size_t"))
+
+	case *ast.StructLiteral:
+		return c.OutputType(ctx, e.Type)
+
+	case *ast.MemberExpression:
+		object := c.InferExpressionType(ctx, e.Object)
+		if s, found := ctx.StructOf(string(object.Base)); found {
+			if field, found := s.Field(e.Member.Name); found {
+				if t := c.OutputType(ctx, field.Type); t != nil {
+					return t
+				}
+			}
+		}
+
+	case *ast.InfixExpression:
+		if isComparisonOperator(e.Operator.Token) {
+			return csyntax.NewConcreteType("int")
+		}
+
+		left := c.InferExpressionType(ctx, e.LeftOperand)
+		right := c.InferExpressionType(ctx, e.RightOperand)
+		if left.PointerLevel > 0 || right.PointerLevel > 0 {
+			return left
+		}
+
+		return c.arithmeticResult(ctx, e, left, right)
+	}
+
+	if t, found := ctx.Typed.TypeOf(expr); found {
+		return c.OutputType(ctx, t)
+	}
+
+	// FIXME: integer literals are int for now
+	return csyntax.NewConcreteType("int")
+}
+
+// arithmeticRanks orders C types of numbers for the usual arithmetic conversions, floats are wider than integers,
+// and unsigned integers are wider than signed ones in the same size.
+var arithmeticRanks = map[string]int{
+	"int8_t":   1,
+	"uint8_t":  2,
+	"int16_t":  3,
+	"uint16_t": 4,
+	"int":      5,
+	"int32_t":  5,
+	"uint32_t": 6,
+	"long":     7,
+	"int64_t":  7,
+	"uint64_t": 8,
+	"size_t":   8,
+	"float":    9,
+	"double":   10,
+}
+
+// arithmeticResult returns C type of arithmetic on numbers in types left and right, the wider or floating type
+// wins, and constants take type of the other operand unless a float constant meets an integer.
+func (c *Coder) arithmeticResult(ctx *Context, e *ast.InfixExpression, left *csyntax.Type, right *csyntax.Type) *csyntax.Type {
+	if left.PointerLevel > 0 || right.PointerLevel > 0 {
+		return left
+	}
+
+	lrank, rrank := arithmeticRanks[string(left.Base)], arithmeticRanks[string(right.Base)]
+	lconst, rconst := c.isUntypedConstant(ctx, e.LeftOperand), c.isUntypedConstant(ctx, e.RightOperand)
+	switch {
+	case lconst && !rconst && !(lrank >= arithmeticRanks["float"] && rrank < arithmeticRanks["float"]):
+		return right
+
+	case rconst && !lconst && !(rrank >= arithmeticRanks["float"] && lrank < arithmeticRanks["float"]):
+		return left
+
+	case rrank > lrank:
+		return right
+	}
+
+	return left
+}
+
+// isUntypedConstant tells whether expr is a numeric constant, which takes type of the other operand.
+func (c *Coder) isUntypedConstant(ctx *Context, expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.FloatLiteral:
+		return true
+
+	case *ast.ParenthesizedExpression:
+		return c.isUntypedConstant(ctx, e.Expression)
+	}
+
+	_, ok := c.constantInteger(ctx, expr)
+	return ok
+}
+
+// outputTypes returns types of values returned by a call through output parameters, which are int if the function
+// is not found.
+func (c *Coder) outputTypes(ctx *Context, call *ast.CallExpression, count int) []*csyntax.Type {
+	var returns []*csyntax.Type
+	if info, found := c.calledFunction(ctx, call); found {
+		returns = c.returnTypes(ctx, info.Declaration)
+	}
+
+	result := make([]*csyntax.Type, count)
+	for i := range result {
+		if i < len(returns) {
+			result[i] = returns[i]
+
+		} else {
+			result[i] = csyntax.NewConcreteType("int")
+		}
+	}
+
+	return result
+}
+
+// parameterType returns C type of a parameter or a return value, arrays are passed by pointers to their elements.
+func (c *Coder) parameterType(ctx *Context, t ast.Type) *csyntax.Type {
+	typ := c.OutputType(ctx, t)
+	if typ.IsArray() {
+		return csyntax.NewType(string(typ.Base), typ.PointerLevel+1)
+	}
+
+	return typ
+}
+
+// returnTypes returns C types of values returned by a function.
+func (c *Coder) returnTypes(ctx *Context, decl *ast.FunctionDeclaration) []*csyntax.Type {
+	if decl.ReturnTypes == nil {
+		return nil
+	}
+
+	result := make([]*csyntax.Type, 0, decl.ReturnTypes.Length())
+	for _, item := range decl.ReturnTypes.Types {
+		result = append(result, c.parameterType(ctx, item.Type))
+	}
+
+	return result
+}
+
+// typeDependencies returns names of types which SHALL be defined before the declaration. Structures are declared
+// forward, so only fields by value and aliases of aliases are dependencies.
+func typeDependencies(ctx *Context, decl *ast.TypeDeclaration) []string {
+	result := make([]string, 0, 4)
+	if s, ok := decl.StructType(); ok {
+		for _, field := range s.Fields {
+			if t := ast.ValueType(field.Type); t != nil {
+				result = append(result, t.Identifier.Name)
+			}
+		}
+
+		return result
+	}
+
+	if t, ok := decl.Definition.(*ast.SimpleType); ok {
+		target, found := ctx.Types[t.Identifier.Name]
+		if !found {
+			return result
+		}
+
+		if _, isStruct := target.StructType(); !isStruct || len(t.PointerAsterisk) == 0 {
+			result = append(result, t.Identifier.Name)
+		}
+	}
+
+	return result
+}
+
+// SortTypeDeclarations orders type declarations so that every type is defined before used by value, declarations
+// keep the order in source if possible. Recursive types are reported by checker, and are kept as is here.
+func SortTypeDeclarations(ctx *Context, decls []*ast.TypeDeclaration) []*ast.TypeDeclaration {
+	result := make([]*ast.TypeDeclaration, 0, len(decls))
+	visited := make(map[*ast.TypeDeclaration]bool)
+
+	var visit func(decl *ast.TypeDeclaration)
+	visit = func(decl *ast.TypeDeclaration) {
+		if visited[decl] {
+			return
+		}
+
+		visited[decl] = true
+		for _, name := range typeDependencies(ctx, decl) {
+			if dep, found := ctx.Types[name]; found {
+				visit(dep)
+			}
+		}
+
+		result = append(result, decl)
+	}
+
+	for _, decl := range decls {
+		visit(decl)
+	}
+
+	return result
+}
+
+// constantInteger evaluates an integer constant expression, in which sizes known for the target are constants.
+func (c *Coder) constantInteger(ctx *Context, expr ast.Expression) (int64, bool) {
+	return check.NewLayout(c.Target, ctx.Types).ConstantInteger(expr, ctx.variableType)
+}
+
+// OutputSizeofExpression outputs size evaluated at compile time if it is known for the target, or C 'sizeof'.
+func (c *Coder) OutputSizeofExpression(ctx *Context, e *ast.SizeofExpression) csyntax.Expression {
+	if size, ok := c.constantInteger(ctx, e); ok {
+		return csyntax.NewIntegerLiteral(size)
+	}
+
+	t, value := check.SizeofOperand(e, ctx.variableType)
+	if t != nil {
+		return csyntax.NewSizeofType(c.OutputType(ctx, t))
+	}
+
+	if id, ok := value.(*ast.Identifier); ok {
+		if info, found := ctx.Find(id.Name); found {
+			return outputVariableSizeof(info)
+		}
+	}
+
+	return csyntax.NewSizeofExpression(c.OutputExpression(ctx, value))
+}
+
+// outputVariableSizeof outputs size of variable. Arrays in arguments are pointers in C, so their size is computed by
+// the declared type.
+func outputVariableSizeof(info *VariableInfo) csyntax.Expression {
+	if info.Parameter && info.CodeType.IsArray() {
+		t := info.CodeType
+		return csyntax.NewSizeofType(&t)
+	}
+
+	return csyntax.NewSizeofExpression(csyntax.NewIdentifier(info.CodeName))
+}
@@ -0,0 +1,57 @@
+package coder
+
+import (
+	"testing"
+
+	"github.com/flily/magi-c/ast"
+)
+
+func TestTypeMap(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"int32", "int32_t"},
+		{"uint64", "uint64_t"},
+		{"float64", "double"},
+		{"bool", "int"},
+		{"int", "int"},
+		{"Point", "Point"},
+	}
+
+	for _, c := range cases {
+		if got := TypeMap(c.name); got != c.expected {
+			t.Errorf("TypeMap(%s) expect %s, got %s", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestInferExpressionType(t *testing.T) {
+	c := NewCoder(".", "output")
+	ctx := NewContext()
+	ctx.PushFrame()
+	ctx.DeclareVariable("p", ast.ASTBuildSimpleType("*int8"), "p", c.OutputType(ctx, ast.ASTBuildSimpleType("*int8")))
+
+	cases := []struct {
+		expr     ast.Expression
+		base     string
+		pointers int
+	}{
+		{ast.ASTBuildValue(1), "int", 0},
+		{ast.ASTBuildValue(1.5), "double", 0},
+		{ast.ASTBuildValue("s"), "char", 1},
+		{ast.ASTBuildValue(nil), "void", 1},
+		{ast.ASTBuildIdentifier("p"), "int8_t", 1},
+		{ast.ASTBuildPrefixExpression(ast.Asterisk, ast.ASTBuildIdentifier("p")), "int8_t", 0},
+		{ast.ASTBuildPrefixExpression(ast.Ampersand, ast.ASTBuildIdentifier("p")), "int8_t", 2},
+		{ast.ASTBuildInfixExpression(ast.ASTBuildValue(1), ast.Plus, ast.ASTBuildValue(1.5)), "double", 0},
+		{ast.ASTBuildInfixExpression(ast.ASTBuildValue(1.5), ast.LessThan, ast.ASTBuildValue(2)), "int", 0},
+	}
+
+	for i, cs := range cases {
+		got := c.InferExpressionType(ctx, cs.expr)
+		if string(got.Base) != cs.base || got.PointerLevel != cs.pointers {
+			t.Errorf("case %d: expect %s with %d pointer levels, got %s with %d", i, cs.base, cs.pointers, got.Base, got.PointerLevel)
+		}
+	}
+}
@@ -36,6 +36,9 @@ func (l ErrorLevel) NewDiagnostic(ctx *Context, message string, note string) *Di
 type DiagnosticInfo interface {
 	error
 	Level() ErrorLevel
+
+	// Rule returns ID of the rule producing diagnostic, which is empty if not produced by a rule.
+	Rule() string
 }
 
 type Diagnostic struct {
@@ -43,6 +46,7 @@ type Diagnostic struct {
 	message string
 	context *Context
 	note    string
+	rule    string
 }
 
 func NewDiagnostic(level ErrorLevel, ctx *Context, message string, note string) *Diagnostic {
@@ -75,11 +79,30 @@ func (d *Diagnostic) Level() ErrorLevel {
 	return d.level
 }
 
+func (d *Diagnostic) Context() *Context {
+	return d.context
+}
+
+func (d *Diagnostic) Rule() string {
+	return d.rule
+}
+
 func (d *Diagnostic) Error() string {
 	messageLine := fmt.Sprintf("%s: %s: %s", d.context.PositionString(), d.level, d.message)
+	if len(d.rule) > 0 {
+		messageLine += fmt.Sprintf(" [%s]", d.rule)
+	}
+
 	return messageLine + DefaultNewLine + d.context.HighlightText(d.note)
 }
 
+// ByRule marks diagnostic produced by rule, in the level configured for the rule.
+func (d *Diagnostic) ByRule(rule string, level ErrorLevel) *Diagnostic {
+	d.rule = rule
+	d.level = level
+	return d
+}
+
 func (d *Diagnostic) With(note string, args ...any) *Diagnostic {
 	d.note = fmt.Sprintf(note, args...)
 	return d
@@ -109,6 +132,10 @@ func (c *DiagnosticCombo) Level() ErrorLevel {
 	return c.info.level
 }
 
+func (c *DiagnosticCombo) Rule() string {
+	return c.info.rule
+}
+
 func (c *DiagnosticCombo) Error() string {
 	parts := make([]string, 0, 2)
 	parts = append(parts, c.info.Error())
@@ -181,6 +208,18 @@ func (c *DiagnosticContainer) Error() string {
 	return strings.Join(parts, DefaultNewLine)
 }
 
+// CountRule returns number of diagnostics produced by rule.
+func (c *DiagnosticContainer) CountRule(rule string) int {
+	count := 0
+	for _, d := range c.Diagnostics {
+		if d.Rule() == rule {
+			count++
+		}
+	}
+
+	return count
+}
+
 func (c *DiagnosticContainer) Count(level ErrorLevel) int {
 	count := 0
 	for _, d := range c.Diagnostics {
@@ -232,3 +232,41 @@ func TestDiagnosticContaineMerge(t *testing.T) {
 		t.Fatalf("diagnostic container message mismatch, expected:\n%s\ngot:\n%s", expected, container.Error())
 	}
 }
+
+func TestDiagnosticByRule(t *testing.T) {
+	fd := createTestFile1()
+
+	line1 := fd.LineContext(3)
+	ctx1 := line1.Mark(7, 14)
+
+	line2 := fd.LineContext(4)
+	ctx2 := line2.Mark(13, 19)
+
+	container := NewDiagnosticContainer(Error)
+	err1 := ctx1.Warning("the quick brown fox").With("jumps over the lazy dog").ByRule("fox", Error)
+	err2 := ctx2.Warning("lorem ipsum").With("dolor sit amet").For(ctx1.Note("consectetur adipiscing elit"))
+
+	expected := strings.Join([]string{
+		"example.txt:4:8: error: the quick brown fox [fox]",
+		"    4 | sed do eiusmod tempor incididunt",
+		"      |        ^^^^^^^",
+		"      |        jumps over the lazy dog",
+	}, "\n")
+
+	if err1.Error() != expected {
+		t.Fatalf("error message mismatch, expected:\n%s\ngot:\n%s", expected, err1.Error())
+	}
+
+	if e := container.Add(err1); e == nil {
+		t.Fatalf("expected err1 to raise error level")
+	}
+
+	_ = container.Add(err2)
+	if err1.Rule() != "fox" || err2.Rule() != "" {
+		t.Fatalf("diagnostic rule mismatch, got '%s' and '%s'", err1.Rule(), err2.Rule())
+	}
+
+	if c := container.CountRule("fox"); c != 1 {
+		t.Fatalf("expected 1 diagnostic of rule fox, got %d", c)
+	}
+}
@@ -5,25 +5,66 @@ import (
 	"slices"
 
 	"github.com/flily/magi-c/ast"
+	"github.com/flily/magi-c/context"
 	"github.com/flily/magi-c/preprocessor"
 	"github.com/flily/magi-c/tokenizer"
 )
 
 var expressionFirstSet = []ast.TokenType{
+	ast.Null,
+	ast.False,
+	ast.True,
 	ast.Integer,
 	ast.Float,
 	ast.String,
 	ast.IdentifierName,
+	ast.LeftParen,
+	ast.New,
+	ast.Sizeof,
 }
 
 func inExpressionFirstSet(t ast.TokenType) bool {
-	return slices.Contains(expressionFirstSet, t)
+	return slices.Contains(expressionFirstSet, t) || slices.Contains(prefixOperators, t)
 }
 
+// declarationKeywords start declarations which appear only at top level of document.
+var declarationKeywords = []ast.TokenType{
+	ast.Function,
+	ast.Structure,
+	ast.TypeDefine,
+	ast.Module,
+	ast.Import,
+	ast.Export,
+}
+
+var statementKeywords = []ast.TokenType{
+	ast.Return,
+	ast.Var,
+	ast.Const,
+	ast.Global,
+	ast.If,
+	ast.While,
+	ast.Do,
+	ast.For,
+	ast.Foreach,
+	ast.Break,
+	ast.Continue,
+	ast.Delete,
+	ast.NodePreprocessorInclude,
+	ast.NodePreprocessorInline,
+}
+
+// statementStops are tokens where parsing of a block recovers from a syntax error.
+var statementStops = append(slices.Clone(statementKeywords), ast.RightBrace)
+
 type LLParser struct {
 	tokenizer  *tokenizer.Tokenizer
 	tokens     []ast.TerminalNode
 	tokenIndex int
+	errors     *context.DiagnosticContainer
+
+	// blockFollows is set when parsing condition before a block, where '{' starts the block but not a literal.
+	blockFollows bool
 }
 
 func NewLLParser(tokenizer *tokenizer.Tokenizer) *LLParser {
@@ -59,12 +100,16 @@ func (p *LLParser) Parse() (*ast.Document, error) {
 
 	p.tokens = tokens
 	p.tokenIndex = 0
-	program, err := p.parseProgram()
-	if err != nil {
-		return nil, err
+	p.errors = context.NewDiagnosticContainer(context.Error)
+	program := p.parseProgram()
+	program.Filename = p.tokenizer.Filename
+	program.AddTrailingComments(p.tokenizer.DanglingComments...)
+
+	// All syntax errors are reported, with a partial document in which failed parts are error nodes.
+	if p.errors.Count(context.Error) > 0 {
+		return program, p.errors
 	}
 
-	program.Filename = p.tokenizer.Filename
 	return program, nil
 }
 
@@ -98,6 +143,10 @@ func takeToken[T ast.TerminalNode](p *LLParser) T {
 	return token.(T)
 }
 
+func isToken(node ast.TerminalNode, t ast.TokenType) bool {
+	return node != nil && node.Type() == t
+}
+
 func (p *LLParser) takeToken() ast.TerminalNode {
 	token := p.currentToken()
 	if token != nil {
@@ -107,6 +156,32 @@ func (p *LLParser) takeToken() ast.TerminalNode {
 	return token
 }
 
+// liftComments attaches comments around tokens from index first to the last taken token onto node.
+func (p *LLParser) liftComments(node ast.Commentable, first int) {
+	ast.LiftComments(node, p.getToken(first), p.getToken(p.tokenIndex-1))
+}
+
+// onSameLine checks whether current token starts on the line where previous token ends. Newlines terminate
+// statements, so an operator on next line does not continue the expression.
+func (p *LLParser) onSameLine() bool {
+	prev, current := p.peekToken(-1), p.currentToken()
+	if prev == nil || current == nil {
+		return false
+	}
+
+	line, _ := prev.Context().Last()
+	_, currentLine, _ := current.Context().Position()
+	return line == currentLine
+}
+
+// skipSemicolon skips an optional semicolon at the end of a statement.
+func (p *LLParser) skipSemicolon() {
+	current := p.currentToken()
+	if current != nil && current.Type() == ast.Semicolon && p.onSameLine() {
+		p.takeToken()
+	}
+}
+
 func (p *LLParser) restoreToken() ast.TerminalNode {
 	if p.tokenIndex > 0 {
 		p.tokenIndex--
@@ -158,8 +233,72 @@ func (p *LLParser) expectToken(expectedTypes ...ast.TokenType) (ast.TerminalNode
 		node.Type(), ast.TokenTypeListString(expectedTypes))
 }
 
-func (p *LLParser) parseProgram() (*ast.Document, error) {
+// addError records a syntax error, an error passed up from nested blocks is recorded only once.
+func (p *LLParser) addError(err error) {
+	n := len(p.errors.Diagnostics)
+	if n > 0 && p.errors.Diagnostics[n-1] == err {
+		return
+	}
+
+	info, ok := err.(context.DiagnosticInfo)
+	if !ok {
+		info = context.NewError(p.tokenizer.EOFContext(), "%s", err)
+	}
+
+	_ = p.errors.Add(info)
+}
+
+// errorNode creates an error node of tokens from index first to the last taken token.
+func (p *LLParser) errorNode(first int) *ast.ErrorNode {
+	return ast.NewErrorNode(slices.Clone(p.tokens[first:p.tokenIndex]))
+}
+
+// synchronize skips tokens after a syntax error until a token in stops, at least one token is skipped to make
+// progress. Braces are skipped in pairs, tokens in skipped braces except declaration keywords do not stop.
+func (p *LLParser) synchronize(first int, stops []ast.TokenType) {
+	if p.tokenIndex == first {
+		p.takeToken()
+	}
+
+	depth := 0
+	for current := p.currentToken(); current != nil; current = p.currentToken() {
+		t := current.Type()
+		if slices.Contains(declarationKeywords, t) {
+			return
+		}
+
+		if depth == 0 && slices.Contains(stops, t) {
+			return
+		}
+
+		switch t {
+		case ast.LeftBrace:
+			depth++
+
+		case ast.RightBrace:
+			if depth > 0 {
+				depth--
+			}
+		}
+
+		p.takeToken()
+	}
+}
+
+// atDeclaration tells whether parsing reaches end of input or a top level declaration, where a block can not
+// recover from an error.
+func (p *LLParser) atDeclaration() bool {
+	current := p.currentToken()
+	return current == nil || slices.Contains(declarationKeywords, current.Type())
+}
+
+func (p *LLParser) parseProgram() *ast.Document {
 	declarations := make([]ast.Declaration, 0, 1000)
+	stops := []ast.TokenType{
+		ast.Global,
+		ast.NodePreprocessorInclude,
+		ast.NodePreprocessorInline,
+	}
 
 	for {
 		current := p.currentToken()
@@ -167,16 +306,19 @@ func (p *LLParser) parseProgram() (*ast.Document, error) {
 			break
 		}
 
+		first := p.tokenIndex
 		dec, err := p.parseDeclaration(current)
 		if err != nil {
-			return nil, err
+			p.addError(err)
+			p.synchronize(first, stops)
+			dec = p.errorNode(first)
 		}
+		p.liftComments(dec, first)
 
 		declarations = append(declarations, dec)
 	}
 
-	document := ast.NewDocument(declarations)
-	return document, nil
+	return ast.NewDocument(declarations)
 }
 
 func (p *LLParser) parseDeclaration(current ast.TerminalNode) (ast.Declaration, error) {
@@ -192,23 +334,239 @@ func (p *LLParser) parseDeclaration(current ast.TerminalNode) (ast.Declaration,
 	case ast.Function:
 		result, err = p.parseFunctionDeclaration()
 
+	case ast.Structure, ast.TypeDefine:
+		result, err = p.parseTypeDeclaration()
+
+	case ast.Global:
+		result, err = p.parseGlobalDeclaration(takeToken[*ast.TerminalToken](p))
+
+	case ast.Module, ast.Import:
+		result, err = p.parseModuleDeclaration()
+
+	case ast.Export:
+		result, err = p.parseExportDeclaration()
+
 	default:
-		err = current.Context().Error("unexpected token: %s, expect a fun keyword or a preprocessor directive", current.Type().String())
+		err = current.Context().Error("unexpected token: %s, expect a fun keyword, a type or global declaration or a preprocessor directive", current.Type().String())
 	}
 
 	return result, err
 }
 
+// parseModuleDeclaration parses 'module name' and 'import name'.
+func (p *LLParser) parseModuleDeclaration() (ast.Declaration, error) {
+	keyword := takeToken[*ast.TerminalToken](p)
+	name, err := p.expectToken(ast.IdentifierName)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSemicolon()
+	if keyword.Token == ast.Module {
+		return ast.NewModuleDeclaration(keyword, name.(*ast.Identifier)), nil
+	}
+
+	return ast.NewImportDeclaration(keyword, name.(*ast.Identifier)), nil
+}
+
+// parseExportDeclaration parses a function or global variable with 'export' keyword.
+func (p *LLParser) parseExportDeclaration() (ast.Declaration, error) {
+	export := takeToken[*ast.TerminalToken](p)
+
+	current := p.currentToken()
+	if current == nil {
+		ctx := p.tokenizer.EOFContext()
+		return nil, ctx.Error("unexpected end of input, expect a function or global variable to export")
+	}
+
+	switch current.Type() {
+	case ast.Function:
+		decl, err := p.parseFunctionDeclaration()
+		if err != nil {
+			return nil, err
+		}
+
+		f := decl.(*ast.FunctionDeclaration)
+		f.Export = export
+		return f, nil
+
+	case ast.Global:
+		decl, err := p.parseGlobalDeclaration(takeToken[*ast.TerminalToken](p))
+		if err != nil {
+			return nil, err
+		}
+
+		decl.Export = export
+		return decl, nil
+	}
+
+	return nil, current.Context().Error("unexpected token: %s, expect a function or global variable to export", current.Type().String())
+}
+
+// parseGlobalDeclaration parses 'global var name type = value', which declares a global variable in document, or
+// declares access to a global variable in function.
+func (p *LLParser) parseGlobalDeclaration(keyword *ast.TerminalToken) (*ast.GlobalDeclaration, error) {
+	varKeyword, err := p.expectTerminalToken(ast.Var)
+	if err != nil {
+		return nil, err
+	}
+
+	variable, err := p.parseVariableDeclaration(varKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewGlobalDeclaration(keyword, variable.(*ast.VariableDeclaration)), nil
+}
+
+func (p *LLParser) parseTypeDeclaration() (ast.Declaration, error) {
+	keyword := takeToken[*ast.TerminalToken](p)
+
+	name, err := p.expectToken(ast.IdentifierName)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition ast.Type
+	if keyword.Token == ast.Structure {
+		definition, err = p.parseStructBody(nil)
+
+	} else if current := p.currentToken(); current != nil && current.Type() == ast.Structure {
+		definition, err = p.parseStructBody(takeToken[*ast.TerminalToken](p))
+
+	} else {
+		definition, err = p.parseSimpleType()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewTypeDeclaration(keyword, name.(*ast.Identifier), definition), nil
+}
+
+// parseStructBody parses fields in braces, each field is a name and a type, separated by newlines or semicolons.
+func (p *LLParser) parseStructBody(keyword *ast.TerminalToken) (*ast.StructType, error) {
+	lBrace, err := p.expectTerminalToken(ast.LeftBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ast.NewStructType(keyword, lBrace)
+	for {
+		current := p.currentToken()
+		if current == nil {
+			ctx := p.tokenizer.EOFContext()
+			return nil, ctx.Error("unexpected end of input, expect '}' to close struct")
+		}
+
+		if current.Type() == ast.RightBrace {
+			result.RBrace = takeToken[*ast.TerminalToken](p)
+			return result, nil
+		}
+
+		first := p.tokenIndex
+		name, err := p.expectToken(ast.IdentifierName)
+		if err != nil {
+			return nil, err
+		}
+
+		typ, err := p.parseDataType()
+		if err != nil {
+			return nil, err
+		}
+
+		field := ast.NewFieldDeclaration(name.(*ast.Identifier), typ)
+		p.skipSemicolon()
+		p.liftComments(field, first)
+		result.AddField(field)
+	}
+}
+
+// parseFunctionGlobals parses global variables used by function, in brackets or parentheses after 'with'.
+func (p *LLParser) parseFunctionGlobals(f *ast.FunctionDeclaration, closing ast.TokenType) error {
+	f.LGlobals = takeToken[*ast.TerminalToken](p)
+	globals, err := p.parseArgumentList()
+	if err != nil {
+		return err
+	}
+
+	rGlobals, err := p.expectTerminalToken(closing)
+	if err != nil {
+		return err
+	}
+
+	f.Globals = globals
+	f.RGlobals = rGlobals
+	return nil
+}
+
+// parseFunctionReceiver parses receiver of an instance method like '(p *Point)', if any.
+func (p *LLParser) parseFunctionReceiver(f *ast.FunctionDeclaration) error {
+	current := p.currentToken()
+	if current == nil || current.Type() != ast.LeftParen {
+		return nil
+	}
+
+	lParen := takeToken[*ast.TerminalToken](p)
+	if _, err := p.expectToken(ast.IdentifierName); err != nil {
+		return err
+	}
+
+	p.restoreToken()
+	receiver, err := p.parseArgument()
+	if err != nil {
+		return err
+	}
+
+	if receiver.Comma != nil {
+		return receiver.Comma.Context().Error("method has multiple receivers").
+			With("SHALL be one receiver")
+	}
+
+	rParen, err := p.expectTerminalToken(ast.RightParen)
+	if err != nil {
+		return err
+	}
+
+	f.LParenReceiver = lParen
+	f.Receiver = receiver
+	f.RParenReceiver = rParen
+	return nil
+}
+
 func (p *LLParser) parseFunctionDeclaration() (ast.Declaration, error) {
 	keyword := p.takeToken().(*ast.TerminalToken)
 	result := ast.NewFunctionDeclaration(keyword)
 
+	if current := p.currentToken(); current != nil && current.Type() == ast.LeftBracket {
+		// fun [g int]f() { ... }
+		if err := p.parseFunctionGlobals(result, ast.RightBracket); err != nil {
+			return nil, err
+		}
+
+	} else if err := p.parseFunctionReceiver(result); err != nil {
+		return nil, err
+	}
+
 	name, err := p.expectToken(ast.IdentifierName)
 	if err != nil {
 		return nil, err
 	}
 	result.Name = name.(*ast.Identifier)
 
+	if current := p.currentToken(); result.Receiver == nil && current != nil && current.Type() == ast.Period {
+		// fun Point.New() { ... }
+		result.TypeName = result.Name
+		result.Dot = takeToken[*ast.TerminalToken](p)
+
+		name, err := p.expectToken(ast.IdentifierName)
+		if err != nil {
+			return nil, err
+		}
+		result.Name = name.(*ast.Identifier)
+	}
+
 	lParenArgs, err := p.expectToken(ast.LeftParen)
 	if err != nil {
 		return nil, err
@@ -239,6 +597,21 @@ func (p *LLParser) parseFunctionDeclaration() (ast.Declaration, error) {
 		result.RParenArgs = rParenArgs.(*ast.TerminalToken)
 	}
 
+	if current, ok := p.currentToken().(*ast.Identifier); ok && current.Name == ast.WithKeyword {
+		if next := p.peekToken(1); next != nil && next.Type() == ast.LeftParen {
+			// fun f() with(g int) { ... }
+			if result.Globals != nil {
+				return nil, current.Context().Error("global variables are declared twice").
+					With("remove either brackets or 'with'")
+			}
+
+			result.With = takeToken[*ast.Identifier](p)
+			if err := p.parseFunctionGlobals(result, ast.RightParen); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	lParanOrBrace, err := p.expectToken(ast.LeftParen, ast.LeftBrace)
 	if err != nil {
 		return nil, err
@@ -249,7 +622,7 @@ func (p *LLParser) parseFunctionDeclaration() (ast.Declaration, error) {
 	if lParanOrBrace.Type() == ast.LeftParen {
 		result.LParenReturnTypes = lParanOrBrace.(*ast.TerminalToken)
 
-		typeLead, err := p.expectToken(ast.RightParen, ast.IdentifierName)
+		typeLead, err := p.expectToken(ast.RightParen, ast.IdentifierName, ast.Asterisk)
 		if err != nil {
 			return nil, err
 		}
@@ -258,7 +631,7 @@ func (p *LLParser) parseFunctionDeclaration() (ast.Declaration, error) {
 		case ast.RightParen:
 			result.RParenReturnTypes = typeLead.(*ast.TerminalToken)
 
-		case ast.IdentifierName:
+		case ast.IdentifierName, ast.Asterisk:
 			p.restoreToken()
 			types, err := p.parseTypeList()
 			if err != nil {
@@ -286,27 +659,65 @@ func (p *LLParser) parseFunctionDeclaration() (ast.Declaration, error) {
 
 	result.LBrace = lBrace
 
+	statements, rBrace, err := p.parseStatementsUntilRBrace("function body")
+	if err != nil {
+		return nil, err
+	}
+	result.Statements = statements
+	result.RBrace = rBrace
+
+	return result, nil
+}
+
+// parseStatementsUntilRBrace parses statements after '{' until the closing '}', which is taken and returned.
+func (p *LLParser) parseStatementsUntilRBrace(what string) ([]ast.Statement, *ast.TerminalToken, error) {
+	statements := make([]ast.Statement, 0, 10)
 	for {
 		current := p.currentToken()
 		if current == nil {
 			ctx := p.tokenizer.EOFContext()
-			return nil, ctx.Error("unexpected end of input, expect '}' to close function body")
+			return nil, nil, ctx.Error("unexpected end of input, expect '}' to close %s", what)
 		}
 
 		if current.Type() == ast.RightBrace {
-			result.RBrace = p.takeToken().(*ast.TerminalToken)
-			break
+			return statements, p.takeToken().(*ast.TerminalToken), nil
+		}
+
+		if slices.Contains(declarationKeywords, current.Type()) {
+			return nil, nil, current.Context().Error("unexpected token '%s' in statement", current.Type().String()).
+				With("missing '}' to close %s", what)
 		}
 
+		first := p.tokenIndex
 		stmt, err := p.parseStatement(current)
 		if err != nil {
-			return nil, err
+			p.addError(err)
+			if p.atDeclaration() {
+				return nil, nil, err
+			}
+
+			p.synchronize(first, statementStops)
+			stmt = p.errorNode(first)
 		}
+		p.skipSemicolon()
+		p.liftComments(stmt, first)
+
+		statements = append(statements, stmt)
+	}
+}
 
-		result.Statements = append(result.Statements, stmt)
+func (p *LLParser) parseBlockStatement() (*ast.BlockStatement, error) {
+	lBrace, err := p.expectTerminalToken(ast.LeftBrace)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	statements, rBrace, err := p.parseStatementsUntilRBrace("block")
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewBlockStatement(lBrace, statements, rBrace), nil
 }
 
 func (p *LLParser) parseStatement(start ast.TerminalNode) (ast.Statement, error) {
@@ -316,6 +727,36 @@ func (p *LLParser) parseStatement(start ast.TerminalNode) (ast.Statement, error)
 	case ast.Return:
 		return p.parseReturn(start.(*ast.TerminalToken))
 
+	case ast.Var, ast.Const:
+		return p.parseVariableDeclaration(start.(*ast.TerminalToken))
+
+	case ast.Ref:
+		return p.parseReferenceDeclaration(start.(*ast.TerminalToken))
+
+	case ast.Delete:
+		return p.parseDeleteStatement(start.(*ast.TerminalToken))
+
+	case ast.Global:
+		return p.parseGlobalDeclaration(start.(*ast.TerminalToken))
+
+	case ast.If:
+		return p.parseIfStatement(start.(*ast.TerminalToken))
+
+	case ast.While:
+		return p.parseWhileStatement(start.(*ast.TerminalToken))
+
+	case ast.Do:
+		return p.parseDoWhileStatement(start.(*ast.TerminalToken))
+
+	case ast.For:
+		return p.parseForStatement(start.(*ast.TerminalToken))
+
+	case ast.Foreach:
+		return p.parseForeachStatement(start.(*ast.TerminalToken))
+
+	case ast.Break, ast.Continue:
+		return ast.NewLoopControlStatement(start.(*ast.TerminalToken)), nil
+
 	case ast.NodePreprocessorInclude:
 		return start.(*ast.PreprocessorInclude), nil
 
@@ -323,59 +764,497 @@ func (p *LLParser) parseStatement(start ast.TerminalNode) (ast.Statement, error)
 		return start.(*ast.PreprocessorInline), nil
 
 	default:
+		if inExpressionFirstSet(start.Type()) {
+			p.restoreToken()
+			return p.parseExpressionStatement()
+		}
+
 		return nil, start.Context().Error("unexpected token '%s' in statement", start.Type().String())
 	}
 }
 
-func (p *LLParser) parseReturn(keyword *ast.TerminalToken) (ast.Statement, error) {
-	result := ast.NewReturnStatement(keyword)
-
-	returnList, err := p.parseExpressionList()
+// parseExpressionStatement parses statements starting with an expression list, the token after the list decides
+// the kind of statement.
+func (p *LLParser) parseExpressionStatement() (ast.Statement, error) {
+	list, err := p.parseExpressionList()
 	if err != nil {
 		return nil, err
 	}
-	result.Value = returnList
 
-	return result, nil
-}
+	current := p.currentToken()
+	if current != nil && p.onSameLine() && current.Type() == ast.InferenceAssign {
+		return p.parseInferenceDeclaration(list)
+	}
 
-func (p *LLParser) parseArgument() (*ast.ArgumentDeclaration, error) {
-	argName := takeToken[*ast.Identifier](p)
+	if current != nil && p.onSameLine() && slices.Contains(assignmentOperators, current.Type()) {
+		return p.parseAssignmentStatement(list)
+	}
 
-	typeLead := p.currentToken()
-	if typeLead == nil {
-		ctx := p.tokenizer.EOFContext()
-		return nil, ctx.Error("unexpected EOF, expect argument type")
+	if list.Length() > 1 {
+		return nil, list.Context().Error("expression list is not a statement").With("expect '=' or ':=' after names")
 	}
 
-	var err error
-	var typeNode ast.Type
+	if current != nil && p.onSameLine() && (current.Type() == ast.Increment || current.Type() == ast.Decrement) {
+		return p.parseIncrementStatement(list.Expressions[0].Expression)
+	}
 
-	switch typeLead.Type() {
-	case ast.Asterisk:
-		typeNode, err = p.parseSimpleType()
+	expr := list.Expressions[0].Expression
+	if _, ok := expr.(*ast.CallExpression); !ok {
+		return nil, expr.Context().Error("expression is evaluated but not used").With("only function call can be a statement")
+	}
 
-	case ast.IdentifierName:
-		typeNode, err = p.parseSimpleType()
+	return ast.NewExpressionStatement(expr), nil
+}
 
-	default:
-		err = typeLead.Context().Error("unexpected token '%s', expect argument type", typeLead.Type().String())
+func (p *LLParser) parseIncrementStatement(target ast.Expression) (ast.Statement, error) {
+	operator := takeToken[*ast.TerminalToken](p)
+	if !ast.IsAssignable(target) {
+		return nil, target.Context().Error("cannot apply '%s' to expression", operator.Token).
+			With("expect a variable, dereference, member or element")
 	}
 
-	if err != nil {
-		return nil, err
+	return ast.NewIncrementStatement(target, operator), nil
+}
+
+func (p *LLParser) parseAssignmentStatement(targets *ast.ExpressionList) (ast.Statement, error) {
+	for _, item := range targets.Expressions {
+		if !ast.IsAssignable(item.Expression) {
+			return nil, item.Expression.Context().Error("cannot assign to expression").With("expect a variable, dereference, member or element")
+		}
 	}
 
-	var argComma *ast.TerminalToken = nil
-	last := p.currentToken()
-	if last != nil && last.Type() == ast.Comma {
-		comma := takeToken[*ast.TerminalToken](p)
-		argComma = comma
+	operator := takeToken[*ast.TerminalToken](p)
+	if operator.Type() != ast.Assign && targets.Length() > 1 {
+		return nil, operator.Context().Error("compound assignment '%s' requires a single target", operator.Type().String()).
+			With("use '=' for multiple targets")
 	}
 
-	arg := ast.NewArgumentDeclaration(argName, typeNode, argComma)
-	return arg, nil
-}
+	if !p.onSameLine() {
+		return nil, operator.Context().NextInLineContext().Error("missing value after '%s'", operator.Type().String()).With("expect expression")
+	}
+
+	values, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+
+	if values.Length() <= 0 {
+		current := p.currentToken()
+		return nil, current.Context().Error("unexpected token '%s', expect expression", current.Type().String())
+	}
+
+	return ast.NewAssignmentStatement(targets, operator, values), nil
+}
+
+func (p *LLParser) parseInferenceDeclaration(names *ast.ExpressionList) (ast.Statement, error) {
+	for _, item := range names.Expressions {
+		if _, ok := item.Expression.(*ast.Identifier); !ok {
+			return nil, item.Expression.Context().Error("non-name on left side of ':='").With("expect an identifier")
+		}
+	}
+
+	assign := takeToken[*ast.TerminalToken](p)
+	if !p.onSameLine() {
+		return nil, assign.Context().NextInLineContext().Error("missing value after ':='").With("expect expression")
+	}
+
+	values, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+
+	if values.Length() <= 0 {
+		current := p.currentToken()
+		return nil, current.Context().Error("unexpected token '%s', expect expression", current.Type().String())
+	}
+
+	return ast.NewInferenceDeclaration(names, assign, values), nil
+}
+
+func (p *LLParser) parseType() (ast.Type, error) {
+	current := p.currentToken()
+	if current != nil && current.Type() == ast.Auto {
+		return ast.NewAutoType(takeToken[*ast.TerminalToken](p)), nil
+	}
+
+	return p.parseDataType()
+}
+
+// parseDataType parses a simple type, a fixed size array type if '[' follows, or a list type if '[]' follows.
+func (p *LLParser) parseDataType() (ast.Type, error) {
+	element, err := p.parseSimpleType()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isToken(p.currentToken(), ast.LeftBracket) || !p.onSameLine() {
+		return element, nil
+	}
+
+	if isToken(p.peekToken(1), ast.RightBracket) {
+		lBracket := takeToken[*ast.TerminalToken](p)
+		rBracket := takeToken[*ast.TerminalToken](p)
+		return ast.NewListType(element, lBracket, rBracket), nil
+	}
+
+	return p.parseArrayType(element)
+}
+
+// parseArrayType parses size of array in brackets after element type, size is optional and checked later.
+func (p *LLParser) parseArrayType(element *ast.SimpleType) (*ast.ArrayType, error) {
+	defer p.enclosed()()
+	lBracket := takeToken[*ast.TerminalToken](p)
+
+	var size ast.Expression
+	if current := p.currentToken(); current != nil && current.Type() != ast.RightBracket {
+		expr, err := p.parseExpression(PrecedenceLowest)
+		if err != nil {
+			return nil, err
+		}
+		size = expr
+	}
+
+	rBracket, err := p.expectTerminalToken(ast.RightBracket)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewArrayType(element, lBracket, size, rBracket), nil
+}
+
+func (p *LLParser) parseVariableDeclaration(keyword *ast.TerminalToken) (ast.Statement, error) {
+	var ref *ast.TerminalToken
+	if keyword.Type() != ast.Ref && isToken(p.currentToken(), ast.Ref) {
+		ref = takeToken[*ast.TerminalToken](p)
+	}
+
+	name, err := p.expectToken(ast.IdentifierName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ast.NewVariableDeclaration(keyword, name.(*ast.Identifier))
+	result.Ref = ref
+
+	current := p.currentToken()
+	if current == nil || !p.onSameLine() {
+		return result, nil
+	}
+
+	if current.Type() != ast.Assign {
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		result.Type = typ
+
+		current = p.currentToken()
+		if current == nil || !p.onSameLine() || current.Type() != ast.Assign {
+			return result, nil
+		}
+	}
+
+	result.Assign = takeToken[*ast.TerminalToken](p)
+	if !p.onSameLine() {
+		return nil, result.Assign.Context().NextInLineContext().Error("missing value after '='").With("expect expression")
+	}
+
+	value, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+	result.Value = value
+
+	return result, nil
+}
+
+// parseReferenceDeclaration parses declarations starting with 'ref', in form of 'ref p *T = v' or 'ref a, b := x, y'.
+func (p *LLParser) parseReferenceDeclaration(keyword *ast.TerminalToken) (ast.Statement, error) {
+	if isToken(p.peekToken(1), ast.InferenceAssign) || isToken(p.peekToken(1), ast.Comma) {
+		stmt, err := p.parseExpressionStatement()
+		if err != nil {
+			return nil, err
+		}
+
+		d, ok := stmt.(*ast.InferenceDeclaration)
+		if !ok {
+			return nil, stmt.Context().Error("unexpected statement after 'ref'").With("expect declaration by ':='")
+		}
+
+		d.Ref = keyword
+		return d, nil
+	}
+
+	return p.parseVariableDeclaration(keyword)
+}
+
+func (p *LLParser) parseDeleteStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	if !p.onSameLine() {
+		return nil, keyword.Context().NextInLineContext().Error("missing pointer after 'delete'").With("expect expression")
+	}
+
+	target, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ast.IsAssignable(target) {
+		return nil, target.Context().Error("cannot delete expression").With("expect a variable, dereference, member or element")
+	}
+
+	return ast.NewDeleteStatement(keyword, target), nil
+}
+
+func (p *LLParser) parseConditionalBranch(keyword *ast.TerminalToken) (*ast.ConditionalBranch, error) {
+	current := p.currentToken()
+	if current == nil || current.Type() == ast.LeftBrace {
+		return nil, keyword.Context().NextInLineContext().Error("missing condition in '%s' statement", keyword.Token).
+			With("condition required")
+	}
+
+	p.blockFollows = true
+	condition, err := p.parseExpression(PrecedenceLowest)
+	p.blockFollows = false
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseBlockStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewConditionalBranch(keyword, condition, body), nil
+}
+
+func (p *LLParser) parseIfStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	branch, err := p.parseConditionalBranch(keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ast.NewIfStatement(branch)
+	for {
+		current := p.currentToken()
+		if current == nil {
+			return result, nil
+		}
+
+		switch current.Type() {
+		case ast.Elif:
+			elif, err := p.parseConditionalBranch(p.takeToken().(*ast.TerminalToken))
+			if err != nil {
+				return nil, err
+			}
+			result.AddElif(elif)
+
+		case ast.Else:
+			elseKeyword := p.takeToken().(*ast.TerminalToken)
+			body, err := p.parseBlockStatement()
+			if err != nil {
+				return nil, err
+			}
+			result.SetElse(elseKeyword, body)
+			return result, nil
+
+		default:
+			return result, nil
+		}
+	}
+}
+
+func (p *LLParser) parseWhileStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	branch, err := p.parseConditionalBranch(keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewWhileStatement(branch.Keyword, branch.Condition, branch.Body), nil
+}
+
+func (p *LLParser) parseDoWhileStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	body, err := p.parseBlockStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	while, err := p.expectTerminalToken(ast.While)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.onSameLine() {
+		return nil, while.Context().NextInLineContext().Error("missing condition in 'do-while' statement").
+			With("condition required")
+	}
+
+	condition, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewDoWhileStatement(keyword, body, while, condition), nil
+}
+
+// parseSimpleStatement parses statements allowed in header of for loop.
+func (p *LLParser) parseSimpleStatement() (ast.Statement, error) {
+	current := p.currentToken()
+	if current == nil {
+		ctx := p.tokenizer.EOFContext()
+		return nil, ctx.Error("unexpected end of input, expect a statement")
+	}
+
+	if current.Type() == ast.Var {
+		return p.parseVariableDeclaration(p.takeToken().(*ast.TerminalToken))
+	}
+
+	if inExpressionFirstSet(current.Type()) {
+		return p.parseExpressionStatement()
+	}
+
+	return nil, current.Context().Error("unexpected token '%s' in for loop header", current.Type().String()).
+		With("expect a declaration, assignment or increment")
+}
+
+func (p *LLParser) parseForStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	result := ast.NewForStatement(keyword)
+
+	lParen, err := p.expectTerminalToken(ast.LeftParen)
+	if err != nil {
+		return nil, err
+	}
+	result.LParen = lParen
+
+	if current := p.currentToken(); current != nil && current.Type() != ast.Semicolon {
+		init, err := p.parseSimpleStatement()
+		if err != nil {
+			return nil, err
+		}
+		result.Initializer = init
+	}
+
+	if result.FirstSemicolon, err = p.expectTerminalToken(ast.Semicolon); err != nil {
+		return nil, err
+	}
+
+	if current := p.currentToken(); current != nil && current.Type() != ast.Semicolon {
+		condition, err := p.parseExpression(PrecedenceLowest)
+		if err != nil {
+			return nil, err
+		}
+		result.Condition = condition
+	}
+
+	if result.LastSemicolon, err = p.expectTerminalToken(ast.Semicolon); err != nil {
+		return nil, err
+	}
+
+	if current := p.currentToken(); current != nil && current.Type() != ast.RightParen {
+		post, err := p.parseSimpleStatement()
+		if err != nil {
+			return nil, err
+		}
+		result.Post = post
+	}
+
+	if result.RParen, err = p.expectTerminalToken(ast.RightParen); err != nil {
+		return nil, err
+	}
+
+	if result.Body, err = p.parseBlockStatement(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *LLParser) parseForeachStatement(keyword *ast.TerminalToken) (ast.Statement, error) {
+	result := ast.NewForeachStatement(keyword)
+
+	lParen, err := p.expectTerminalToken(ast.LeftParen)
+	if err != nil {
+		return nil, err
+	}
+	result.LParen = lParen
+
+	variable, err := p.expectToken(ast.IdentifierName)
+	if err != nil {
+		return nil, err
+	}
+	result.Variable = variable.(*ast.Identifier)
+
+	if result.In, err = p.expectTerminalToken(ast.In); err != nil {
+		return nil, err
+	}
+
+	if result.Iterable, err = p.parseExpression(PrecedenceLowest); err != nil {
+		return nil, err
+	}
+
+	if result.RParen, err = p.expectTerminalToken(ast.RightParen); err != nil {
+		return nil, err
+	}
+
+	if result.Body, err = p.parseBlockStatement(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *LLParser) parseReturn(keyword *ast.TerminalToken) (ast.Statement, error) {
+	result := ast.NewReturnStatement(keyword)
+	if !p.onSameLine() {
+		result.Value = ast.NewExpressionList()
+		return result, nil
+	}
+
+	returnList, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+	result.Value = returnList
+
+	return result, nil
+}
+
+func (p *LLParser) parseArgument() (*ast.ArgumentDeclaration, error) {
+	argName := takeToken[*ast.Identifier](p)
+
+	typeLead := p.currentToken()
+	if typeLead == nil {
+		ctx := p.tokenizer.EOFContext()
+		return nil, ctx.Error("unexpected EOF, expect argument type")
+	}
+
+	var err error
+	var typeNode ast.Type
+
+	switch typeLead.Type() {
+	case ast.Asterisk:
+		typeNode, err = p.parseDataType()
+
+	case ast.IdentifierName:
+		typeNode, err = p.parseDataType()
+
+	default:
+		err = typeLead.Context().Error("unexpected token '%s', expect argument type", typeLead.Type().String())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var argComma *ast.TerminalToken = nil
+	last := p.currentToken()
+	if last != nil && last.Type() == ast.Comma {
+		comma := takeToken[*ast.TerminalToken](p)
+		argComma = comma
+	}
+
+	arg := ast.NewArgumentDeclaration(argName, typeNode, argComma)
+	return arg, nil
+}
 
 func (p *LLParser) parseSimpleType() (*ast.SimpleType, error) {
 	asterisks := make([]*ast.TerminalToken, 0, 2)
@@ -413,7 +1292,7 @@ func (p *LLParser) parseArgumentList() (*ast.ArgumentList, error) {
 		}
 
 		switch current.Type() {
-		case ast.RightParen:
+		case ast.RightParen, ast.RightBracket:
 			return args, nil
 
 		case ast.IdentifierName:
@@ -492,6 +1371,7 @@ func (p *LLParser) parseExpressionList() (*ast.ExpressionList, error) {
 
 		comma, _ := p.expectTerminalToken(ast.Comma)
 		list.Add(expr, comma)
+		finished = comma == nil
 	}
 
 	return list, nil
@@ -509,15 +1389,53 @@ func (p *LLParser) parseExpression(precedence Precedence) (ast.Expression, error
 
 	switch currrent.Type() {
 	case ast.IdentifierName:
-		identifier := takeToken[*ast.Identifier](p)
-		result = identifier
+		if p.isArrayLiteral() {
+			result, err = p.parseArrayLiteral()
+
+		} else if p.isStructLiteral() {
+			result, err = p.parseStructLiteral()
+
+		} else {
+			identifier := takeToken[*ast.Identifier](p)
+			result = identifier
+		}
 
 	case ast.Integer:
 		literal := takeToken[*ast.IntegerLiteral](p)
 		result = literal
 
+	case ast.Float:
+		literal := takeToken[*ast.FloatLiteral](p)
+		result = literal
+
+	case ast.String:
+		literal := takeToken[*ast.StringLiteral](p)
+		result = literal
+
+	case ast.True, ast.False:
+		literal := takeToken[*ast.BooleanLiteral](p)
+		result = literal
+
+	case ast.Null:
+		literal := takeToken[*ast.NullLiteral](p)
+		result = literal
+
+	case ast.LeftParen:
+		result, err = p.parseParenthesizedExpression()
+
+	case ast.New:
+		result, err = p.parseNewExpression()
+
+	case ast.Sizeof:
+		result, err = p.parseSizeofExpression()
+
 	default:
-		err = currrent.Context().Error("unexpected token '%s' in expression", currrent.Type().String())
+		if slices.Contains(prefixOperators, currrent.Type()) {
+			result, err = p.parsePrefixExpression()
+
+		} else {
+			err = currrent.Context().Error("unexpected token '%s' in expression", currrent.Type().String())
+		}
 	}
 
 	if err != nil {
@@ -530,14 +1448,26 @@ func (p *LLParser) parseExpression(precedence Precedence) (ast.Expression, error
 func (p *LLParser) parseComplexExpression(first ast.Expression, precedence Precedence) (ast.Expression, error) {
 	current := p.currentToken()
 	currentPrecedence := GetPrecedence(current)
-	if current == nil || currentPrecedence <= precedence {
+	if current == nil || currentPrecedence <= precedence || !p.onSameLine() {
 		return first, nil
 	}
 
 	var expr ast.Expression
 	var err error
 	switch current.Type() {
-	case ast.Plus, ast.Sub:
+	case ast.LeftParen:
+		expr, err = p.parseCallExpression(first)
+
+	case ast.LeftBracket:
+		expr, err = p.parseIndexExpression(first)
+
+	case ast.Period:
+		expr, err = p.parseMemberExpression(first)
+
+	case ast.PointerAdd, ast.PointerSub:
+		expr, err = p.parsePointerArithmeticExpression(first)
+
+	default:
 		expr, err = p.parseInfixExpression(first, precedence)
 	}
 
@@ -548,6 +1478,326 @@ func (p *LLParser) parseComplexExpression(first ast.Expression, precedence Prece
 	return p.parseComplexExpression(expr, precedence)
 }
 
+// enclosed clears blockFollows while parsing in parentheses or brackets, returns a function to restore it.
+func (p *LLParser) enclosed() func() {
+	saved := p.blockFollows
+	p.blockFollows = false
+	return func() {
+		p.blockFollows = saved
+	}
+}
+
+// isArrayLiteral looks ahead for 'T[]{' or 'T[N]{'. The latter is an index expression followed by a block in
+// conditions.
+func (p *LLParser) isArrayLiteral() bool {
+	if !isToken(p.peekToken(1), ast.LeftBracket) {
+		return false
+	}
+
+	if isToken(p.peekToken(2), ast.RightBracket) {
+		return isToken(p.peekToken(3), ast.LeftBrace)
+	}
+
+	if p.blockFollows {
+		return false
+	}
+
+	depth := 0
+	for i := 1; ; i++ {
+		token := p.peekToken(i)
+		if token == nil {
+			return false
+		}
+
+		switch token.Type() {
+		case ast.LeftBracket:
+			depth++
+
+		case ast.RightBracket:
+			depth--
+			if depth == 0 {
+				return isToken(p.peekToken(i+1), ast.LeftBrace)
+			}
+		}
+	}
+}
+
+// isStructLiteral looks ahead for 'T{}' or 'T{x:' in the same line, which is a block after condition if
+// blockFollows is set, like 'if ready {}'.
+func (p *LLParser) isStructLiteral() bool {
+	if p.blockFollows || !isToken(p.peekToken(1), ast.LeftBrace) {
+		return false
+	}
+
+	line, _ := p.currentToken().Context().Last()
+	if _, braceLine, _ := p.peekToken(1).Context().Position(); braceLine != line {
+		return false
+	}
+
+	if isToken(p.peekToken(2), ast.RightBrace) {
+		return true
+	}
+
+	return isToken(p.peekToken(2), ast.IdentifierName) && isToken(p.peekToken(3), ast.Colon)
+}
+
+func (p *LLParser) parseArrayLiteral() (ast.Expression, error) {
+	defer p.enclosed()()
+	element := ast.NewSimpleType(nil, takeToken[*ast.Identifier](p))
+	typ, err := p.parseArrayType(element)
+	if err != nil {
+		return nil, err
+	}
+
+	lBrace, err := p.expectTerminalToken(ast.LeftBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+
+	rBrace, err := p.expectTerminalToken(ast.RightBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewArrayLiteral(typ, lBrace, elements, rBrace), nil
+}
+
+// parseNewExpression parses allocation in heap, in forms of list literal 'new T[]{...}', struct literal
+// 'new T{...}' and construction 'new T(...)'.
+func (p *LLParser) parseNewExpression() (ast.Expression, error) {
+	keyword := takeToken[*ast.TerminalToken](p)
+
+	current := p.currentToken()
+	if current == nil {
+		return nil, p.tokenizer.EOFContext().Error("unexpected EOF, expect type after 'new'")
+	}
+
+	if current.Type() != ast.IdentifierName {
+		return nil, current.Context().Error("unexpected token '%s', expect type after 'new'", current.Type().String())
+	}
+
+	var value ast.Expression
+	var err error
+	switch next := p.peekToken(1); {
+	case isToken(next, ast.LeftBracket):
+		value, err = p.parseArrayLiteral()
+
+	case isToken(next, ast.LeftBrace):
+		value, err = p.parseStructLiteral()
+
+	case isToken(next, ast.LeftParen):
+		value, err = p.parseCallExpression(takeToken[*ast.Identifier](p))
+
+	default:
+		err = current.Context().NextInLineContext().Error("missing value of '%s'", current.(*ast.Identifier).Name).
+			With("expect '(', '{' or '[' after type")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewNewExpression(keyword, value), nil
+}
+
+// parseSizeofExpression parses 'sizeof(T)' and 'sizeof(expr)'. Operand is parsed as a type if it is in form of a
+// type, variables like 'p' in 'sizeof(*p)' are told from types by checker.
+func (p *LLParser) parseSizeofExpression() (ast.Expression, error) {
+	keyword := takeToken[*ast.TerminalToken](p)
+	lParen, err := p.expectTerminalToken(ast.LeftParen)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := p.enclosed()
+	var operand ast.Node
+	if p.isSizeofType() {
+		operand, err = p.parseDataType()
+
+	} else {
+		operand, err = p.parseExpression(PrecedenceLowest)
+	}
+	restore()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rParen, err := p.expectTerminalToken(ast.RightParen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewSizeofExpression(keyword, lParen, operand, rParen), nil
+}
+
+// isSizeofType looks ahead for a type closed by ')', in forms of '*T', 'T[]' and 'T[N]'.
+func (p *LLParser) isSizeofType() bool {
+	i := 0
+	for isToken(p.peekToken(i), ast.Asterisk) {
+		i++
+	}
+
+	if !isToken(p.peekToken(i), ast.IdentifierName) {
+		return false
+	}
+
+	i++
+	if isToken(p.peekToken(i), ast.LeftBracket) {
+		depth := 0
+		for ; ; i++ {
+			token := p.peekToken(i)
+			if token == nil {
+				return false
+			}
+
+			if token.Type() == ast.LeftBracket {
+				depth++
+
+			} else if token.Type() == ast.RightBracket {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+
+		i++
+	}
+
+	return isToken(p.peekToken(i), ast.RightParen)
+}
+
+// parseStructLiteral parses fields of struct literal in form of 'T{x: 1, y: 2}', fields may be in multiple lines.
+func (p *LLParser) parseStructLiteral() (ast.Expression, error) {
+	defer p.enclosed()()
+	typ := ast.NewSimpleType(nil, takeToken[*ast.Identifier](p))
+	lBrace := takeToken[*ast.TerminalToken](p)
+
+	fields := make([]*ast.FieldValue, 0, 4)
+	for isToken(p.currentToken(), ast.IdentifierName) {
+		name := takeToken[*ast.Identifier](p)
+		colon, err := p.expectTerminalToken(ast.Colon)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseExpression(PrecedenceLowest)
+		if err != nil {
+			return nil, err
+		}
+
+		comma, _ := p.expectTerminalToken(ast.Comma)
+		fields = append(fields, ast.NewFieldValue(name, colon, value, comma))
+		if comma == nil {
+			break
+		}
+	}
+
+	rBrace, err := p.expectTerminalToken(ast.RightBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewStructLiteral(typ, lBrace, fields, rBrace), nil
+}
+
+func (p *LLParser) parseIndexExpression(object ast.Expression) (ast.Expression, error) {
+	defer p.enclosed()()
+	lBracket := takeToken[*ast.TerminalToken](p)
+
+	index, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	rBracket, err := p.expectTerminalToken(ast.RightBracket)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewIndexExpression(object, lBracket, index, rBracket), nil
+}
+
+func (p *LLParser) parseMemberExpression(object ast.Expression) (ast.Expression, error) {
+	period := takeToken[*ast.TerminalToken](p)
+
+	member, err := p.expectToken(ast.IdentifierName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewMemberExpression(object, period, member.(*ast.Identifier)), nil
+}
+
+func (p *LLParser) parseCallExpression(callee ast.Expression) (ast.Expression, error) {
+	defer p.enclosed()()
+	lParen := takeToken[*ast.TerminalToken](p)
+
+	args, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+
+	rParen, err := p.expectTerminalToken(ast.RightParen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewCallExpression(callee, lParen, args, rParen), nil
+}
+
+func (p *LLParser) parsePrefixExpression() (ast.Expression, error) {
+	operator := takeToken[*ast.TerminalToken](p)
+
+	operand, err := p.parseExpression(PrecedencePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewPrefixExpression(operator, operand), nil
+}
+
+// parsePointerArithmeticExpression parses 'p +>> n' and 'p -<< n', and the unit type of offset if it follows on
+// the same line, like 'p +>> n uint32'.
+func (p *LLParser) parsePointerArithmeticExpression(pointer ast.Expression) (ast.Expression, error) {
+	operator := takeToken[*ast.TerminalToken](p)
+
+	offset, err := p.parseExpression(GetPrecedence(operator))
+	if err != nil {
+		return nil, err
+	}
+
+	var unit *ast.SimpleType
+	if isToken(p.currentToken(), ast.IdentifierName) && p.onSameLine() {
+		unit = ast.NewSimpleType(nil, takeToken[*ast.Identifier](p))
+	}
+
+	return ast.NewPointerArithmeticExpression(pointer, operator, offset, unit), nil
+}
+
+func (p *LLParser) parseParenthesizedExpression() (ast.Expression, error) {
+	defer p.enclosed()()
+	lParen := takeToken[*ast.TerminalToken](p)
+
+	expr, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	rParen, err := p.expectTerminalToken(ast.RightParen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewParenthesizedExpression(lParen, expr, rParen), nil
+}
+
 func (p *LLParser) parseInfixExpression(left ast.Expression, precedence Precedence) (ast.Expression, error) {
 	operator := takeToken[*ast.TerminalToken](p)
 	currentPrecedence := GetPrecedence(operator)
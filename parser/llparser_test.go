@@ -158,6 +158,42 @@ func TestLLParserFunctionWithArguments(t *testing.T) {
 	).Run(t)
 }
 
+func TestLLParserReturnTypesWithoutParentheses(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"fun foo() int32 {\n    return 1\n}",
+			strings.Join([]string{
+				"test.mc:1:11: error: unexpected token identifier, expect '(, {'",
+				"    1 | fun foo() int32 {",
+				"      |           ^^^^^",
+			}, "\n"),
+		},
+		{
+			"fun (p *Point) Next() *Point {\n    return p\n}",
+			strings.Join([]string{
+				"test.mc:1:23: error: unexpected token *, expect '(, {'",
+				"    1 | fun (p *Point) Next() *Point {",
+				"      |                       ^",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		parser := NewLLParserFromCode(c.code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
 func TestLLParserReturnWithExpressionList(t *testing.T) {
 	newCorrectCodeTestCase(
 		strings.Join([]string{
@@ -300,3 +336,1298 @@ func TestLLParserReturnWithExpressionArithmetic3(t *testing.T) {
 		),
 	).Run(t)
 }
+
+func TestLLParserReturnWithStringLiteral(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun greeting() (string) {",
+			`    return "hello, \"world\"\n"`,
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"greeting",
+				nil,
+				ast.ASTBuildTypeList(
+					ast.ASTBuildTypeListItemWithoutComma("string"),
+				),
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(
+						ast.ASTBuildExpressionList(
+							ast.ASTBuildExpressionListItemWithoutComma(
+								ast.ASTBuildValue("hello, \"world\"\n"),
+							),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserAttachComments(t *testing.T) {
+	code := strings.Join([]string{
+		`// main function`,
+		`fun main() {`,
+		`    // return nothing`,
+		`    return // trailing`,
+		`}`,
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	doc, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("parse failed: %s", err)
+	}
+
+	fn := doc.Declarations[0].(*ast.FunctionDeclaration)
+	if got := fn.LeadingComments(); len(got) != 1 || got[0].Text() != "main function" {
+		t.Errorf("wrong leading comments of function: %v", got)
+	}
+
+	stmt := fn.Statements[0]
+	if got := stmt.LeadingComments(); len(got) != 1 || got[0].Text() != "return nothing" {
+		t.Errorf("wrong leading comments of statement: %v", got)
+	}
+
+	if got := stmt.TrailingComments(); len(got) != 1 || got[0].Text() != "trailing" {
+		t.Errorf("wrong trailing comments of statement: %v", got)
+	}
+}
+
+func TestLLParserExpressionPrecedence(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun calc(a int, b int) (int) {",
+			"    return a + b * 2 == -a << 1 or not (a < b) and a | b ^ ~b & *a",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"calc",
+				ast.ASTBuildArgumentList(
+					ast.ASTBuildArgumentWithComma("a", "int"),
+					ast.ASTBuildArgumentWithoutComma("b", "int"),
+				),
+				ast.ASTBuildTypeList(
+					ast.ASTBuildTypeListItemWithoutComma("int"),
+				),
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(
+						ast.ASTBuildExpressionList(
+							ast.ASTBuildExpressionListItemWithoutComma(
+								ast.ASTBuildInfixExpression(
+									ast.ASTBuildInfixExpression(
+										ast.ASTBuildInfixExpression(
+											ast.ASTBuildIdentifier("a"),
+											ast.Plus,
+											ast.ASTBuildInfixExpression(
+												ast.ASTBuildIdentifier("b"),
+												ast.Asterisk,
+												ast.ASTBuildValue(2),
+											),
+										),
+										ast.Equal,
+										ast.ASTBuildInfixExpression(
+											ast.ASTBuildPrefixExpression(ast.Sub, ast.ASTBuildIdentifier("a")),
+											ast.ShiftLeft,
+											ast.ASTBuildValue(1),
+										),
+									),
+									ast.Or,
+									ast.ASTBuildInfixExpression(
+										ast.ASTBuildPrefixExpression(ast.Not,
+											ast.ASTBuildParenthesizedExpression(
+												ast.ASTBuildInfixExpression(
+													ast.ASTBuildIdentifier("a"),
+													ast.LessThan,
+													ast.ASTBuildIdentifier("b"),
+												),
+											),
+										),
+										ast.And,
+										ast.ASTBuildInfixExpression(
+											ast.ASTBuildIdentifier("a"),
+											ast.VerticalBar,
+											ast.ASTBuildInfixExpression(
+												ast.ASTBuildIdentifier("b"),
+												ast.Caret,
+												ast.ASTBuildInfixExpression(
+													ast.ASTBuildPrefixExpression(ast.Tilde, ast.ASTBuildIdentifier("b")),
+													ast.Ampersand,
+													ast.ASTBuildPrefixExpression(ast.Asterisk, ast.ASTBuildIdentifier("a")),
+												),
+											),
+										),
+									),
+								),
+							),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserReturnLiterals(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun values() (bool, bool, float, ptr) {",
+			"    return true, false, 3.5, null",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"values",
+				nil,
+				ast.ASTBuildTypeList(
+					ast.ASTBuildTypeListItemWithComma("bool"),
+					ast.ASTBuildTypeListItemWithComma("bool"),
+					ast.ASTBuildTypeListItemWithComma("float"),
+					ast.ASTBuildTypeListItemWithoutComma("ptr"),
+				),
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(
+						ast.ASTBuildExpressionList(
+							ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(true)),
+							ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(false)),
+							ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(3.5)),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(nil)),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserUnclosedParenthesis(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() (int) {",
+		"    return (1 + 2",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:1: error: unexpected token }, expect ')'",
+		"    3 | }",
+		"      | ^",
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	_, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect error, got nil")
+	}
+
+	if err.Error() != expected {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestLLParserCallExpression(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    print(add(1, 2) * 3, f())",
+			"    return",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildExpressionStatement(
+						ast.ASTBuildCallExpression(
+							ast.ASTBuildIdentifier("print"),
+							ast.ASTBuildExpressionListItemWithComma(
+								ast.ASTBuildInfixExpression(
+									ast.ASTBuildCallExpression(
+										ast.ASTBuildIdentifier("add"),
+										ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(1)),
+										ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(2)),
+									),
+									ast.Asterisk,
+									ast.ASTBuildValue(3),
+								),
+							),
+							ast.ASTBuildExpressionListItemWithoutComma(
+								ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("f")),
+							),
+						),
+					),
+					ast.ASTBuildReturnStatement(ast.ASTBuildExpressionList()),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserStatementsSeparatedByNewline(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    return",
+			"    f(a)",
+			"    (g)(b)",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(ast.ASTBuildExpressionList()),
+					ast.ASTBuildExpressionStatement(
+						ast.ASTBuildCallExpression(
+							ast.ASTBuildIdentifier("f"),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("a")),
+						),
+					),
+					ast.ASTBuildExpressionStatement(
+						ast.ASTBuildCallExpression(
+							ast.ASTBuildParenthesizedExpression(ast.ASTBuildIdentifier("g")),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("b")),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserExpressionStatementNotCall(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    a + 1",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:5: error: expression is evaluated but not used",
+		"    2 |     a + 1",
+		"      |     ^ ^ ^",
+		"      |     only function call can be a statement",
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	_, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect error, got nil")
+	}
+
+	if err.Error() != expected {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestLLParserVariableDeclarations(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    var a int32 = 5",
+			"    const c *int32 = a + 1;",
+			"    var x auto = c",
+			"    var y = 1.5",
+			"    var z uint8",
+			"    b, _ := a, f()",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildVariableDeclaration(ast.Var, "a", ast.ASTBuildSimpleType("int32"), ast.ASTBuildValue(5)),
+					ast.ASTBuildVariableDeclaration(ast.Const, "c", ast.ASTBuildSimpleType("*int32"),
+						ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.Plus, ast.ASTBuildValue(1)),
+					),
+					ast.ASTBuildVariableDeclaration(ast.Var, "x", ast.ASTBuildAutoType(), ast.ASTBuildIdentifier("c")),
+					ast.ASTBuildVariableDeclaration(ast.Var, "y", nil, ast.ASTBuildValue(1.5)),
+					ast.ASTBuildVariableDeclaration(ast.Var, "z", ast.ASTBuildSimpleType("uint8"), nil),
+					ast.ASTBuildInferenceDeclaration([]string{"b", "_"},
+						ast.ASTBuildIdentifier("a"),
+						ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("f")),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserInferenceDeclarationErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"    a, f() := 1, 2",
+			strings.Join([]string{
+				"test.mc:2:8: error: non-name on left side of ':='",
+				"    2 |     a, f() := 1, 2",
+				"      |        ^^^",
+				"      |        expect an identifier",
+			}, "\n"),
+		},
+		{
+			"    a, b",
+			strings.Join([]string{
+				"test.mc:2:5: error: expression list is not a statement",
+				"    2 |     a, b",
+				"      |     ^^ ^",
+				"      |     expect '=' or ':=' after names",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join([]string{"fun main() {", c.code, "}"}, "\n")
+		parser := NewLLParserFromCode(code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
+func TestLLParserAssignmentStatements(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    a = b + 1",
+			"    x += 2",
+			"    *p = v",
+			"    s.f[i] <<= 1",
+			"    a, b = b, a",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildAssignmentStatement(ast.ASTBuildIdentifier("a"), ast.Assign,
+						ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("b"), ast.Plus, ast.ASTBuildValue(1)),
+					),
+					ast.ASTBuildAssignmentStatement(ast.ASTBuildIdentifier("x"), ast.AddAssign, ast.ASTBuildValue(2)),
+					ast.ASTBuildAssignmentStatement(
+						ast.ASTBuildPrefixExpression(ast.Asterisk, ast.ASTBuildIdentifier("p")),
+						ast.Assign,
+						ast.ASTBuildIdentifier("v"),
+					),
+					ast.ASTBuildAssignmentStatement(
+						ast.ASTBuildIndexExpression(
+							ast.ASTBuildMemberExpression(ast.ASTBuildIdentifier("s"), "f"),
+							ast.ASTBuildIdentifier("i"),
+						),
+						ast.ShiftLeftAssign,
+						ast.ASTBuildValue(1),
+					),
+					ast.NewAssignmentStatement(
+						ast.ASTBuildExpressionList(
+							ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildIdentifier("a")),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("b")),
+						),
+						ast.ASTBuildSymbol(ast.Assign),
+						ast.ASTBuildExpressionList(
+							ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildIdentifier("b")),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("a")),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserAssignmentErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"    f() = 1",
+			strings.Join([]string{
+				"test.mc:2:5: error: cannot assign to expression",
+				"    2 |     f() = 1",
+				"      |     ^^^",
+				"      |     expect a variable, dereference, member or element",
+			}, "\n"),
+		},
+		{
+			"    a, b += 1, 2",
+			strings.Join([]string{
+				"test.mc:2:10: error: compound assignment '+=' requires a single target",
+				"    2 |     a, b += 1, 2",
+				"      |          ^^",
+				"      |          use '=' for multiple targets",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join([]string{"fun main() {", c.code, "}"}, "\n")
+		parser := NewLLParserFromCode(code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
+func TestLLParserIfStatements(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    if (a > 0) {",
+			"        f()",
+			"    }",
+			"    if a == 0 {",
+			"        return",
+			"    } elif a == 1 {",
+			"    } else {",
+			"        if b {",
+			"            g()",
+			"        }",
+			"    }",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildIfStatement(
+						[]*ast.ConditionalBranch{
+							ast.ASTBuildConditionalBranch(ast.If,
+								ast.ASTBuildParenthesizedExpression(
+									ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.GreaterThan, ast.ASTBuildValue(0)),
+								),
+								ast.ASTBuildExpressionStatement(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("f"))),
+							),
+						},
+						nil,
+					),
+					ast.ASTBuildIfStatement(
+						[]*ast.ConditionalBranch{
+							ast.ASTBuildConditionalBranch(ast.If,
+								ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.Equal, ast.ASTBuildValue(0)),
+								ast.ASTBuildReturnStatement(ast.NewExpressionList()),
+							),
+							ast.ASTBuildConditionalBranch(ast.Elif,
+								ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.Equal, ast.ASTBuildValue(1)),
+							),
+						},
+						ast.ASTBuildBlockStatement(
+							ast.ASTBuildIfStatement(
+								[]*ast.ConditionalBranch{
+									ast.ASTBuildConditionalBranch(ast.If,
+										ast.ASTBuildIdentifier("b"),
+										ast.ASTBuildExpressionStatement(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("g"))),
+									),
+								},
+								nil,
+							),
+						),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserIfStatementErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"    if {\n    }",
+			strings.Join([]string{
+				"test.mc:2:7: error: missing condition in 'if' statement",
+				"    2 |     if {",
+				"      |       ^",
+				"      |       condition required",
+			}, "\n"),
+		},
+		{
+			"    if a > 0 {\n        f()",
+			strings.Join([]string{
+				"test.mc:4:2: error: unexpected end of input, expect '}' to close function body",
+				"    4 | }<EOF>",
+				"      |  ^^^^^",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join([]string{"fun main() {", c.code, "}"}, "\n")
+		parser := NewLLParserFromCode(code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
+func TestLLParserLoopStatements(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    while (a > 0) {",
+			"        a--",
+			"        continue",
+			"    }",
+			"    do {",
+			"        break",
+			"    } while a < 10",
+			"    for (i := 0; i < 10; i++) {",
+			"        f(i)",
+			"    }",
+			"    for (;;) {",
+			"    }",
+			"    foreach (x in arr) {",
+			"        s += x",
+			"    }",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction(
+				"main",
+				nil,
+				nil,
+				[]ast.Statement{
+					ast.ASTBuildWhileStatement(
+						ast.ASTBuildParenthesizedExpression(
+							ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.GreaterThan, ast.ASTBuildValue(0)),
+						),
+						ast.ASTBuildIncrementStatement(ast.ASTBuildIdentifier("a"), ast.Decrement),
+						ast.ASTBuildLoopControlStatement(ast.Continue),
+					),
+					ast.ASTBuildDoWhileStatement(
+						ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("a"), ast.LessThan, ast.ASTBuildValue(10)),
+						ast.ASTBuildLoopControlStatement(ast.Break),
+					),
+					ast.ASTBuildForStatement(
+						ast.ASTBuildInferenceDeclaration([]string{"i"}, ast.ASTBuildValue(0)),
+						ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("i"), ast.LessThan, ast.ASTBuildValue(10)),
+						ast.ASTBuildIncrementStatement(ast.ASTBuildIdentifier("i"), ast.Increment),
+						ast.ASTBuildExpressionStatement(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("f"),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("i")),
+						)),
+					),
+					ast.ASTBuildForStatement(nil, nil, nil),
+					ast.ASTBuildForeachStatement("x", ast.ASTBuildIdentifier("arr"),
+						ast.ASTBuildAssignmentStatement(ast.ASTBuildIdentifier("s"), ast.AddAssign, ast.ASTBuildIdentifier("x")),
+					),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserLoopStatementErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"    for (i := 0, i < 10) {\n    }",
+			strings.Join([]string{
+				"test.mc:2:24: error: unexpected token ), expect ';'",
+				"    2 |     for (i := 0, i < 10) {",
+				"      |                        ^",
+			}, "\n"),
+		},
+		{
+			"    foreach (x of arr) {\n    }",
+			strings.Join([]string{
+				"test.mc:2:16: error: unexpected token identifier, expect 'in'",
+				"    2 |     foreach (x of arr) {",
+				"      |                ^^",
+			}, "\n"),
+		},
+		{
+			"    f()++",
+			strings.Join([]string{
+				"test.mc:2:5: error: cannot apply '++' to expression",
+				"    2 |     f()++",
+				"      |     ^^^",
+				"      |     expect a variable, dereference, member or element",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		code := strings.Join([]string{"fun main() {", c.code, "}"}, "\n")
+		parser := NewLLParserFromCode(code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
+func TestLLParserTypeDeclarations(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"struct Point { x uint32; y uint32 }",
+			"",
+			"type Node struct {",
+			"    value int // payload",
+			"    next  *Node",
+			"}",
+			"",
+			"type Celsius float64",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.NewTypeDeclaration(ast.ASTBuildKeyword(ast.Structure), ast.ASTBuildIdentifier("Point"),
+				ast.ASTBuildStructType(
+					ast.ASTBuildField("x", "uint32"),
+					ast.ASTBuildField("y", "uint32"),
+				),
+			),
+			ast.ASTBuildTypeDeclaration("Node",
+				ast.ASTBuildStructType(
+					ast.ASTBuildField("value", "int"),
+					ast.ASTBuildField("next", "*Node"),
+				),
+			),
+			ast.ASTBuildTypeDeclaration("Celsius", ast.ASTBuildSimpleType("float64")),
+		),
+	).Run(t)
+}
+
+func TestLLParserMethodDeclarations(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun (p *Point) Distance() (float32) {",
+			"    return p.x",
+			"}",
+			"",
+			"fun (p Point) Scale(n int) {",
+			"}",
+			"",
+			"fun Point.New(x int, y int) (*Point) {",
+			"    return null",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildMethod("p", "*Point", "Distance", nil,
+				ast.ASTBuildTypeList(ast.ASTBuildTypeListItemWithoutComma("float32")),
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(ast.ASTBuildExpressionList(
+						ast.ASTBuildExpressionListItemWithoutComma(
+							ast.ASTBuildMemberExpression(ast.ASTBuildIdentifier("p"), "x"),
+						),
+					)),
+				},
+			),
+			ast.ASTBuildMethod("p", "Point", "Scale",
+				ast.ASTBuildArgumentList(ast.ASTBuildArgumentWithoutComma("n", "int")),
+				nil, nil,
+			),
+			ast.ASTBuildTypeMethod("Point", "New",
+				ast.ASTBuildArgumentList(
+					ast.ASTBuildArgumentWithComma("x", "int"),
+					ast.ASTBuildArgumentWithoutComma("y", "int"),
+				),
+				ast.ASTBuildTypeList(ast.ASTBuildTypeListItemWithoutComma("*Point")),
+				[]ast.Statement{
+					ast.ASTBuildReturnStatement(ast.ASTBuildExpressionList(
+						ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(nil)),
+					)),
+				},
+			),
+		),
+	).Run(t)
+}
+
+func TestLLParserMethodDeclarationErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			"fun (a *Point, b int) Distance() {\n}",
+			strings.Join([]string{
+				"test.mc:1:14: error: method has multiple receivers",
+				"    1 | fun (a *Point, b int) Distance() {",
+				"      |              ^",
+				"      |              SHALL be one receiver",
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		parser := NewLLParserFromCode(c.code, "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error for %q, got nil", c.code)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", c.expected, err.Error())
+		}
+	}
+}
+
+func TestLLParserGlobalDeclarations(t *testing.T) {
+	globals := func() *ast.ArgumentList {
+		return ast.ASTBuildArgumentList(
+			ast.ASTBuildArgumentWithComma("gbr", "int32"),
+			ast.ASTBuildArgumentWithoutComma("gbi", "int32"),
+		)
+	}
+
+	body := []ast.Statement{
+		ast.ASTBuildReturnStatement(ast.ASTBuildExpressionList(
+			ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildIdentifier("gbr")),
+		)),
+	}
+
+	ret := ast.ASTBuildTypeList(ast.ASTBuildTypeListItemWithoutComma("int32"))
+
+	style2 := ast.ASTBuildFunction("calc2", nil, ret, body)
+	style2.Globals = globals()
+
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"global var gbr int32 = 1",
+			"global var gbi int32",
+			"",
+			"fun calc1() with(gbr int32, gbi int32) (int32) {",
+			"    return gbr",
+			"}",
+			"",
+			"fun [gbr int32, gbi int32]calc2() (int32) {",
+			"    return gbr",
+			"}",
+			"",
+			"fun calc3() (int32) {",
+			"    global var gbr int32",
+			"    return gbr",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildGlobalDeclaration("gbr", ast.ASTBuildSimpleType("int32"), ast.ASTBuildValue(1)),
+			ast.ASTBuildGlobalDeclaration("gbi", ast.ASTBuildSimpleType("int32"), nil),
+			ast.ASTBuildFunctionWithGlobals("calc1", globals(), nil, ret, body),
+			style2,
+			ast.ASTBuildFunction("calc3", nil, ret, []ast.Statement{
+				ast.ASTBuildGlobalDeclaration("gbr", ast.ASTBuildSimpleType("int32"), nil),
+				body[0],
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserModuleDeclarations(t *testing.T) {
+	exported := ast.ASTBuildFunction("add", nil, nil, nil)
+	exported.Export = ast.ASTBuildKeyword(ast.Export)
+
+	global := ast.ASTBuildGlobalDeclaration("count", ast.ASTBuildSimpleType("int"), nil)
+	global.Export = ast.ASTBuildKeyword(ast.Export)
+
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"module math",
+			"import io",
+			"",
+			"export global var count int",
+			"",
+			"export fun add() {",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildModuleDeclaration("math"),
+			ast.ASTBuildImportDeclaration("io"),
+			global,
+			exported,
+		),
+	).Run(t)
+}
+
+func TestLLParserExportErrors(t *testing.T) {
+	code := "export type Point int"
+	expected := strings.Join([]string{
+		"test.mc:1:8: error: unexpected token: type, expect a function or global variable to export",
+		"    1 | export type Point int",
+		"      |        ^^^^",
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	_, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect error for %q, got nil", code)
+	}
+
+	if err.Error() != expected {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestLLParserErrorRecovery(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f() {",
+		"    var x int = = 3",
+		"    return 0",
+		"}",
+		"",
+		"struct P {",
+		"    y",
+		"}",
+		"",
+		"fun g() {",
+		"    var y = 1",
+		"}",
+	}, "\n")
+
+	expectedErrors := strings.Join([]string{
+		"test.mc:2:17: error: unexpected token '=' in expression",
+		"    2 |     var x int = = 3",
+		"      |                 ^",
+		"test.mc:8:1: error: unexpected token }, expect type identifier",
+		"    8 | }",
+		"      | ^",
+	}, "\n")
+
+	expectedDoc := ast.ASTBuildDocument(
+		ast.ASTBuildFunction("f", nil, nil, []ast.Statement{
+			ast.ASTBuildErrorNode(),
+			ast.ASTBuildReturnStatement(
+				ast.ASTBuildExpressionList(
+					ast.ASTBuildExpressionListItemWithoutComma(
+						ast.ASTBuildValue(0),
+					),
+				),
+			),
+		}),
+		ast.ASTBuildErrorNode(),
+		ast.ASTBuildFunction("g", nil, nil, []ast.Statement{
+			ast.ASTBuildVariableDeclaration(ast.Var, "y", nil, ast.ASTBuildValue(1)),
+		}),
+	)
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	doc, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect errors, got nil")
+	}
+
+	if err.Error() != expectedErrors {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expectedErrors, err.Error())
+	}
+
+	if doc == nil {
+		t.Fatalf("partial document expected, got nil")
+	}
+
+	if err := doc.EqualTo(nil, expectedDoc); err != nil {
+		t.Fatalf("expected document not equal to actual:\n%s", err)
+	}
+}
+
+func TestLLParserErrorRecoveryUnclosedBlock(t *testing.T) {
+	code := strings.Join([]string{
+		"fun f() {",
+		"    while true {",
+		"        var = 1",
+		"",
+		"fun g() {",
+		"    return",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:3:13: error: unexpected token =, expect 'identifier'",
+		"    3 |         var = 1",
+		"      |             ^",
+		"test.mc:5:1: error: unexpected token 'fun' in statement",
+		"    5 | fun g() {",
+		"      | ^^^",
+		"      | missing '}' to close block",
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	doc, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect errors, got nil")
+	}
+
+	if err.Error() != expected {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+
+	if len(doc.Declarations) != 2 {
+		t.Fatalf("expect 2 declarations, got %d", len(doc.Declarations))
+	}
+
+	if _, ok := doc.Declarations[0].(*ast.ErrorNode); !ok {
+		t.Errorf("expect an error node, got %T", doc.Declarations[0])
+	}
+
+	if _, ok := doc.Declarations[1].(*ast.FunctionDeclaration); !ok {
+		t.Errorf("expect function declaration, got %T", doc.Declarations[1])
+	}
+}
+
+func TestLLParserArrays(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"type Buffer struct {",
+			"    data uint8[16]",
+			"}",
+			"fun main() {",
+			"    var a1 = int[]{1, 2, 3}",
+			"    var a2 int[5] = int[5]{1, 2}",
+			"    a2[1] = a1[0]",
+			"    if a1[0] {",
+			"    }",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildTypeDeclaration("Buffer", ast.ASTBuildStructType(
+				ast.NewFieldDeclaration(ast.ASTBuildIdentifier("data"), ast.ASTBuildArrayType("uint8", 16)),
+			)),
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildVariableDeclaration(ast.Var, "a1", nil,
+					ast.ASTBuildArrayLiteral(ast.ASTBuildArrayType("int", 0),
+						ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(1)),
+						ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(2)),
+						ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(3)),
+					),
+				),
+				ast.ASTBuildVariableDeclaration(ast.Var, "a2", ast.ASTBuildArrayType("int", 5),
+					ast.ASTBuildArrayLiteral(ast.ASTBuildArrayType("int", 5),
+						ast.ASTBuildExpressionListItemWithComma(ast.ASTBuildValue(1)),
+						ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(2)),
+					),
+				),
+				ast.ASTBuildAssignmentStatement(
+					ast.ASTBuildIndexExpression(ast.ASTBuildIdentifier("a2"), ast.ASTBuildValue(1)),
+					ast.Assign,
+					ast.ASTBuildIndexExpression(ast.ASTBuildIdentifier("a1"), ast.ASTBuildValue(0)),
+				),
+				ast.ASTBuildIfStatement(
+					[]*ast.ConditionalBranch{
+						ast.ASTBuildConditionalBranch(ast.If,
+							ast.ASTBuildIndexExpression(ast.ASTBuildIdentifier("a1"), ast.ASTBuildValue(0)),
+						),
+					},
+					nil,
+				),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserLists(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"type Queue struct {",
+			"    items int[]",
+			"}",
+			"fun main() {",
+			"    var l1 int[]",
+			"    l2 := new int[4]{1}",
+			"    foreach (x in l2) {",
+			"    }",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildTypeDeclaration("Queue", ast.ASTBuildStructType(
+				ast.NewFieldDeclaration(ast.ASTBuildIdentifier("items"), ast.ASTBuildListType("int")),
+			)),
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildVariableDeclaration(ast.Var, "l1", ast.ASTBuildListType("int"), nil),
+				ast.ASTBuildInferenceDeclaration(
+					[]string{"l2"},
+					ast.ASTBuildNewExpression(
+						ast.ASTBuildArrayLiteral(ast.ASTBuildArrayType("int", 4),
+							ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(1)),
+						),
+					),
+				),
+				ast.ASTBuildForeachStatement("x", ast.ASTBuildIdentifier("l2")),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserNewExpressionError(t *testing.T) {
+	code := strings.Join([]string{
+		"fun main() {",
+		"    l := new 42",
+		"}",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"test.mc:2:14: error: unexpected token 'integer', expect type after 'new'",
+		"    2 |     l := new 42",
+		"      |              ^^",
+	}, "\n")
+
+	parser := NewLLParserFromCode(code, "test.mc")
+	_, err := parser.Parse()
+	if err == nil {
+		t.Fatalf("expect error, got nil")
+	}
+
+	if err.Error() != expected {
+		t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestLLParserPointerArithmetic(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    p2 := p1 +>> 1",
+			"    p3 := p1 +>> n * 2 uint32",
+			"    p4 := p3 -<< 1 uint32 +>> 2",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildInferenceDeclaration([]string{"p2"},
+					ast.ASTBuildPointerArithmeticExpression(ast.ASTBuildIdentifier("p1"), ast.PointerAdd, ast.ASTBuildValue(1), ""),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"p3"},
+					ast.ASTBuildPointerArithmeticExpression(ast.ASTBuildIdentifier("p1"), ast.PointerAdd,
+						ast.ASTBuildInfixExpression(ast.ASTBuildIdentifier("n"), ast.Asterisk, ast.ASTBuildValue(2)),
+						"uint32",
+					),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"p4"},
+					ast.ASTBuildPointerArithmeticExpression(
+						ast.ASTBuildPointerArithmeticExpression(ast.ASTBuildIdentifier("p3"), ast.PointerSub, ast.ASTBuildValue(1), "uint32"),
+						ast.PointerAdd, ast.ASTBuildValue(2), "",
+					),
+				),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserSizeof(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    var buf uint8[sizeof(Header) * 2]",
+			"    n1 := sizeof(*p) + sizeof(int32[4])",
+			"    n2 := sizeof(buf[0]) + sizeof(int32[])",
+			"    n3 := sizeof(a.b)",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildVariableDeclaration(ast.Var, "buf",
+					ast.NewArrayType(ast.ASTBuildSimpleType("uint8"), ast.ASTBuildSymbol(ast.LeftBracket),
+						ast.ASTBuildInfixExpression(
+							ast.ASTBuildSizeofExpression(ast.ASTBuildSimpleType("Header")),
+							ast.Asterisk,
+							ast.ASTBuildValue(2),
+						),
+						ast.ASTBuildSymbol(ast.RightBracket),
+					),
+					nil,
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"n1"},
+					ast.ASTBuildInfixExpression(
+						ast.ASTBuildSizeofExpression(ast.ASTBuildSimpleType("*p")),
+						ast.Plus,
+						ast.ASTBuildSizeofExpression(ast.ASTBuildArrayType("int32", 4)),
+					),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"n2"},
+					ast.ASTBuildInfixExpression(
+						ast.ASTBuildSizeofExpression(ast.NewArrayType(ast.ASTBuildSimpleType("buf"),
+							ast.ASTBuildSymbol(ast.LeftBracket), ast.ASTBuildValue(0), ast.ASTBuildSymbol(ast.RightBracket))),
+						ast.Plus,
+						ast.ASTBuildSizeofExpression(ast.ASTBuildListType("int32")),
+					),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"n3"},
+					ast.ASTBuildSizeofExpression(ast.ASTBuildMemberExpression(ast.ASTBuildIdentifier("a"), "b")),
+				),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserStructLiteral(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    p := Point{x: 1, y: n}",
+			"    var q Point = Point{}",
+			"    show(Point{",
+			"        x: 2,",
+			"    })",
+			"    if ready {",
+			"        x = 1",
+			"    }",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildInferenceDeclaration([]string{"p"},
+					ast.ASTBuildStructLiteral("Point",
+						ast.ASTBuildFieldValueWithComma("x", ast.ASTBuildValue(1)),
+						ast.ASTBuildFieldValueWithoutComma("y", ast.ASTBuildIdentifier("n")),
+					),
+				),
+				ast.ASTBuildVariableDeclaration(ast.Var, "q", ast.ASTBuildSimpleType("Point"),
+					ast.ASTBuildStructLiteral("Point"),
+				),
+				ast.ASTBuildExpressionStatement(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("show"),
+					ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildStructLiteral("Point",
+						ast.ASTBuildFieldValueWithComma("x", ast.ASTBuildValue(2)),
+					)),
+				)),
+				ast.ASTBuildIfStatement(
+					[]*ast.ConditionalBranch{
+						ast.ASTBuildConditionalBranch(ast.If,
+							ast.ASTBuildIdentifier("ready"),
+							ast.ASTBuildAssignmentStatement(ast.ASTBuildIdentifier("x"), ast.Assign, ast.ASTBuildValue(1)),
+						),
+					},
+					nil,
+				),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserOwnership(t *testing.T) {
+	newCorrectCodeTestCase(
+		strings.Join([]string{
+			"fun main() {",
+			"    var ref p1 *int32 = &n",
+			"    p2 := new int32(5)",
+			"    a := new Node()",
+			"    ref b := a",
+			"    ref bb *Node = a.next",
+			"    d := ref p2",
+			"    pt := new Point{",
+			"        x: 1,",
+			"        y: b.y,",
+			"    }",
+			"    delete p2",
+			"}",
+		}, "\n"),
+		ast.ASTBuildDocument(
+			ast.ASTBuildFunction("main", nil, nil, []ast.Statement{
+				ast.ASTBuildReferenceDeclaration(ast.Var, "p1", ast.ASTBuildSimpleType("*int32"),
+					ast.ASTBuildPrefixExpression(ast.Ampersand, ast.ASTBuildIdentifier("n")),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"p2"},
+					ast.ASTBuildNewExpression(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("int32"),
+						ast.ASTBuildExpressionListItemWithoutComma(ast.ASTBuildValue(5)),
+					)),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"a"},
+					ast.ASTBuildNewExpression(ast.ASTBuildCallExpression(ast.ASTBuildIdentifier("Node"))),
+				),
+				ast.ASTBuildReferenceInference([]string{"b"}, ast.ASTBuildIdentifier("a")),
+				ast.ASTBuildReferenceDeclaration(ast.Ref, "bb", ast.ASTBuildSimpleType("*Node"),
+					ast.ASTBuildMemberExpression(ast.ASTBuildIdentifier("a"), "next"),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"d"},
+					ast.ASTBuildPrefixExpression(ast.Ref, ast.ASTBuildIdentifier("p2")),
+				),
+				ast.ASTBuildInferenceDeclaration([]string{"pt"},
+					ast.ASTBuildNewExpression(ast.ASTBuildStructLiteral("Point",
+						ast.ASTBuildFieldValueWithComma("x", ast.ASTBuildValue(1)),
+						ast.ASTBuildFieldValueWithComma("y", ast.ASTBuildMemberExpression(ast.ASTBuildIdentifier("b"), "y")),
+					)),
+				),
+				ast.ASTBuildDeleteStatement(ast.ASTBuildIdentifier("p2")),
+			}),
+		),
+	).Run(t)
+}
+
+func TestLLParserOwnershipErrors(t *testing.T) {
+	cases := []struct {
+		code     []string
+		expected []string
+	}{
+		{
+			[]string{
+				"fun main() {",
+				"    p := new Point",
+				"}",
+			},
+			[]string{
+				"test.mc:2:19: error: missing value of 'Point'",
+				"    2 |     p := new Point<EOL LF>",
+				"      |                   ^^^^^^^^",
+				"      |                   expect '(', '{' or '[' after type",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    delete get()",
+				"}",
+			},
+			[]string{
+				"test.mc:2:12: error: cannot delete expression",
+				"    2 |     delete get()",
+				"      |            ^^^^^",
+				"      |            expect a variable, dereference, member or element",
+			},
+		},
+		{
+			[]string{
+				"fun main() {",
+				"    ref a, b = x, y",
+				"}",
+			},
+			[]string{
+				"test.mc:2:9: error: unexpected statement after 'ref'",
+				"    2 |     ref a, b = x, y",
+				"      |         ^^ ^ ^ ^^ ^",
+				"      |         expect declaration by ':='",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		parser := NewLLParserFromCode(strings.Join(c.code, "\n"), "test.mc")
+		_, err := parser.Parse()
+		if err == nil {
+			t.Fatalf("expect error, got nil")
+		}
+
+		expected := strings.Join(c.expected, "\n")
+		if err.Error() != expected {
+			t.Errorf("wrong error message, expected:\n%s\ngot:\n%s", expected, err.Error())
+		}
+	}
+}
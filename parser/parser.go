@@ -31,8 +31,52 @@ const (
 )
 
 var precedenceMap = map[ast.TokenType]Precedence{
-	ast.Plus: PrecedenceSum,
-	ast.Sub:  PrecedenceSum,
+	ast.Or:                 PrecedenceLogicalOR,
+	ast.And:                PrecedenceLogicalAND,
+	ast.Equal:              PrecedenceComparisonEquality,
+	ast.NotEqual:           PrecedenceComparisonEquality,
+	ast.LessThan:           PrecedenceComparisonRelational,
+	ast.LessThanOrEqual:    PrecedenceComparisonRelational,
+	ast.GreaterThan:        PrecedenceComparisonRelational,
+	ast.GreaterThanOrEqual: PrecedenceComparisonRelational,
+	ast.Plus:               PrecedenceSum,
+	ast.Sub:                PrecedenceSum,
+	ast.PointerAdd:         PrecedenceSum,
+	ast.PointerSub:         PrecedenceSum,
+	ast.Asterisk:           PrecedenceProduct,
+	ast.Slash:              PrecedenceProduct,
+	ast.Percent:            PrecedenceProduct,
+	ast.VerticalBar:        PrecedenceBitwiseOR,
+	ast.Caret:              PrecedenceBitwiseXOR,
+	ast.Ampersand:          PrecedenceBitwiseAND,
+	ast.ShiftLeft:          PrecedenceShift,
+	ast.ShiftRight:         PrecedenceShift,
+	ast.LeftParen:          PrecedenceCall,
+	ast.LeftBracket:        PrecedenceIndex,
+	ast.Period:             PrecedenceIndex,
+}
+
+var assignmentOperators = []ast.TokenType{
+	ast.Assign,
+	ast.AddAssign,
+	ast.SubAssign,
+	ast.MulAssign,
+	ast.DivAssign,
+	ast.ModAssign,
+	ast.AndAssign,
+	ast.OrAssign,
+	ast.XorAssign,
+	ast.ShiftLeftAssign,
+	ast.ShiftRightAssign,
+}
+
+var prefixOperators = []ast.TokenType{
+	ast.Sub,
+	ast.Tilde,
+	ast.Not,
+	ast.Ampersand,
+	ast.Asterisk,
+	ast.Ref,
 }
 
 func GetPrecedence(node ast.TerminalNode) Precedence {
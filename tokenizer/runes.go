@@ -83,6 +83,13 @@ var validSymbolInitialRunes = []bool{
 	'-':  true,
 	'.':  true,
 	'/':  true,
+	':':  true,
+	';':  true,
+	'<':  true,
+	'=':  true,
+	'>':  true,
+	'?':  true,
+	'@':  true,
 	'[':  true,
 	'\\': true,
 	']':  true,
@@ -3,6 +3,9 @@ package tokenizer
 import (
 	"math"
 	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/flily/magi-c/ast"
 	"github.com/flily/magi-c/context"
@@ -20,6 +23,9 @@ type Tokenizer struct {
 	state         TokenizerState
 	cursor        *context.Cursor
 	Preprocessors map[string]preprocessor.PreprocessorInitializer
+
+	// DanglingComments are comments at the end of file, which are not attached to any token.
+	DanglingComments []*ast.Comment
 }
 
 func NewTokenizerFrom(buffer []byte, filename string) *Tokenizer {
@@ -100,8 +106,15 @@ func (t *Tokenizer) ScanWordToken(i int) ast.TerminalNode {
 	content, ctx := t.scanWord(i)
 
 	tokenType := ast.GetKeywordTokenType(content)
-	if tokenType == ast.Invalid {
+	switch tokenType {
+	case ast.Invalid:
 		return ast.NewIdentifier(ctx)
+
+	case ast.True, ast.False:
+		return ast.NewBooleanLiteral(ctx, tokenType == ast.True)
+
+	case ast.Null:
+		return ast.NewNullLiteral(ctx)
 	}
 
 	return ast.NewTerminalToken(ctx, tokenType)
@@ -350,6 +363,157 @@ func (t *Tokenizer) ScanNumber() (ast.TerminalNode, error) {
 	}
 }
 
+func hexDigitValue(r rune) (int, bool) {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0'), true
+
+	case 'a' <= r && r <= 'f':
+		return int(r-'a') + 10, true
+
+	case 'A' <= r && r <= 'F':
+		return int(r-'A') + 10, true
+	}
+
+	return 0, false
+}
+
+var simpleEscapeMap = map[rune]byte{
+	'a':  '\a',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+	'v':  '\v',
+	'0':  0,
+	'\\': '\\',
+	'\'': '\'',
+	'"':  '"',
+}
+
+// scanEscapeSequence scans an escape sequence starting at offset i, which points to the backslash.
+// It returns the decoded bytes and the length of the escape sequence in runes.
+func (t *Tokenizer) scanEscapeSequence(i int) ([]byte, int, error) {
+	r, eol, _ := t.cursor.Peek(i + 1)
+	if eol {
+		_, ctx := t.cursor.MakeContext(t.cursor.PeekState(i), t.cursor.PeekState(i+2))
+		return nil, 0, ctx.Error("unterminated escape sequence").With("escape sequence requires a character")
+	}
+
+	if b, ok := simpleEscapeMap[r]; ok {
+		return []byte{b}, 2, nil
+	}
+
+	switch r {
+	case 'x':
+		v, n := 0, 0
+		for n < 2 {
+			d, _, _ := t.cursor.Peek(i + 2 + n)
+			h, ok := hexDigitValue(d)
+			if !ok {
+				break
+			}
+
+			v = v*16 + h
+			n++
+		}
+
+		if n != 2 {
+			s, ctx := t.cursor.MakeContext(t.cursor.PeekState(i), t.cursor.PeekState(i+2+n))
+			return nil, 0, ctx.Error("invalid hexadecimal escape sequence '%s'", s).With("expect exactly 2 hexadecimal digits")
+		}
+
+		return []byte{byte(v)}, 4, nil
+
+	case 'u':
+		brace, _, _ := t.cursor.Peek(i + 2)
+		if brace != '{' {
+			s, ctx := t.cursor.MakeContext(t.cursor.PeekState(i), t.cursor.PeekState(i+2))
+			return nil, 0, ctx.Error("invalid unicode escape sequence '%s'", s).With("expect '\\u{...}'")
+		}
+
+		v, n := 0, 0
+		for {
+			d, eol, _ := t.cursor.Peek(i + 3 + n)
+			if eol || d == '}' {
+				break
+			}
+
+			h, ok := hexDigitValue(d)
+			if !ok {
+				_, ctx := t.cursor.MakeContext(t.cursor.PeekState(i+3+n), t.cursor.PeekState(i+4+n))
+				return nil, 0, ctx.Error("invalid character '%c' in unicode escape sequence", d).With("hexadecimal digit expected")
+			}
+
+			v = v*16 + h
+			n++
+			if n > 6 {
+				break
+			}
+		}
+
+		closing, _, _ := t.cursor.Peek(i + 3 + n)
+		s, ctx := t.cursor.MakeContext(t.cursor.PeekState(i), t.cursor.PeekState(i+3+n))
+		if closing != '}' {
+			return nil, 0, ctx.Error("unterminated unicode escape sequence '%s'", s).With("expect 1 to 6 hexadecimal digits and '}'")
+		}
+
+		if n == 0 {
+			return nil, 0, ctx.Error("empty unicode escape sequence '%s}'", s).With("expect 1 to 6 hexadecimal digits")
+		}
+
+		if v > unicode.MaxRune || (0xd800 <= v && v <= 0xdfff) {
+			return nil, 0, ctx.Error("invalid unicode code point U+%04X", v).With("not a valid unicode scalar value")
+		}
+
+		return utf8.AppendRune(nil, rune(v)), 4 + n, nil
+	}
+
+	s, ctx := t.cursor.MakeContext(t.cursor.PeekState(i), t.cursor.PeekState(i+2))
+	return nil, 0, ctx.Error("invalid escape sequence '%s'", s).With("unknown escape sequence")
+}
+
+func (t *Tokenizer) ScanString() (ast.TerminalNode, error) {
+	begin := t.cursor.State()
+	value := make([]byte, 0, 64)
+
+	i := 1 // skip opening quote
+	for {
+		r, eol, _ := t.cursor.Peek(i)
+		if eol {
+			_, quoteCtx := t.cursor.MakeContext(begin, t.cursor.PeekState(1))
+			_, last := t.cursor.MakeContext(t.cursor.PeekState(i-1), t.cursor.PeekState(i))
+			err := last.NextInLineContext().Error("unterminated string literal").With("\"").
+				For(quoteCtx.Note("string literal begins here"))
+			return nil, err
+		}
+
+		if r == '"' {
+			break
+		}
+
+		if r == '\\' {
+			bs, n, err := t.scanEscapeSequence(i)
+			if err != nil {
+				return nil, err
+			}
+
+			value = append(value, bs...)
+			i += n
+			continue
+		}
+
+		value = utf8.AppendRune(value, r)
+		i++
+	}
+
+	finish := t.cursor.PeekState(i + 1)
+	_, ctx := t.cursor.FinishWith(begin, finish)
+	t.cursor.SetState(finish)
+	return ast.NewStringLiteral(ctx, string(value)), nil
+}
+
 func (t *Tokenizer) scanPreprocessorDirective() (ast.TerminalNode, error) {
 	cmd, ctxHash, ctxCmd, err := preprocessor.ScanDirective(t.cursor)
 	if err != nil {
@@ -366,6 +530,110 @@ func (t *Tokenizer) scanPreprocessorDirective() (ast.TerminalNode, error) {
 	return pp.Process(ctxHash, ctxCmd)
 }
 
+func (t *Tokenizer) scanLineComment() *ast.Comment {
+	begin := t.cursor.State()
+	line, _ := t.cursor.CurrentLine()
+	content := []rune(line)[begin.Column+2:]
+
+	finish := t.cursor.State()
+	finish.Column += len(content) + 2
+	_, ctx := t.cursor.FinishWith(begin, finish)
+	t.cursor.SetState(finish)
+
+	text := strings.TrimPrefix(strings.TrimRightFunc(string(content), unicode.IsSpace), " ")
+	return ast.NewComment(ctx, false, []string{text})
+}
+
+func trimBlockCommentLines(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for i, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if i > 0 {
+			line = strings.TrimLeftFunc(line, unicode.IsSpace)
+			if strings.HasPrefix(line, "*") {
+				line = line[1:]
+			}
+		}
+
+		result = append(result, strings.TrimPrefix(line, " "))
+	}
+
+	if len(result) > 1 && len(result[0]) <= 0 {
+		result = result[1:]
+	}
+
+	if len(result) > 1 && len(result[len(result)-1]) <= 0 {
+		result = result[:len(result)-1]
+	}
+
+	return result
+}
+
+func (t *Tokenizer) scanBlockComment() (*ast.Comment, error) {
+	begin := t.cursor.State()
+	_, openCtx := t.cursor.MakeContext(begin, t.cursor.PeekState(2))
+
+	ctxs := make([]*context.Context, 0, 4)
+	lines := make([]string, 0, 4)
+	start, column := begin.Column, begin.Column+2
+	for {
+		content := []rune(t.cursor.File.Line(t.cursor.Line).String())
+		end := -1
+		for i := column; i+1 < len(content); i++ {
+			if content[i] == '*' && content[i+1] == '/' {
+				end = i
+				break
+			}
+		}
+
+		if end >= 0 {
+			lines = append(lines, string(content[column:end]))
+			finish := context.NewCursorState(t.cursor.Line, end+2)
+			_, ctx := t.cursor.MakeContext(context.NewCursorState(t.cursor.Line, start), finish)
+			ctxs = append(ctxs, ctx)
+			t.cursor.SetState(finish)
+			break
+		}
+
+		lines = append(lines, string(content[column:]))
+		finish := context.NewCursorState(t.cursor.Line, len(content))
+		_, ctx := t.cursor.MakeContext(context.NewCursorState(t.cursor.Line, start), finish)
+		ctxs = append(ctxs, ctx)
+
+		if eof := t.cursor.NextLine(); eof {
+			err := t.EOFContext().Error("unterminated block comment").With("*/").
+				For(openCtx.Note("block comment begins here"))
+			return nil, err
+		}
+
+		start, column = 0, 0
+	}
+
+	return ast.NewComment(context.Join(ctxs...), true, trimBlockCommentLines(lines)), nil
+}
+
+// SkipTrivia skips whitespaces and comments before next token, and returns the comments.
+func (t *Tokenizer) SkipTrivia() ([]*ast.Comment, error) {
+	var comments []*ast.Comment
+	for {
+		t.SkipWhitespace()
+		if t.cursor.PeekString(ast.SCommentStart) != nil {
+			comments = append(comments, t.scanLineComment())
+
+		} else if t.cursor.PeekString("/*") != nil {
+			comment, err := t.scanBlockComment()
+			if err != nil {
+				return nil, err
+			}
+
+			comments = append(comments, comment)
+
+		} else {
+			return comments, nil
+		}
+	}
+}
+
 func (t *Tokenizer) ScanToken() (ast.TerminalNode, error) {
 	r, _, eof := t.cursor.Rune()
 	if eof {
@@ -373,7 +641,11 @@ func (t *Tokenizer) ScanToken() (ast.TerminalNode, error) {
 	}
 
 	if IsValidNumberInitialRune(r) {
-		return t.ScanNumber()
+		// a period is member access unless followed by a digit, like '.5'
+		next, _, _ := t.cursor.Peek(1)
+		if r != '.' || ('0' <= next && next <= '9') {
+			return t.ScanNumber()
+		}
 	}
 
 	if IsValidIdentifierInitialRune(r) {
@@ -384,6 +656,10 @@ func (t *Tokenizer) ScanToken() (ast.TerminalNode, error) {
 		return t.scanPreprocessorDirective()
 	}
 
+	if r == '"' {
+		return t.ScanString()
+	}
+
 	if IsValidSymbolRune(r) {
 		return t.ScanSymbol()
 	}
@@ -392,16 +668,57 @@ func (t *Tokenizer) ScanToken() (ast.TerminalNode, error) {
 	return nil, ctx.Error("no token found")
 }
 
+func commentLine(comment *ast.Comment) int {
+	_, line, _ := comment.Context().Position()
+	return line
+}
+
+func tokenLastLine(token ast.TerminalNode) int {
+	line, _ := token.Context().Last()
+	return line
+}
+
+// attachComments attaches comments before the next token. Comments starting in the same line of previous token
+// are trailing comments of previous token, except comments after a left brace, which are regarded as leading
+// comments of the first token in the block.
+func attachComments(tokens []ast.TerminalNode, comments []*ast.Comment, next ast.TerminalNode) []*ast.Comment {
+	if len(tokens) > 0 {
+		prev := tokens[len(tokens)-1]
+		if prev.Type() != ast.LeftBrace {
+			last := tokenLastLine(prev)
+			i := 0
+			for i < len(comments) && commentLine(comments[i]) == last {
+				i++
+			}
+
+			prev.AddTrailingComments(comments[:i]...)
+			comments = comments[i:]
+		}
+	}
+
+	if next == nil {
+		return comments
+	}
+
+	next.AddLeadingComments(comments...)
+	return nil
+}
+
 func (t *Tokenizer) ScanAll() ([]ast.TerminalNode, error) {
 	tokens := make([]ast.TerminalNode, 0, 1000)
 
 	for {
-		t.SkipWhitespace()
+		comments, err := t.SkipTrivia()
+		if err != nil {
+			return nil, err
+		}
+
 		token, err := t.ScanToken()
 		if err != nil {
 			return nil, err
 		}
 
+		t.DanglingComments = attachComments(tokens, comments, token)
 		if token == nil {
 			break
 		}
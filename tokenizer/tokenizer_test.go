@@ -1039,3 +1039,295 @@ func TestTokenizerScanSimplestProgram(t *testing.T) {
 		t.Fatalf("expected 8 nodes, got %d", len(nodes))
 	}
 }
+
+func TestTokenizerScanTokenString(t *testing.T) {
+	code := strings.Join([]string{
+		`  "hello, world\n" "tab\there" "q\"b\\s" "\x41\u{4e2d}"`,
+	}, "\n")
+
+	tokenizer := NewTokenizerFromString(code, "test.txt")
+
+	expectedValues := []string{
+		"hello, world\n",
+		"tab\there",
+		"q\"b\\s",
+		"A中",
+	}
+
+	for i, expected := range expectedValues {
+		tokenizer.SkipWhitespace()
+		tok, err := tokenizer.ScanToken()
+		if err != nil {
+			t.Fatalf("unexpected error:\n%v", err)
+		}
+
+		str, ok := tok.(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("[%d] expected StringLiteral, got %T", i, tok)
+		}
+
+		if str.Value != expected {
+			t.Errorf("[%d] expected string value %q, got %q", i, expected, str.Value)
+		}
+	}
+
+	tokenizer = NewTokenizerFromString(code, "test.txt")
+	tokenizer.SkipWhitespace()
+	tok, err := tokenizer.ScanToken()
+	if err != nil {
+		t.Fatalf("unexpected error:\n%v", err)
+	}
+
+	exp := strings.Join([]string{
+		`    1 |   "hello, world\n" "tab\there" "q\"b\\s" "\x41\u{4e2d}"`,
+		"      |   ^^^^^^^^^^^^^^^^",
+		"      |   here",
+	}, "\n")
+	checkTerminalNode(t, tok, ast.String, exp)
+}
+
+func TestTokenizerScanTokenStringEmpty(t *testing.T) {
+	tokenizer := NewTokenizerFromString(`""`, "test.txt")
+	tok, err := tokenizer.ScanToken()
+	if err != nil {
+		t.Fatalf("unexpected error:\n%v", err)
+	}
+
+	str, ok := tok.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("expected StringLiteral, got %T", tok)
+	}
+
+	if str.Value != "" {
+		t.Errorf("expected empty string, got %q", str.Value)
+	}
+}
+
+func TestTokenizerScanTokenStringErrors(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{
+			`a + "hello`,
+			strings.Join([]string{
+				`test.txt:1:11: error: unterminated string literal`,
+				`    1 | a + "hello<EOF>`,
+				`      |           ^^^^^`,
+				`      |           "`,
+				`test.txt:1:5: note: string literal begins here`,
+				`    1 | a + "hello`,
+				`      |     ^`,
+			}, "\n"),
+		},
+		{
+			strings.Join([]string{
+				`var s = "abc`,
+				`x := 1`,
+			}, "\n"),
+			strings.Join([]string{
+				`test.txt:1:13: error: unterminated string literal`,
+				`    1 | var s = "abc<EOL LF>`,
+				`      |             ^^^^^^^^`,
+				`      |             "`,
+				`test.txt:1:9: note: string literal begins here`,
+				`    1 | var s = "abc`,
+				`      |         ^`,
+			}, "\n"),
+		},
+		{
+			"\"abc\\\nx",
+			strings.Join([]string{
+				`test.txt:1:5: error: unterminated escape sequence`,
+				`    1 | "abc\<EOL LF>`,
+				`      |     ^^^^^^^^^`,
+				`      |     escape sequence requires a character`,
+			}, "\n"),
+		},
+		{
+			`"bad \q escape"`,
+			strings.Join([]string{
+				`test.txt:1:6: error: invalid escape sequence '\q'`,
+				`    1 | "bad \q escape"`,
+				`      |      ^^`,
+				`      |      unknown escape sequence`,
+			}, "\n"),
+		},
+		{
+			`"\x4g"`,
+			strings.Join([]string{
+				`test.txt:1:2: error: invalid hexadecimal escape sequence '\x4'`,
+				`    1 | "\x4g"`,
+				`      |  ^^^`,
+				`      |  expect exactly 2 hexadecimal digits`,
+			}, "\n"),
+		},
+		{
+			`"\u41"`,
+			strings.Join([]string{
+				`test.txt:1:2: error: invalid unicode escape sequence '\u'`,
+				`    1 | "\u41"`,
+				`      |  ^^`,
+				`      |  expect '\u{...}'`,
+			}, "\n"),
+		},
+		{
+			`"\u{41"`,
+			strings.Join([]string{
+				`test.txt:1:7: error: invalid character '"' in unicode escape sequence`,
+				`    1 | "\u{41"`,
+				`      |       ^`,
+				`      |       hexadecimal digit expected`,
+			}, "\n"),
+		},
+		{
+			`"\u{d800}"`,
+			strings.Join([]string{
+				`test.txt:1:2: error: invalid unicode code point U+D800`,
+				`    1 | "\u{d800}"`,
+				`      |  ^^^^^^^`,
+				`      |  not a valid unicode scalar value`,
+			}, "\n"),
+		},
+		{
+			`"\u{}"`,
+			strings.Join([]string{
+				`test.txt:1:2: error: empty unicode escape sequence '\u{}'`,
+				`    1 | "\u{}"`,
+				`      |  ^^^`,
+				`      |  expect 1 to 6 hexadecimal digits`,
+			}, "\n"),
+		},
+	}
+
+	for _, c := range cases {
+		tokenizer := NewTokenizerFromString(c.code, "test.txt")
+		_, err := tokenizer.ScanAll()
+		if err == nil {
+			t.Fatalf("expected an error for %s, got nil", c.code)
+		}
+
+		checkError(t, err, c.expected)
+	}
+}
+
+func TestTokenizerScanComments(t *testing.T) {
+	code := strings.Join([]string{
+		`// leading comment`,
+		`fun main() { // after brace`,
+		`    /* block */ return 0 // trailing`,
+		`    /*`,
+		`     * multiple`,
+		`     * lines`,
+		`     */`,
+		`}`,
+		`// the end`,
+	}, "\n")
+
+	tokenizer := NewTokenizerFromString(code, "test.txt")
+	tokens, err := tokenizer.ScanAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(tokens) != 8 {
+		t.Fatalf("expect 8 tokens, got %d", len(tokens))
+	}
+
+	checkComments := func(name string, got []*ast.Comment, expected ...*ast.Comment) {
+		t.Helper()
+		if len(got) != len(expected) {
+			t.Fatalf("%s: expect %d comments, got %d", name, len(expected), len(got))
+		}
+
+		for i, comment := range got {
+			if err := comment.EqualTo(nil, expected[i]); err != nil {
+				t.Errorf("%s: comment %d: %s", name, i, err)
+			}
+		}
+	}
+
+	checkComments("fun leading", tokens[0].LeadingComments(), ast.ASTBuildLineComment("leading comment"))
+	checkComments("{ trailing", tokens[4].TrailingComments())
+	checkComments("return leading", tokens[5].LeadingComments(),
+		ast.ASTBuildLineComment("after brace"),
+		ast.ASTBuildBlockComment("block"),
+	)
+	checkComments("0 trailing", tokens[6].TrailingComments(), ast.ASTBuildLineComment("trailing"))
+	checkComments("} leading", tokens[7].LeadingComments(), ast.ASTBuildBlockComment("multiple", "lines"))
+	checkComments("dangling", tokenizer.DanglingComments, ast.ASTBuildLineComment("the end"))
+}
+
+func TestTokenizerScanCommentOnly(t *testing.T) {
+	tokenizer := NewTokenizerFromString("/* a */ // b", "test.txt")
+	tokens, err := tokenizer.ScanAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(tokens) != 0 {
+		t.Fatalf("expect no token, got %d", len(tokens))
+	}
+
+	if len(tokenizer.DanglingComments) != 2 {
+		t.Fatalf("expect 2 dangling comments, got %d", len(tokenizer.DanglingComments))
+	}
+}
+
+func TestTokenizerScanCommentUnterminated(t *testing.T) {
+	code := strings.Join([]string{
+		`fun main() /* not closed`,
+		`}`,
+	}, "\n")
+
+	expected := strings.Join([]string{
+		`test.txt:2:2: error: unterminated block comment`,
+		`    2 | }<EOF>`,
+		`      |  ^^^^^`,
+		`      |  */`,
+		`test.txt:1:12: note: block comment begins here`,
+		`    1 | fun main() /* not closed`,
+		`      |            ^^`,
+	}, "\n")
+
+	tokenizer := NewTokenizerFromString(code, "test.txt")
+	_, err := tokenizer.ScanAll()
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+
+	checkError(t, err, expected)
+}
+
+func TestTokenizerScanAssignmentOperatorsAndMembers(t *testing.T) {
+	code := "a.b <<= c[.5] >>= d"
+
+	tokenizer := NewTokenizerFromString(code, "test.txt")
+	tokens, err := tokenizer.ScanAll()
+	if err != nil {
+		t.Fatalf("unexpected error:\n%v", err)
+	}
+
+	expectedTypes := []ast.TokenType{
+		ast.IdentifierName,
+		ast.Period,
+		ast.IdentifierName,
+		ast.ShiftLeftAssign,
+		ast.IdentifierName,
+		ast.LeftBracket,
+		ast.Float,
+		ast.RightBracket,
+		ast.ShiftRightAssign,
+		ast.IdentifierName,
+	}
+
+	if len(tokens) != len(expectedTypes) {
+		t.Fatalf("expected %d tokens, got %d", len(expectedTypes), len(tokens))
+	}
+
+	for i, expectedType := range expectedTypes {
+		if tokens[i].Type() != expectedType {
+			t.Errorf("token %d: expected type %s, got %s", i, expectedType, tokens[i].Type())
+		}
+	}
+}